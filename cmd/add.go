@@ -24,14 +24,15 @@ SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/shadowblip/steam-shortcut-manager/pkg/chimera"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/flatpak"
 	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
 	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
-	"github.com/shadowblip/steam-shortcut-manager/pkg/steamgriddb"
 	"github.com/spf13/cobra"
 )
 
@@ -39,14 +40,78 @@ import (
 var addCmd = &cobra.Command{
 	Use:   "add <name> <exe>",
 	Short: "Add a Steam shortcut to your steam library",
-	Args:  cobra.ExactArgs(2),
-	Long:  `Adds a Steam shortcut to your library`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		// The name and executable are discovered for Flatpak apps
+		if id, _ := cmd.Flags().GetString("flatpak"); id != "" {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	Long: `Adds a Steam shortcut to your library. Use "add --flatpak <app-id> [name]" to
+add an installed Flatpak application. Use "--compat-tool" to run a Windows
+executable through Proton or another tool listed by "compat-tools". Use
+"--chimera" to also add the shortcut to Chimera, sharing any downloaded
+artwork with it.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
-		name := args[0]
-		exe := args[1]
+		var name, exe string
+		if len(args) > 0 {
+			name = args[0]
+		}
+		if len(args) > 1 {
+			exe = args[1]
+		}
+		flatpakID, _ := cmd.Flags().GetString("flatpak")
 		var errors error
 
+		// Resolve the compat tool first so we fail before changing anything
+		compatToolName, _ := cmd.Flags().GetString("compat-tool")
+		compatTool, err := resolveCompatTool(compatToolName)
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Generate a new shortcut from the cli flags
+		var newShortcut *shortcut.Shortcut
+		if flatpakID != "" {
+			newShortcut, err = newFlatpakShortcutFromFlags(cmd, flatpakID, name)
+			if err != nil {
+				ExitError(err, format)
+			}
+		} else {
+			newShortcut = newShortcutFromFlags(cmd, name, exe)
+		}
+
+		// Validate the shortcut before writing it
+		if skip, _ := cmd.Flags().GetBool("skip-validation"); !skip {
+			fixed, remaining := shortcut.Fix(newShortcut)
+			for _, problem := range fixed {
+				DebugPrintln("Fixed shortcut problem:", problem)
+			}
+			if len(remaining) > 0 {
+				var problems error
+				for _, problem := range remaining {
+					problems = multierror.Append(problems, fmt.Errorf("%v", problem))
+				}
+				ExitError(problems, format)
+			}
+
+			// A new shortcut gets the app id of its fixed executable
+			newShortcut.Appid = int64(shortcut.CalculateAppID(newShortcut.Exe, newShortcut.AppName))
+		}
+
+		// Check the Chimera shortcuts file so we fail before changing anything
+		var chimeraFile *chimeraShortcutsFile
+		if addToChimera(cmd) {
+			chimeraFile, err = loadChimeraShortcutsFile(cmd, flatpakID)
+			if err != nil {
+				ExitError(err, format)
+			}
+			if chimera.FindShortcut(chimeraFile.shortcuts, newShortcut.AppName) >= 0 {
+				ExitError(fmt.Errorf("%w in %v: %v", chimera.ErrShortcutExists, chimeraFile.path, newShortcut.AppName), format)
+			}
+		}
+
 		// Fetch all users
 		users, err := steam.GetUsers()
 		if err != nil {
@@ -56,6 +121,9 @@ var addCmd = &cobra.Command{
 		// Check to see if we're fetching for just one user
 		onlyForUser := cmd.Flags().Lookup("user").Value.String()
 
+		// Images are downloaded once and linked for every other user
+		var images map[steam.ImageType]string
+
 		// Fetch all shortcuts
 		for _, user := range users {
 			if !steam.HasShortcuts(user) {
@@ -70,45 +138,201 @@ var addCmd = &cobra.Command{
 			if err != nil {
 				ExitError(err, format)
 			}
+			userShortcut := *newShortcut
 
-			// Generate a new shortcut from the cli flags
-			newShortcut := newShortcutFromFlags(cmd, name, exe)
 			// Download images for the user if specified
 			if download, _ := cmd.Flags().GetBool("download-images"); download {
-				// Check that we have an API key
-				apiKey, _ := cmd.Flags().GetString("api-key")
-				if apiKey == "" {
-					ExitError(fmt.Errorf("no API key specified"), format)
+				var downloaded map[steam.ImageType]string
+				if images == nil {
+					downloaded, err = downloadShortcutImages(cmd, user, &userShortcut)
+					images = downloaded
+				} else {
+					downloaded, err = linkShortcutImages(user, &userShortcut, images)
 				}
-				DebugPrintln("Downloading images for shortcut")
-				client := steamgriddb.NewClient(apiKey)
-				downloaded, err := downloadImages(client, user, newShortcut)
 				if err != nil {
 					DebugPrintln("Error downloading images:", err)
 					errors = multierror.Append(errors, err)
 				}
 
 				// Update our shortcut with image paths if needed
-				for imgType, path := range downloaded {
-					switch imgType {
-					case "icon":
-						DebugPrintln("Updating shortcut path")
-						newShortcut.Icon = path
-					}
+				if icon, ok := downloaded[steam.ImageIcon]; ok {
+					DebugPrintln("Updating shortcut icon")
+					userShortcut.Icon = icon
 				}
 			}
 
 			// Write the changes
 			DebugPrintln("Adding shortcut")
-			shortcuts.Add(newShortcut)
+			shortcuts.Add(&userShortcut)
 			err = shortcut.Save(shortcuts, shortcutsPath)
 			if err != nil {
 				ExitError(err, format)
 			}
+
+			// Mirror the tags into Steam library collections
+			err = addToTagCollections(user, &userShortcut)
+			if err != nil {
+				DebugPrintln("Error adding shortcut to collections:", err)
+				errors = multierror.Append(errors, err)
+			}
+
+			// Run the shortcut through the given compat tool
+			if compatTool != "" {
+				DebugPrintln("Setting compat tool to", compatTool)
+				err = steam.SetCompatTool(fmt.Sprintf("%v", userShortcut.Appid), compatTool)
+				if err != nil {
+					ExitError(err, format)
+				}
+			}
+		}
+
+		// Add the matching Chimera shortcut using the downloaded images
+		if chimeraFile != nil {
+			err = chimeraFile.add(newShortcut, flatpakID, images)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+			}
+		}
+
+		// The shortcuts were written, but report anything that went wrong
+		if errors != nil {
+			ExitError(errors, format)
 		}
 	},
 }
 
+// downloadedImageTypes maps the names of images returned by downloadImages to
+// their grid image type.
+var downloadedImageTypes = map[string]steam.ImageType{
+	"gridP": steam.ImagePortrait,
+	"gridL": steam.ImageLandscape,
+	"hero":  steam.ImageHero,
+	"logo":  steam.ImageLogo,
+	"icon":  steam.ImageIcon,
+}
+
+// downloadShortcutImages will download the images for the given shortcut from
+// SteamGridDB into the user's grid directory.
+func downloadShortcutImages(cmd *cobra.Command, user string, sc *shortcut.Shortcut) (map[steam.ImageType]string, error) {
+	// Check that we have an API key
+	apiKey := getAPIKey(cmd.Flags())
+	if apiKey == "" {
+		return nil, newCommandError(ErrAuth, fmt.Errorf("no API key specified (use --api-key or the login command)"))
+	}
+	DebugPrintln("Downloading images for shortcut")
+	client, err := newGridDBClient(cmd.Flags(), apiKey)
+	if err != nil {
+		return nil, err
+	}
+	downloaded, err := downloadImages(client, user, sc, newDownloadOptions(cmd.Flags()))
+
+	images := map[steam.ImageType]string{}
+	for name, path := range downloaded {
+		images[downloadedImageTypes[name]] = path
+	}
+	return images, err
+}
+
+// linkShortcutImages will link the given images that were downloaded for
+// another user into the user's grid directory.
+func linkShortcutImages(user string, sc *shortcut.Shortcut, images map[steam.ImageType]string) (map[steam.ImageType]string, error) {
+	var errors error
+	linked := map[steam.ImageType]string{}
+	appID := fmt.Sprintf("%v", sc.Appid)
+	for kind, src := range images {
+		DebugPrintln("Linking", kind, "image for user", user)
+		dst, err := steam.LinkImage(user, appID, kind, src)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+		linked[kind] = dst
+	}
+	return linked, errors
+}
+
+// chimeraShortcutsFile is a Chimera shortcuts file that "add" writes to
+type chimeraShortcutsFile struct {
+	path      string
+	platform  *chimera.Platform
+	shortcuts []*chimera.Shortcut
+}
+
+// addToChimera will return whether or not "add" should also add a Chimera
+// shortcut.
+func addToChimera(cmd *cobra.Command) bool {
+	add, _ := cmd.Flags().GetBool("chimera")
+	return add || cmd.Flags().Changed("chimera-shortcut")
+}
+
+// loadChimeraShortcutsFile will load the Chimera shortcuts file given with
+// --chimera-shortcut. By default this is the flathub shortcuts file for
+// Flatpak apps and the manual shortcuts file otherwise.
+func loadChimeraShortcutsFile(cmd *cobra.Command, flatpakID string) (*chimeraShortcutsFile, error) {
+	file, _ := cmd.Flags().GetString("chimera-shortcut")
+	if file == "" {
+		platform := "manual"
+		if flatpakID != "" {
+			platform = "flathub"
+		}
+		file = chimera.GetShortcutsFile(platform)
+	}
+	file = chimera.ExpandHome(file)
+	platform, err := chimera.GetShortcutsFilePlatform(file)
+	if err != nil {
+		return nil, err
+	}
+	DebugPrintln("Using Chimera shortcuts file:", file)
+
+	shortcuts := []*chimera.Shortcut{}
+	if _, err := os.Stat(file); err == nil {
+		shortcuts, err = chimera.LoadShortcuts(file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &chimeraShortcutsFile{path: file, platform: platform, shortcuts: shortcuts}, nil
+}
+
+// add will add a Chimera shortcut for the given Steam shortcut and link the
+// given grid images to it.
+func (f *chimeraShortcutsFile) add(sc *shortcut.Shortcut, flatpakID string, images map[steam.ImageType]string) error {
+	var errors error
+	chimeraShortcut := chimera.FromSteamShortcut(sc)
+	id := ""
+	if f.platform.IDField == chimera.FieldFlatpakID {
+		id = flatpakID
+	}
+	for _, imageType := range chimera.ImageTypes {
+		src, ok := images[chimera.SteamImageTypes[imageType]]
+		if !ok {
+			continue
+		}
+		dst, err := chimera.LinkImage(imageType, f.platform, chimeraShortcut, id, src)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+		chimeraShortcut.SetImage(imageType, dst)
+	}
+	if chimeraShortcut.Icon == "" && sc.Icon != "" {
+		chimeraShortcut.Icon = sc.Icon
+	}
+
+	err := os.MkdirAll(filepath.Dir(f.path), 0755)
+	if err != nil {
+		return err
+	}
+	f.shortcuts = append(f.shortcuts, chimeraShortcut)
+	err = chimera.SaveShortcuts(f.path, f.shortcuts)
+	if err != nil {
+		return err
+	}
+
+	return errors
+}
+
 // Creates a new shortcut object from command-line flags
 func newShortcutFromFlags(cmd *cobra.Command, name, exe string) *shortcut.Shortcut {
 	getString := func(name string) string {
@@ -141,42 +365,99 @@ func newShortcutFromFlags(cmd *cobra.Command, name, exe string) *shortcut.Shortc
 	return shortcut
 }
 
+// Creates a new shortcut for an installed Flatpak app from command-line flags.
+// Flags that were explicitly set override the discovered values.
+func newFlatpakShortcutFromFlags(cmd *cobra.Command, id, name string) (*shortcut.Shortcut, error) {
+	app, err := flatpak.GetApp(id)
+	if err != nil {
+		return nil, err
+	}
+	DebugPrintln("Found flatpak in", app.Installation.Name, "installation:", app.Installation.Path)
+	sc, err := app.Shortcut(name)
+	if err != nil {
+		return nil, err
+	}
+
+	getString := func(name string) string {
+		res, _ := cmd.Flags().GetString(name)
+		return res
+	}
+	getBool := func(name string) int {
+		res, _ := cmd.Flags().GetBool(name)
+		return boolToInt(res)
+	}
+	sc.AllowDesktopConfig = getBool("allow-desktop-config")
+	sc.AllowOverlay = getBool("allow-overlay")
+	sc.IsHidden = getBool("is-hidden")
+	sc.OpenVR = getBool("openvr")
+	if cmd.Flags().Changed("launch-options") {
+		sc.LaunchOptions = getString("launch-options")
+	}
+	if cmd.Flags().Changed("shortcut-path") {
+		sc.ShortcutPath = getString("shortcut-path")
+	}
+	if cmd.Flags().Changed("start-dir") {
+		sc.StartDir = getString("start-dir")
+	}
+	if cmd.Flags().Changed("icon") {
+		sc.Icon = getString("icon")
+	}
+	tags, _ := cmd.Flags().GetStringSlice("tags")
+	sc.SetTags(tags)
+
+	return sc, nil
+}
+
 // chimeraAddCmd represents the add command
 var chimeraAddCmd = &cobra.Command{
-	Use:   "add <name> <exe>",
+	Use:   "add <name> [exe]",
 	Short: "Add a Chimera shortcut to your steam library",
-	Args:  cobra.ExactArgs(2),
-	Long:  `Adds a Chimera shortcut to your library`,
+	Args:  cobra.RangeArgs(1, 2),
+	Long: `Adds a Chimera shortcut to your library. Each platform requires its own
+flags, e.g. --flatpak-id for flathub or --rom for emulator platforms (see
+"chimera platforms"). The command can be omitted for platforms that know how
+to launch their games.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
 		name := args[0]
-		exe := args[1]
 
 		// Ensure we have a Chimera install
 		if !chimera.HasChimera() {
-			ExitError(fmt.Errorf("no chimera config found at %v", chimera.ConfigDir), format)
+			ExitError(fmt.Errorf("no chimera config found at %v", chimera.GetConfigDir()), format)
 		}
 
 		// Get the platform flag
-		platform := chimeraCmd.PersistentFlags().Lookup("platform").Value.String()
-		DebugPrintln("Using Chimera platform:", platform)
+		platform := getChimeraPlatform(format)
 
-		// Ensure the Chimera shortcuts file exists
-		err := chimera.EnsureShortcutsFileExists(platform)
+		// Check that we have required params for platform
+		fields := getChimeraFields(cmd)
+		err := platform.ValidateFields(fields)
 		if err != nil {
 			ExitError(err, format)
 		}
-
-		// Check that we have required params for platform
-		switch platform {
-		case "flathub":
-			if id, _ := cmd.Flags().GetString("flatpak-id"); id == "" {
-				ExitError(fmt.Errorf("flatpak-id required for flathub platform"), format)
+		onConflict, _ := cmd.Flags().GetString("on-conflict")
+		policy, err := chimera.ParseConflictPolicy(onConflict)
+		if err != nil {
+			ExitError(err, format)
+		}
+		var exe string
+		if len(args) > 1 {
+			exe = args[1]
+		} else {
+			exe, err = platform.Command(fields)
+			if err != nil {
+				ExitError(err, format)
 			}
 		}
 
+		// Ensure the Chimera shortcuts file exists
+		err = chimera.EnsureShortcutsFileExists(platform.Name)
+		if err != nil {
+			ExitError(err, format)
+		}
+
 		// Read from the given shortcuts file
-		shortcutsFile := chimera.GetShortcutsFile(platform)
+		shortcutsFile := chimera.GetShortcutsFile(platform.Name)
 		DebugPrintln("Using shortcuts file:", shortcutsFile)
 		shortcuts, err := chimera.LoadShortcuts(shortcutsFile)
 		if err != nil {
@@ -186,65 +467,85 @@ var chimeraAddCmd = &cobra.Command{
 		// Create the new shortcut to add
 		newShortcut := newChimeraShortcutFromFlags(cmd, name, exe)
 
-		// Download images for the user if specified
+		// Check for an existing shortcut before downloading anything
+		if i := chimera.FindShortcut(shortcuts, name); i >= 0 {
+			switch policy {
+			case chimera.ConflictError:
+				ExitError(fmt.Errorf("%w: %v (use --on-conflict to skip, replace or append)", chimera.ErrShortcutExists, name), format)
+			case chimera.ConflictSkip:
+				DebugPrintln("Skipping existing shortcut:", name)
+				printChimeraShortcut(shortcuts[i], format)
+				return
+			}
+		}
+
+		// Download images for the user if specified. The shortcut is still
+		// added if some images could not be downloaded.
+		var downloadErr error
 		if download, _ := cmd.Flags().GetBool("download-images"); download {
 			DebugPrintln("Requested to download images for shortcut")
 			// Check that we have an API key
-			apiKey, _ := cmd.Flags().GetString("api-key")
+			apiKey := getAPIKey(cmd.Flags())
 			if apiKey == "" {
-				ExitError(fmt.Errorf("no API key specified"), format)
+				ExitError(newCommandError(ErrAuth, fmt.Errorf("no API key specified (use --api-key or the login command)")), format)
 			}
 
 			// Download the images
-			client := steamgriddb.NewClient(apiKey)
-			downloaded, err := downloadChimeraImages(cmd.Flags(), client, platform, newShortcut)
+			client, err := newGridDBClient(cmd.Flags(), apiKey)
 			if err != nil {
 				ExitError(err, format)
 			}
+			var downloaded map[string]string
+			downloaded, downloadErr = downloadChimeraImages(client, platform, newShortcut, fields[platform.IDField], newDownloadOptions(cmd.Flags()))
 
 			// Update our shortcut with image paths
 			for imgType, path := range downloaded {
-				switch imgType {
-				case "poster":
-					newShortcut.Poster = path
-				case "background":
-					newShortcut.Background = path
-				case "banner":
-					newShortcut.Banner = path
-				case "logo":
-					newShortcut.Logo = path
-				}
+				newShortcut.SetImage(imgType, path)
 			}
 		}
 
 		// Save the shortcuts
-		shortcuts = append(shortcuts, newShortcut)
+		shortcuts, _, err = chimera.AddShortcut(shortcuts, newShortcut, policy)
+		if err != nil {
+			ExitError(err, format)
+		}
 		err = chimera.SaveShortcuts(shortcutsFile, shortcuts)
 		if err != nil {
 			ExitError(err, format)
 		}
 
 		// Print the output
-		switch format {
-		case "term":
-			fmt.Println(newShortcut.Name)
-			fmt.Println("  Executable:", newShortcut.Cmd)
-			fmt.Println("  Poster:", newShortcut.Poster)
-			fmt.Println("  Banner:", newShortcut.Banner)
-			fmt.Println("  Logo:", newShortcut.Logo)
-			fmt.Println("  Background:", newShortcut.Background)
-		case "json":
-			out, err := json.MarshalIndent(newShortcut, "", "  ")
-			if err != nil {
-				ExitError(err, format)
-			}
-			fmt.Println(string(out))
-		default:
-			panic("unknown output format: " + format)
+		printChimeraShortcut(newShortcut, format)
+		if downloadErr != nil {
+			ExitError(downloadErr, format)
 		}
 	},
 }
 
+// printChimeraShortcut will print the given Chimera shortcut in the given format
+func printChimeraShortcut(sc *chimera.Shortcut, format string) {
+	printOutput(sc, func() {
+		fmt.Println(sc.Name)
+		fmt.Println("  Executable:", sc.Cmd)
+		fmt.Println("  Poster:", sc.Poster)
+		fmt.Println("  Banner:", sc.Banner)
+		fmt.Println("  Logo:", sc.Logo)
+		fmt.Println("  Background:", sc.Background)
+		fmt.Println("  Icon:", sc.Icon)
+	})
+}
+
+// getChimeraFields will return the platform fields that were set with flags
+func getChimeraFields(cmd *cobra.Command) map[string]string {
+	fields := map[string]string{}
+	for _, field := range chimera.AllFields() {
+		if value, _ := cmd.Flags().GetString(field); value != "" {
+			fields[field] = value
+		}
+	}
+	return fields
+}
+
 // Creates a new Chimera shortcut entry from command-line flags
 func newChimeraShortcutFromFlags(cmd *cobra.Command, name, exe string) *chimera.Shortcut {
 	getString := func(name string) string {
@@ -282,24 +583,33 @@ func init() {
 	addCmd.Flags().Bool("allow-overlay", true, "Allow steam overlay")
 	addCmd.Flags().Bool("is-hidden", false, "Whether or not the shortcut is hidden")
 	addCmd.Flags().String("flatpak-id", "", "Flatpak ID of the shortcut")
+	addCmd.Flags().String("flatpak", "", "Add the installed Flatpak app with the given ID (e.g. org.libretro.RetroArch)")
 	addCmd.Flags().String("launch-options", "", "Launch options for the shortcut")
 	addCmd.Flags().Bool("openvr", false, "Use OpenVR for the shortcut")
 	addCmd.Flags().String("shortcut-path", "", "Path to the shortcut file for this application")
 	addCmd.Flags().String("start-dir", "", "Working directory where the app is started")
 	addCmd.Flags().String("icon", "", "Path to the icon to use for this application")
 	addCmd.Flags().StringSlice("tags", []string{}, "Comma-separated list of tags")
+	addCmd.Flags().String("compat-tool", "", "Compatibility tool to run the shortcut with (e.g. proton_experimental)")
 	addCmd.Flags().String("user", "all", "Steam user ID to add the shortcut for")
-	addCmd.Flags().StringP("chimera-shortcut", "c", "~/.local/share/chimera/shortcuts/chimera.flathub.yaml", "Optional path to Chimera shortcut config")
+	addCmd.Flags().Bool("skip-validation", false, "Write the shortcut without validating or fixing it")
+	addCmd.Flags().Bool("chimera", false, "Also add the shortcut to Chimera")
+	addCmd.Flags().StringP("chimera-shortcut", "c", "", "Optional path to Chimera shortcut config to add the shortcut to (default is chimera.flathub.yaml for --flatpak and chimera.manual.yaml otherwise in the Chimera shortcuts directory)")
 
-	addCmd.Flags().StringP("api-key", "k", "", "SteamGridDB API Key")
+	addCmd.Flags().StringP("api-key", "k", "", "SteamGridDB API Key (env SSM_API_KEY)")
 	addCmd.Flags().BoolP("download-images", "i", false, "Auto-download artwork from SteamGridDB for shortcut (requires SteamGridDB API Key)")
 
 	// Chimera add flags
 	chimeraAddCmd.Flags().String("start-dir", "~", "Working directory where the app is started")
 	chimeraAddCmd.Flags().Bool("is-hidden", false, "Whether or not the shortcut is hidden")
 	chimeraAddCmd.Flags().StringSlice("tags", []string{}, "Comma-separated list of tags")
-	chimeraAddCmd.Flags().String("flatpak-id", "", "Flatpak ID of the shortcut (if platform 'flathub')")
-
-	chimeraAddCmd.Flags().StringP("api-key", "k", "", "SteamGridDB API Key")
+	chimeraAddCmd.Flags().String("on-conflict", string(chimera.ConflictError), "What to do if a shortcut with the same name exists (error, skip, replace, append)")
+	chimeraAddCmd.Flags().String(chimera.FieldFlatpakID, "", "Flatpak ID of the shortcut (if platform 'flathub')")
+	chimeraAddCmd.Flags().String(chimera.FieldGOGID, "", "GOG game ID of the shortcut (if platform 'gog')")
+	chimeraAddCmd.Flags().String(chimera.FieldEpicID, "", "Epic Games Store app name of the shortcut (if platform 'epic-store')")
+	chimeraAddCmd.Flags().String(chimera.FieldROM, "", "Path to the ROM of the shortcut (if an emulator platform)")
+	chimeraAddCmd.Flags().String(chimera.FieldCore, "", "RetroArch core to run the ROM with (if platform 'retroarch')")
+
+	chimeraAddCmd.Flags().StringP("api-key", "k", "", "SteamGridDB API Key (env SSM_API_KEY)")
 	chimeraAddCmd.Flags().BoolP("download-images", "i", false, "Auto-download artwork from SteamGridDB for shortcut (requires SteamGridDB API Key)")
 }
@@ -0,0 +1,135 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/vdf/text"
+	"github.com/spf13/cobra"
+)
+
+// appsCmd represents the apps command
+var appsCmd = &cobra.Command{
+	Use:   "apps",
+	Short: "Show metadata of Steam apps",
+	Long:  `Show metadata of Steam apps from Steam's local app info cache`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// appsInfoCmd represents the apps info command
+var appsInfoCmd = &cobra.Command{
+	Use:   "info <appid>...",
+	Short: "Show the metadata of Steam apps",
+	Long: `Shows the name, type, artwork, store tags and launch options of the given
+Steam apps from appcache/appinfo.vdf. The cache only contains apps that the
+Steam client has seen, such as owned games. Use --raw to show all of the
+metadata in text KeyValues format.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		raw, _ := cmd.Flags().GetBool("raw")
+
+		appIds := []uint32{}
+		for _, arg := range args {
+			appId, err := strconv.ParseUint(arg, 10, 32)
+			if err != nil {
+				ExitError(fmt.Errorf("invalid app id: %v", arg), format)
+			}
+			appIds = append(appIds, uint32(appId))
+		}
+
+		appInfoPath, err := steam.GetAppInfoPath()
+		if err != nil {
+			ExitError(err, format)
+		}
+		apps, err := steam.ReadAppInfo(appInfoPath, appIds...)
+		if err != nil {
+			ExitError(err, format)
+		}
+		if len(apps) < len(appIds) {
+			found := map[uint32]bool{}
+			for _, app := range apps {
+				found[app.AppID] = true
+			}
+			for _, appId := range appIds {
+				if !found[appId] {
+					ExitError(fmt.Errorf("no app info found for app id: %v", appId), format)
+				}
+			}
+		}
+
+		// Print the raw metadata
+		if raw {
+			for _, app := range apps {
+				doc := text.NewDocument()
+				doc.Append(app.Data)
+				fmt.Print(string(text.Marshal(doc)))
+			}
+			return
+		}
+
+		// Print the output
+		sort.Slice(apps, func(i, j int) bool { return apps[i].AppID < apps[j].AppID })
+		printOutput(apps, func() {
+			for _, app := range apps {
+				fmt.Println(app.Name)
+				fmt.Println("  AppId:        ", app.AppID)
+				fmt.Println("  Type:         ", app.Type)
+				fmt.Println("  Last Updated: ", app.LastUpdated.Format("2006-01-02 15:04:05"))
+				fmt.Println("  Change Number:", app.ChangeNumber)
+				fmt.Println("  Store Tags:   ", app.StoreTags)
+				if len(app.Assets) > 0 {
+					fmt.Println("  Assets:")
+					for _, key := range sortedKeys(app.Assets) {
+						fmt.Printf("    %v: %v\n", key, app.Assets[key])
+					}
+				}
+				if len(app.LaunchConfigs) > 0 {
+					fmt.Println("  Launch Configs:")
+					for _, config := range app.LaunchConfigs {
+						fmt.Printf("    %v: %v %v\n", config.ID, config.Executable, config.Arguments)
+						if config.Description != "" {
+							fmt.Println("      Description:", config.Description)
+						}
+						if config.OSList != "" {
+							fmt.Println("      OS:         ", config.OSList)
+						}
+					}
+				}
+			}
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(appsCmd)
+	appsCmd.AddCommand(appsInfoCmd)
+	appsInfoCmd.Flags().Bool("raw", false, "Print all of the app metadata in text KeyValues format")
+}
@@ -0,0 +1,366 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	_ "image/jpeg"
+	_ "image/png"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steamgriddb"
+	"github.com/spf13/cobra"
+)
+
+// AuditReport contains the artwork problems found for a single Steam user
+type AuditReport struct {
+	// Missing is a map of shortcut app ids to the image types they are missing
+	Missing map[string][]steam.ImageType `json:"missing"`
+	// Orphaned is a list of image files whose app id is in the shortcut range
+	// but matches no shortcut
+	Orphaned []string `json:"orphaned"`
+	// Uninstalled is a list of image files for Steam games that are not
+	// installed. These may still be owned, so they are only removed with
+	// --remove-uninstalled.
+	Uninstalled []string `json:"uninstalled"`
+	// Duplicates is a map of image slots (e.g. "1234p") to the files that
+	// exist for that slot with different extensions
+	Duplicates map[string][]string `json:"duplicates"`
+	// Broken is a list of image files that are empty or cannot be read
+	Broken []string `json:"broken"`
+	// MissingIcons is a map of shortcut app ids to icon paths that no longer
+	// exist
+	MissingIcons map[string]string `json:"missingIcons"`
+	// Fixed is a list of actions taken when fixing problems
+	Fixed []string `json:"fixed,omitempty"`
+
+	names map[string]string
+}
+
+// HasProblems will return whether or not the audit found any problems
+func (r *AuditReport) HasProblems() bool {
+	return len(r.Missing) > 0 || len(r.Orphaned) > 0 || len(r.Uninstalled) > 0 || len(r.Duplicates) > 0 ||
+		len(r.Broken) > 0 || len(r.MissingIcons) > 0
+}
+
+// Print will print the audit report to the terminal
+func (r *AuditReport) Print() {
+	if !r.HasProblems() {
+		fmt.Println("  No problems found")
+	}
+	for _, appId := range sortedKeys(r.Missing) {
+		kinds := []string{}
+		for _, kind := range r.Missing[appId] {
+			kinds = append(kinds, string(kind))
+		}
+		fmt.Printf("  Missing images for %v (%v): %v\n", r.names[appId], appId, strings.Join(kinds, ", "))
+	}
+	for _, appId := range sortedKeys(r.MissingIcons) {
+		fmt.Printf("  Icon not found for %v (%v): %v\n", r.names[appId], appId, r.MissingIcons[appId])
+	}
+	for _, file := range r.Orphaned {
+		fmt.Println("  Orphaned image:", file)
+	}
+	for _, file := range r.Uninstalled {
+		fmt.Println("  Image for uninstalled game:", file)
+	}
+	for _, slot := range sortedKeys(r.Duplicates) {
+		fmt.Printf("  Duplicate images for %v: %v\n", slot, strings.Join(r.Duplicates[slot], ", "))
+	}
+	for _, file := range r.Broken {
+		fmt.Println("  Broken image:", file)
+	}
+	for _, fix := range r.Fixed {
+		fmt.Println("  Fixed:", fix)
+	}
+}
+
+// gridAuditCmd represents the grid audit command
+var gridAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Check for missing, orphaned and broken artwork",
+	Long: `Cross-references Steam shortcuts with the files in the Steam grid directory
+and reports missing, orphaned, duplicate and broken artwork.
+
+Only images with a non-Steam shortcut app id are considered orphaned and
+removed with --fix. Images for Steam games that are not installed are reported
+separately, as the game may still be owned, and are only removed when
+--remove-uninstalled is also given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		fix, _ := cmd.Flags().GetBool("fix")
+		removeUninstalled, _ := cmd.Flags().GetBool("remove-uninstalled")
+		if removeUninstalled && !fix {
+			ExitError(newCommandError(ErrUsage, fmt.Errorf("--remove-uninstalled requires --fix")), format)
+		}
+
+		// A SteamGridDB client is needed to download missing images
+		var client *steamgriddb.Client
+		if fix {
+			apiKey := getAPIKey(cmd.Flags())
+			if apiKey == "" {
+				ExitError(newCommandError(ErrAuth, fmt.Errorf("API key is required to fix missing images (use --api-key or the login command)")), format)
+			}
+			var err error
+			client, err = newGridDBClient(cmd.Flags(), apiKey)
+			if err != nil {
+				ExitError(err, format)
+			}
+		}
+
+		// Images for installed Steam games share the grid directory and
+		// should not be considered orphaned.
+		apps, err := steam.GetInstalledGames()
+		if err != nil {
+			ExitError(fmt.Errorf("unable to read installed Steam games: %w", err), format)
+		}
+		knownApps := map[string]bool{}
+		for _, app := range apps {
+			knownApps[app.AppID] = true
+		}
+
+		// Fetch all users
+		users, err := steam.GetUsers()
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Check to see if we're auditing for just one user
+		onlyForUser := cmd.Flags().Lookup("user").Value.String()
+
+		var errors error
+		results := map[string]*AuditReport{}
+		for _, user := range users {
+			if !steam.HasShortcuts(user) {
+				continue
+			}
+			if onlyForUser != "all" && onlyForUser != user {
+				continue
+			}
+
+			shortcutsPath, _ := steam.GetShortcutsPath(user)
+			shortcuts, err := shortcut.Load(shortcutsPath)
+			if err != nil {
+				ExitError(err, format)
+			}
+			report, err := auditGrid(user, shortcuts, knownApps)
+			if err != nil {
+				ExitError(err, format)
+			}
+			results[user] = report
+
+			if !fix {
+				continue
+			}
+
+			// Remove orphaned images
+			for _, file := range report.Orphaned {
+				err := os.Remove(file)
+				if err != nil {
+					errors = multierror.Append(errors, err)
+					continue
+				}
+				report.Fixed = append(report.Fixed, "removed "+file)
+			}
+			if removeUninstalled {
+				for _, file := range report.Uninstalled {
+					err := os.Remove(file)
+					if err != nil {
+						errors = multierror.Append(errors, err)
+						continue
+					}
+					report.Fixed = append(report.Fixed, "removed "+file)
+				}
+			}
+
+			// Download any missing images
+			opts := newDownloadOptions(cmd.Flags())
+			opts.onlyMissing = true
+			for _, sc := range shortcuts.Shortcuts {
+				sc := sc
+				appId := fmt.Sprintf("%v", sc.Appid)
+				if _, ok := report.Missing[appId]; !ok {
+					continue
+				}
+				downloaded, err := downloadImages(client, user, &sc, opts)
+				if err != nil {
+					errors = multierror.Append(errors, fmt.Errorf("%v: %v", sc.AppName, err))
+				}
+				for _, file := range downloaded {
+					report.Fixed = append(report.Fixed, "downloaded "+file)
+				}
+			}
+		}
+
+		// Print the output
+		printOutput(results, func() {
+			for user, report := range results {
+				fmt.Println("User:", user)
+				report.Print()
+			}
+		}, "user")
+
+		if errors != nil {
+			ExitError(errors, format)
+		}
+	},
+}
+
+// auditGrid will check the user's grid directory against the given shortcuts
+func auditGrid(user string, shortcuts *shortcut.Shortcuts, knownApps map[string]bool) (*AuditReport, error) {
+	report := &AuditReport{
+		Missing:      map[string][]steam.ImageType{},
+		Orphaned:     []string{},
+		Uninstalled:  []string{},
+		Duplicates:   map[string][]string{},
+		Broken:       []string{},
+		MissingIcons: map[string]string{},
+		names:        map[string]string{},
+	}
+
+	// Build a lookup of shortcut app ids
+	shortcutIDs := map[string]*shortcut.Shortcut{}
+	for _, sc := range shortcuts.Shortcuts {
+		sc := sc
+		appId := fmt.Sprintf("%v", sc.Appid)
+		shortcutIDs[appId] = &sc
+		report.names[appId] = sc.AppName
+	}
+
+	// Check every image in the grid directory
+	images, err := steam.GetImages(user)
+	if err != nil {
+		return nil, err
+	}
+	found := map[string]map[steam.ImageType]bool{}
+	slots := map[string][]string{}
+	for _, img := range images {
+		if found[img.AppID] == nil {
+			found[img.AppID] = map[steam.ImageType]bool{}
+		}
+		found[img.AppID][img.Type] = true
+
+		slot := strings.TrimSuffix(filepath.Base(img.Path), filepath.Ext(img.Path))
+		slots[slot] = append(slots[slot], img.Path)
+
+		if _, ok := shortcutIDs[img.AppID]; !ok && !knownApps[img.AppID] {
+			// Only images in the shortcut app id range can be orphaned.
+			// Anything else may belong to an owned but uninstalled game.
+			appId, err := strconv.ParseUint(img.AppID, 10, 64)
+			if err == nil && shortcut.IsShortcutAppID(appId) {
+				report.Orphaned = append(report.Orphaned, img.Path)
+			} else {
+				report.Uninstalled = append(report.Uninstalled, img.Path)
+			}
+			continue
+		}
+		if !isReadableImage(img.Path) {
+			report.Broken = append(report.Broken, img.Path)
+		}
+	}
+	for slot, files := range slots {
+		if len(files) > 1 {
+			report.Duplicates[slot] = files
+		}
+	}
+
+	// Check each shortcut for missing images
+	for appId, sc := range shortcutIDs {
+		for _, kind := range steam.ImageTypes {
+			if found[appId][kind] {
+				continue
+			}
+			// Icons can live outside of the grid directory
+			if kind == steam.ImageIcon && sc.Icon != "" {
+				continue
+			}
+			report.Missing[appId] = append(report.Missing[appId], kind)
+		}
+		if sc.Icon == "" {
+			continue
+		}
+		if _, err := os.Stat(sc.Icon); err != nil {
+			report.MissingIcons[appId] = sc.Icon
+		}
+	}
+	sort.Strings(report.Orphaned)
+	sort.Strings(report.Uninstalled)
+	sort.Strings(report.Broken)
+
+	return report, nil
+}
+
+// isReadableImage will return whether or not the given image file is non-empty
+// and can be decoded.
+func isReadableImage(file string) bool {
+	info, err := os.Stat(file)
+	if err != nil || info.Size() == 0 {
+		return false
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	// Go cannot decode .ico files, so only check that they can be opened.
+	if strings.EqualFold(filepath.Ext(file), ".ico") {
+		return true
+	}
+	_, _, err = image.DecodeConfig(f)
+	return err == nil
+}
+
+// sortedKeys will return the sorted keys of the given map
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	gridCmd.AddCommand(gridAuditCmd)
+
+	gridAuditCmd.Flags().Bool("fix", false, "Remove orphaned shortcut images and download missing images")
+	gridAuditCmd.Flags().Bool("remove-uninstalled", false, "With --fix, also remove images for Steam games that are not installed")
+	gridAuditCmd.Flags().StringP("api-key", "k", "", "SteamGridDB API Key (required with --fix, env SSM_API_KEY)")
+	gridAuditCmd.Flags().String("style-hero", "", `Optional hero style to download ("alternate" "blurred" "material")`)
+	gridAuditCmd.Flags().String("style-grid", "", `Optional grid style to download ("alternate" "blurred" "white_logo" "material" "no_logo")`)
+	gridAuditCmd.Flags().String("style-icon", "", `Optional icon style to download ("official" "custom")`)
+	gridAuditCmd.Flags().String("style-logo", "", `Optional logo style to download ("official" "white" "black" "custom")`)
+	gridAuditCmd.Flags().String("nsfw", "", `Whether to download NSFW images ("any" "false" "true", default "false")`)
+	gridAuditCmd.Flags().String("humor", "", `Whether to download humor images ("any" "false" "true", default "any")`)
+}
@@ -25,6 +25,10 @@ SOFTWARE.
 package cmd
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/chimera"
 	"github.com/spf13/cobra"
 )
 
@@ -38,15 +42,51 @@ var chimeraCmd = &cobra.Command{
 	},
 }
 
+// chimeraPlatformsCmd represents the chimera platforms command
+var chimeraPlatformsCmd = &cobra.Command{
+	Use:   "platforms",
+	Short: "List supported Chimera platforms",
+	Long: `Lists the supported Chimera platforms and the fields each one requires when
+adding a shortcut.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		platforms := chimera.GetPlatforms()
+
+		// Print the output
+		printOutput(platforms, func() {
+			for _, platform := range platforms {
+				fmt.Println(platform.Name)
+				fmt.Println("  Title:", platform.Title)
+				fmt.Println("  Kind: ", platform.Kind)
+				if len(platform.Fields) > 0 {
+					fmt.Println("  Required Flags:", "--"+strings.Join(platform.Fields, ", --"))
+				}
+			}
+		})
+	},
+}
+
+// getChimeraPlatform will return the supported Chimera platform selected with
+// the --platform flag, exiting if it is not supported.
+func getChimeraPlatform(format string) *chimera.Platform {
+	name := chimeraCmd.PersistentFlags().Lookup("platform").Value.String()
+	platform, err := chimera.GetPlatform(name)
+	if err != nil {
+		ExitError(err, format)
+	}
+	DebugPrintln("Using Chimera platform:", platform.Name)
+	return platform
+}
+
 func init() {
 	rootCmd.AddCommand(chimeraCmd)
+	chimeraCmd.AddCommand(chimeraPlatformsCmd)
 
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
 	// and all subcommands, e.g.:
 	// chimeraCmd.PersistentFlags().String("foo", "", "A help for foo")
-	chimeraCmd.PersistentFlags().StringP("platform", "p", "flathub", "Shortcut platform (e.g. flatpak, gog, steam, etc.)")
+	chimeraCmd.PersistentFlags().StringP("platform", "p", "flathub", "Shortcut platform (e.g. flathub, gog, epic-store, retroarch, snes, manual; see 'chimera platforms')")
 	chimeraCmd.MarkFlagRequired("platform")
 
 	// Cobra supports local flags which will only run when this command
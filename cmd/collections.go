@@ -0,0 +1,310 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
+	"github.com/spf13/cobra"
+)
+
+// collectionsCmd represents the collections command
+var collectionsCmd = &cobra.Command{
+	Use:   "collections",
+	Short: "Manage Steam library collections",
+	Long: `Lists and manages the collections shown in the Steam library. Collections
+are stored in each user's cloud storage file, which Steam overwrites while it
+is running, so Steam should be closed when making changes.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		users, err := getSelectedUsers(cmd)
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		results := map[string][]*steam.Collection{}
+		for _, user := range users {
+			collections, err := steam.LoadCollections(user)
+			if err != nil {
+				ExitError(err, format)
+			}
+			results[user] = collections.List()
+		}
+
+		// Print the output
+		printOutput(results, func() {
+			for user, collections := range results {
+				if len(collections) == 0 {
+					continue
+				}
+				fmt.Println("User:", user)
+				for _, collection := range collections {
+					fmt.Println("  ", collection.Name)
+					fmt.Println("    ID:  ", collection.ID)
+					if collection.Dynamic {
+						fmt.Println("    Apps: dynamic")
+						continue
+					}
+					fmt.Println("    Apps:", len(collection.Added))
+				}
+			}
+		}, "user")
+	},
+}
+
+// collectionsCreateCmd represents the collections create command
+var collectionsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a new collection",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		editCollections(cmd, "Created", func(user string, collections *steam.Collections) (*steam.Collection, error) {
+			collection, err := collections.Create(args[0])
+			if err != nil {
+				return nil, err
+			}
+			DebugPrintln("Created collection", collection.ID, "for user", user)
+			return collection, nil
+		})
+	},
+}
+
+// collectionsRenameCmd represents the collections rename command
+var collectionsRenameCmd = &cobra.Command{
+	Use:   "rename <name|id> <new-name>",
+	Short: "Rename a collection",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		editCollections(cmd, "Renamed", func(user string, collections *steam.Collections) (*steam.Collection, error) {
+			err := collections.Rename(args[0], args[1])
+			if err != nil {
+				return nil, err
+			}
+			return collections.Get(args[1])
+		})
+	},
+}
+
+// collectionsDeleteCmd represents the collections delete command
+var collectionsDeleteCmd = &cobra.Command{
+	Use:   "delete <name|id>",
+	Short: "Delete a collection",
+	Long:  `Deletes a collection. The apps in the collection are not removed.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		editCollections(cmd, "Deleted", func(user string, collections *steam.Collections) (*steam.Collection, error) {
+			// Look up the collection first since it is gone once deleted
+			collection, err := collections.Get(args[0])
+			if err != nil {
+				return nil, err
+			}
+			return collection, collections.Delete(collection.ID)
+		})
+	},
+}
+
+// collectionsAddCmd represents the collections add command
+var collectionsAddCmd = &cobra.Command{
+	Use:   "add <collection> <name|appid>...",
+	Short: "Add shortcuts or games to a collection",
+	Long: `Adds the given shortcuts (by name or app id) or Steam games (by app id) to
+a collection. Use --create to create the collection if it does not exist.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		create, _ := cmd.Flags().GetBool("create")
+		editCollections(cmd, "Updated", func(user string, collections *steam.Collections) (*steam.Collection, error) {
+			appIds, err := resolveCollectionApps(user, args[1:])
+			if err != nil {
+				return nil, err
+			}
+			if create {
+				_, err = collections.GetOrCreate(args[0])
+				if err != nil {
+					return nil, err
+				}
+			}
+			added, err := collections.AddApps(args[0], appIds...)
+			if err != nil {
+				return nil, err
+			}
+			DebugPrintln("Added", added, "apps to collection for user", user)
+			return collections.Get(args[0])
+		})
+	},
+}
+
+// collectionsRemoveCmd represents the collections remove command
+var collectionsRemoveCmd = &cobra.Command{
+	Use:   "remove <collection> <name|appid>...",
+	Short: "Remove shortcuts or games from a collection",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		editCollections(cmd, "Updated", func(user string, collections *steam.Collections) (*steam.Collection, error) {
+			appIds, err := resolveCollectionApps(user, args[1:])
+			if err != nil {
+				return nil, err
+			}
+			removed, err := collections.RemoveApps(args[0], appIds...)
+			if err != nil {
+				return nil, err
+			}
+			DebugPrintln("Removed", removed, "apps from collection for user", user)
+			return collections.Get(args[0])
+		})
+	},
+}
+
+// getSelectedUsers will return the Steam users selected with the --user flag
+func getSelectedUsers(cmd *cobra.Command) ([]string, error) {
+	users, err := steam.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+	onlyForUser := cmd.Flags().Lookup("user").Value.String()
+	if onlyForUser == "all" {
+		return users, nil
+	}
+	for _, user := range users {
+		if user == onlyForUser {
+			return []string{user}, nil
+		}
+	}
+	return nil, fmt.Errorf("no steam user found with id: %v", onlyForUser)
+}
+
+// editCollections will load the collections of each selected user, apply the
+// given change and save them. The change is applied for every user before
+// anything is written, so a change that fails for one user is not saved for
+// the others. The collection returned by the change is printed for each user
+// along with the given action.
+func editCollections(cmd *cobra.Command, action string, change func(user string, collections *steam.Collections) (*steam.Collection, error)) {
+	format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+	users, err := getSelectedUsers(cmd)
+	if err != nil {
+		ExitError(err, format)
+	}
+	changed := []*steam.Collections{}
+	results := map[string]*steam.Collection{}
+	for _, user := range users {
+		collections, err := steam.LoadCollections(user)
+		if err != nil {
+			ExitError(err, format)
+		}
+		collection, err := change(user, collections)
+		if err != nil {
+			ExitError(fmt.Errorf("user %v: %v", user, err), format)
+		}
+		changed = append(changed, collections)
+		results[user] = collection
+	}
+
+	// Write the changes
+	for _, collections := range changed {
+		err = collections.Save()
+		if err != nil {
+			ExitError(err, format)
+		}
+	}
+
+	// Print the output
+	printOutput(results, func() {
+		for user, collection := range results {
+			fmt.Printf("%v collection %v (%v) for user %v\n", action, collection.Name, collection.ID, user)
+		}
+	}, "user")
+}
+
+// resolveCollectionApps will return the app ids for the given shortcut names
+// or app ids.
+func resolveCollectionApps(user string, namesOrIDs []string) ([]int64, error) {
+	var shortcuts *shortcut.Shortcuts
+	if steam.HasShortcuts(user) {
+		shortcutsPath, _ := steam.GetShortcutsPath(user)
+		var err error
+		shortcuts, err = shortcut.Load(shortcutsPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	appIds := []int64{}
+	for _, nameOrID := range namesOrIDs {
+		if shortcuts != nil {
+			if _, sc, err := shortcuts.Lookup(nameOrID); err == nil {
+				appIds = append(appIds, sc.Appid)
+				continue
+			}
+		}
+		appId, err := strconv.ParseInt(nameOrID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w with name or id: %v", shortcut.ErrNotFound, nameOrID)
+		}
+		appIds = append(appIds, appId)
+	}
+	return appIds, nil
+}
+
+// addToTagCollections will add the given shortcut to a collection for each of
+// its tags, creating collections as needed. Steam no longer reads the tags
+// in shortcuts.vdf, so this makes them show up in the library.
+func addToTagCollections(user string, sc *shortcut.Shortcut) error {
+	tags := sc.TagList()
+	if len(tags) == 0 {
+		return nil
+	}
+	collections, err := steam.LoadCollections(user)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		collection, err := collections.GetOrCreate(tag)
+		if err != nil {
+			return err
+		}
+		if collection.Dynamic {
+			DebugPrintln("Skipping dynamic collection", collection.Name)
+			continue
+		}
+		_, err = collections.AddApps(collection.ID, sc.Appid)
+		if err != nil {
+			return err
+		}
+	}
+	return collections.Save()
+}
+
+func init() {
+	rootCmd.AddCommand(collectionsCmd)
+	collectionsCmd.AddCommand(collectionsCreateCmd)
+	collectionsCmd.AddCommand(collectionsRenameCmd)
+	collectionsCmd.AddCommand(collectionsDeleteCmd)
+	collectionsCmd.AddCommand(collectionsAddCmd)
+	collectionsCmd.AddCommand(collectionsRemoveCmd)
+	collectionsCmd.PersistentFlags().String("user", "all", "Steam user ID to manage collections for")
+	collectionsAddCmd.Flags().Bool("create", false, "Create the collection if it does not exist")
+}
@@ -0,0 +1,77 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
+	"github.com/spf13/cobra"
+)
+
+// compatToolNone is the --compat-tool value that removes a compat tool mapping
+const compatToolNone = "none"
+
+// compatToolsCmd represents the compat-tools command
+var compatToolsCmd = &cobra.Command{
+	Use:   "compat-tools",
+	Short: "List available Wine/Proton compatibility tools",
+	Long: `Lists the compatibility tools found in compatibilitytools.d and the Steam
+library. Use the tool name with "add --compat-tool" or "edit --compat-tool"
+to run a shortcut through Proton.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		tools, err := steam.GetCompatTools()
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Print the output
+		printOutput(tools, func() {
+			for _, tool := range tools {
+				fmt.Println(tool.Name)
+				fmt.Println("  Display Name:", tool.DisplayName)
+				fmt.Println("  Source:      ", tool.Source)
+				fmt.Println("  Path:        ", tool.Path)
+			}
+		})
+	},
+}
+
+// resolveCompatTool will return the internal name of the compat tool given on
+// the command line. Returns an empty string if the mapping should be removed.
+func resolveCompatTool(name string) (string, error) {
+	if name == "" || name == compatToolNone {
+		return "", nil
+	}
+	tool, err := steam.LookupCompatTool(name)
+	if err != nil {
+		return "", fmt.Errorf("%v (see \"compat-tools\" for available tools)", err)
+	}
+	return tool.Name, nil
+}
+
+func init() {
+	rootCmd.AddCommand(compatToolsCmd)
+}
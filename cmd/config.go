@@ -0,0 +1,400 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/fileutil"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/keyring"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steamgriddb"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// configEnvPrefix is the prefix of environment variables that override config
+// keys, e.g. SSM_API_KEY for "api-key".
+const configEnvPrefix = "SSM"
+
+// configKey is a setting that can be stored in the config file. Command-line
+// flags with the same name use the configured value as their default.
+type configKey struct {
+	Name        string
+	Description string
+	Default     string
+	Values      []string
+	Secret      bool
+}
+
+// configKeys are all supported config keys
+var configKeys = []*configKey{
+	{Name: "api-key", Description: "SteamGridDB API Key", Secret: true},
+	{Name: "user", Description: "Steam user ID to manage shortcuts for", Default: "all"},
+	{Name: "output", Description: "Output format", Default: "term", Values: outputFormatNames()},
+	{Name: "style-grid", Description: "Preferred grid style to download"},
+	{Name: "style-hero", Description: "Preferred hero style to download"},
+	{Name: "style-logo", Description: "Preferred logo style to download"},
+	{Name: "style-icon", Description: "Preferred icon style to download"},
+	{Name: "nsfw", Description: "Include NSFW images from SteamGridDB", Values: contentPolicyNames()},
+	{Name: "humor", Description: "Include humor images from SteamGridDB", Values: contentPolicyNames()},
+	{Name: "steam-dir", Description: "Steam directory", Default: "~/.steam/steam"},
+	{Name: "chimera-dir", Description: "Chimera data directory", Default: "$XDG_DATA_HOME/chimera"},
+}
+
+// ConfigResult is a single config key and its current value
+type ConfigResult struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Source      string `json:"source"`
+	Env         string `json:"env"`
+	Description string `json:"description"`
+}
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage default settings",
+	Long: `Reads and changes the default settings stored in the config file
+($HOME/.steam-shortcut-manager.yaml unless --config is given). Flags with the
+same name as a config key use the configured value when they are not given on
+the command line. Each key can also be set with an environment variable named
+after the key, e.g. SSM_API_KEY for "api-key".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Show the value of a config key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		key, err := getConfigKey(args[0])
+		if err != nil {
+			ExitError(err, format)
+		}
+		result := key.result(false)
+
+		// Print the output
+		printOutput(result, func() {
+			fmt.Println(result.Value)
+		})
+	},
+}
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> [value]",
+	Short: "Change the value of a config key",
+	Long: `Changes the value of a config key in the config file. An empty value
+removes the key from the config file.
+
+Secret keys such as api-key are read from standard input instead of the command
+line so they do not end up in your shell history. They are stored in the config
+file in plain text, so prefer the login command, which stores the API key in
+the system keyring.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		key, err := getConfigKey(args[0])
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Secrets are only accepted from standard input, except for an empty
+		// value to remove them
+		var value string
+		switch {
+		case key.Secret && len(args) == 2 && args[1] != "":
+			ExitError(newCommandError(ErrUsage, fmt.Errorf("%v is read from standard input, not the command line (or use the login command)", key.Name)), format)
+		case key.Secret && len(args) == 1:
+			value, err = readSecret(key.Description)
+			if err != nil {
+				ExitError(err, format)
+			}
+		case len(args) == 1:
+			ExitError(newCommandError(ErrUsage, fmt.Errorf("no value given for %v", key.Name)), format)
+		default:
+			value = args[1]
+		}
+		if value != "" && len(key.Values) > 0 && !contains(key.Values, value) {
+			ExitError(fmt.Errorf("invalid value for %v: %v (supported: %v)", key.Name, value, strings.Join(key.Values, ", ")), format)
+		}
+
+		file, err := getConfigFile()
+		if err != nil {
+			ExitError(err, format)
+		}
+		err = setConfigValue(file, key.Name, value)
+		if err != nil {
+			ExitError(err, format)
+		}
+		DebugPrintln("Updated config file:", file)
+		viper.Set(key.Name, value)
+		result := key.result(false)
+		result.Source = "config"
+
+		// Print the output
+		printOutput(result, func() {
+			if value == "" {
+				fmt.Printf("Removed %v from %v\n", key.Name, file)
+				return
+			}
+			fmt.Printf("Set %v in %v\n", key.Name, file)
+		})
+	},
+}
+
+// configListCmd represents the config list command
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all config keys and their values",
+	Long: `Lists all config keys, their current values and where each value comes
+from: a command-line flag, an environment variable, the config file, the
+keyring (for an API key stored with login) or the default. Secret values are
+masked unless --show-secrets is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		showSecrets, _ := cmd.Flags().GetBool("show-secrets")
+
+		results := []*ConfigResult{}
+		for _, key := range configKeys {
+			results = append(results, key.result(!showSecrets))
+		}
+
+		// Print the output
+		printOutput(results, func() {
+			if file := viper.ConfigFileUsed(); file != "" {
+				fmt.Println("Config file:", file)
+			}
+			for _, result := range results {
+				value := result.Value
+				if value == "" {
+					value = "(unset)"
+				}
+				fmt.Printf("%v = %v (%v, env %v)\n", result.Key, value, result.Source, result.Env)
+				fmt.Printf("  %v\n", result.Description)
+			}
+		})
+	},
+}
+
+// result will return the current value of the config key. Secret values are
+// masked if requested.
+func (k *configKey) result(mask bool) *ConfigResult {
+	source := getConfigSource(k.Name)
+	value := viper.GetString(k.Name)
+	if source == "default" {
+		value = k.Default
+	}
+
+	// The API key may be stored with the login command instead
+	if source == "default" && k.Name == "api-key" {
+		if apiKey, err := keyring.Default().Get(apiKeySecret); err == nil {
+			source, value = "keyring", apiKey
+		}
+	}
+	if mask && k.Secret && value != "" {
+		value = maskSecret(value)
+	}
+
+	return &ConfigResult{
+		Key:         k.Name,
+		Value:       value,
+		Source:      source,
+		Env:         getConfigEnv(k.Name),
+		Description: k.Description,
+	}
+}
+
+// getConfigKey will return the config key with the given name
+func getConfigKey(name string) (*configKey, error) {
+	names := []string{}
+	for _, key := range configKeys {
+		if key.Name == name {
+			return key, nil
+		}
+		names = append(names, key.Name)
+	}
+	return nil, fmt.Errorf("unknown config key: %v (supported: %v)", name, strings.Join(names, ", "))
+}
+
+// getConfigEnv will return the environment variable for the given config key
+func getConfigEnv(name string) string {
+	return configEnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// getConfigSource will return where the value of the given config key comes
+// from: "flag", "env", "config" or "default".
+func getConfigSource(name string) string {
+	if flag := rootCmd.PersistentFlags().Lookup(name); flag != nil && flag.Changed {
+		return "flag"
+	}
+	if _, ok := os.LookupEnv(getConfigEnv(name)); ok {
+		return "env"
+	}
+	if viper.InConfig(name) {
+		return "config"
+	}
+	return "default"
+}
+
+// getConfigFile will return the path to the config file to write to
+func getConfigFile() (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	if file := viper.ConfigFileUsed(); file != "" {
+		return file, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return path.Join(home, ".steam-shortcut-manager.yaml"), nil
+}
+
+// setConfigValue will set the given key in the given YAML config file, keeping
+// any comments and other keys. An empty value removes the key. The file is
+// replaced atomically and is only readable by the current user since it can
+// hold the API key.
+func setConfigValue(file, key, value string) error {
+	doc := &yaml.Node{}
+	data, err := os.ReadFile(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	err = yaml.Unmarshal(data, doc)
+	if err != nil {
+		return fmt.Errorf("invalid config file %v: %w", file, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = &yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+		}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("invalid config file %v: expected a mapping of keys", file)
+	}
+
+	// Replace or remove the existing key
+	content := []*yaml.Node{}
+	found := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		k, v := root.Content[i], root.Content[i+1]
+		if k.Value != key {
+			content = append(content, k, v)
+			continue
+		}
+		found = true
+		if value == "" {
+			continue
+		}
+		v.SetString(value)
+		content = append(content, k, v)
+	}
+	if !found && value != "" {
+		k := &yaml.Node{}
+		k.SetString(key)
+		v := &yaml.Node{}
+		v.SetString(value)
+		content = append(content, k, v)
+	}
+	root.Content = content
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFile(file, out, 0600)
+}
+
+// applyConfig will set every flag of the given command and its subcommands
+// that is named after a config key to the configured value, unless the flag
+// was given on the command line.
+func applyConfig(cmd *cobra.Command) {
+	for _, flags := range []*pflag.FlagSet{cmd.PersistentFlags(), cmd.Flags()} {
+		flags.VisitAll(func(flag *pflag.Flag) {
+			if flag.Changed || !isConfigKey(flag.Name) || !viper.IsSet(flag.Name) {
+				return
+			}
+			flag.Value.Set(viper.GetString(flag.Name))
+		})
+	}
+	for _, child := range cmd.Commands() {
+		applyConfig(child)
+	}
+}
+
+// getFlagOrConfig will return the value of the given flag, or the configured
+// value if the command does not have the flag.
+func getFlagOrConfig(flags *pflag.FlagSet, name string) string {
+	if flag := flags.Lookup(name); flag != nil {
+		return flag.Value.String()
+	}
+	return viper.GetString(name)
+}
+
+// isConfigKey will return whether or not the given name is a config key
+func isConfigKey(name string) bool {
+	_, err := getConfigKey(name)
+	return err == nil
+}
+
+// maskSecret will hide all but the last few characters of the given value
+func maskSecret(value string) string {
+	if len(value) <= 4 {
+		return strings.Repeat("*", len(value))
+	}
+	return strings.Repeat("*", 8) + value[len(value)-4:]
+}
+
+// contentPolicyNames will return the names of all SteamGridDB content
+// policies
+func contentPolicyNames() []string {
+	names := []string{}
+	for _, policy := range steamgriddb.ContentPolicies {
+		names = append(names, string(policy))
+	}
+	return names
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+
+	configListCmd.Flags().Bool("show-secrets", false, "Show secret values like the API key")
+}
@@ -0,0 +1,133 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package cmd
+
+import (
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/desktop"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+	"github.com/spf13/cobra"
+)
+
+// importDesktopCmd represents the import-desktop command
+var importDesktopCmd = &cobra.Command{
+	Use:   "import-desktop [file.desktop...]",
+	Short: "Import Steam shortcuts from XDG .desktop files",
+	Long: `Imports Steam shortcuts from XDG .desktop files. If no files are given, the
+user and system application directories (including Flatpak exports) are
+scanned.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+
+		// Discover the desktop files to import
+		files := args
+		if len(files) == 0 {
+			var err error
+			files, err = desktop.Discover(desktop.ApplicationDirs())
+			if err != nil {
+				ExitError(err, format)
+			}
+		}
+
+		// Parse and filter the desktop entries
+		var errors error
+		entries := []*desktop.Entry{}
+		for _, file := range files {
+			entry, err := desktop.Load(file)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				continue
+			}
+			if !includeDesktopEntry(cmd, entry, len(args) > 0) {
+				DebugPrintln("Skipping desktop entry:", file)
+				continue
+			}
+			entries = append(entries, entry)
+		}
+
+		// Convert the entries into shortcuts
+		newShortcuts := []*shortcut.Shortcut{}
+		for _, entry := range entries {
+			sc, err := entry.Shortcut()
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				continue
+			}
+			newShortcuts = append(newShortcuts, sc)
+		}
+
+		importShortcuts(cmd, newShortcuts, errors, format)
+	},
+}
+
+// includeDesktopEntry will return whether or not the given desktop entry
+// matches the command-line filters. Explicitly given files are always
+// included, even if they are hidden.
+func includeDesktopEntry(cmd *cobra.Command, entry *desktop.Entry, explicit bool) bool {
+	includeHidden, _ := cmd.Flags().GetBool("include-hidden")
+	if !explicit && !includeHidden && !entry.IsVisible() {
+		return false
+	}
+	if entry.Type != "" && entry.Type != "Application" {
+		return false
+	}
+
+	// Filter by category
+	categories, _ := cmd.Flags().GetStringSlice("category")
+	if len(categories) > 0 {
+		found := false
+		for _, category := range categories {
+			if entry.HasCategory(category) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	// Filter by name or desktop file ID
+	globs, _ := cmd.Flags().GetStringSlice("glob")
+	return matchesAnyGlob(globs, entry.Name, entry.ID)
+}
+
+func init() {
+	rootCmd.AddCommand(importDesktopCmd)
+
+	importDesktopCmd.Flags().StringSlice("category", []string{}, "Only import entries in one of the given categories (e.g. Game)")
+	importDesktopCmd.Flags().StringSlice("glob", []string{}, "Only import entries whose name or desktop file ID matches one of the given globs")
+	importDesktopCmd.Flags().Bool("include-hidden", false, "Include entries marked NoDisplay or Hidden")
+	importDesktopCmd.Flags().Bool("dry-run", false, "Preview the shortcuts that would be imported without writing them")
+	importDesktopCmd.Flags().String("user", "all", "Steam user ID to import the shortcuts for")
+	importDesktopCmd.Flags().StringP("api-key", "k", "", "SteamGridDB API Key (env SSM_API_KEY)")
+	importDesktopCmd.Flags().BoolP("download-images", "i", false, "Auto-download artwork from SteamGridDB for each entry (requires SteamGridDB API Key)")
+	importDesktopCmd.Flags().Bool("only-missing", true, "Only download images that do not already exist")
+	importDesktopCmd.Flags().String("style-hero", "", `Optional hero style to download ("alternate" "blurred" "material")`)
+	importDesktopCmd.Flags().String("style-grid", "", `Optional grid style to download ("alternate" "blurred" "white_logo" "material" "no_logo")`)
+	importDesktopCmd.Flags().String("style-icon", "", `Optional icon style to download ("official" "custom")`)
+	importDesktopCmd.Flags().String("style-logo", "", `Optional logo style to download ("official" "white" "black" "custom")`)
+	importDesktopCmd.Flags().String("nsfw", "", `Whether to download NSFW images ("any" "false" "true", default "false")`)
+	importDesktopCmd.Flags().String("humor", "", `Whether to download humor images ("any" "false" "true", default "any")`)
+}
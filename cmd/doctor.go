@@ -0,0 +1,143 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
+	"github.com/spf13/cobra"
+)
+
+// DoctorResult contains the validation results for a single shortcut
+type DoctorResult struct {
+	AppName  string              `json:"name"`
+	Appid    int64               `json:"appid"`
+	Problems []*shortcut.Problem `json:"problems"`
+	Fixed    []*shortcut.Problem `json:"fixed,omitempty"`
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check Steam shortcuts for common problems",
+	Long: `Validates existing Steam shortcuts against Steam's conventions, such as
+quoted and existing executable paths, readable icons and consistent app IDs.
+
+An app id that does not match the executable and name is only reported. Steam
+keeps the original app id when a shortcut is renamed, and play time,
+controller layouts and screenshots belong to it, so --fix never changes it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		fix, _ := cmd.Flags().GetBool("fix")
+
+		// Fetch all users
+		users, err := steam.GetUsers()
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Check to see if we're checking for just one user
+		onlyForUser := cmd.Flags().Lookup("user").Value.String()
+
+		results := map[string][]*DoctorResult{}
+		for _, user := range users {
+			if !steam.HasShortcuts(user) {
+				continue
+			}
+			if onlyForUser != "all" && onlyForUser != user {
+				continue
+			}
+
+			shortcutsPath, _ := steam.GetShortcutsPath(user)
+			shortcuts, err := shortcut.Load(shortcutsPath)
+			if err != nil {
+				ExitError(err, format)
+			}
+
+			// Validate each shortcut
+			results[user] = []*DoctorResult{}
+			changed := false
+			for key, sc := range shortcuts.Shortcuts {
+				sc := sc
+				result := &DoctorResult{AppName: sc.AppName, Appid: sc.Appid}
+				if fix {
+					result.Fixed, _ = shortcut.Fix(&sc)
+					if len(result.Fixed) > 0 {
+						shortcuts.Shortcuts[key] = sc
+						changed = true
+					}
+				}
+				result.Problems = shortcut.Validate(&sc)
+				if len(result.Problems) == 0 && len(result.Fixed) == 0 {
+					continue
+				}
+				results[user] = append(results[user], result)
+			}
+
+			// Write the changes
+			if changed {
+				err = shortcut.Save(shortcuts, shortcutsPath)
+				if err != nil {
+					ExitError(err, format)
+				}
+			}
+		}
+
+		// Print the output
+		printOutput(results, func() {
+			for user, userResults := range results {
+				fmt.Println("User:", user)
+				if len(userResults) == 0 {
+					fmt.Println("  No problems found")
+				}
+				for _, result := range userResults {
+					fmt.Printf("  %v (%v)\n", result.AppName, result.Appid)
+					for _, problem := range result.Fixed {
+						fmt.Println("    Fixed:", problem)
+					}
+					for _, problem := range result.Problems {
+						if problem.Info {
+							fmt.Println("    Note:", problem)
+							continue
+						}
+						fixable := ""
+						if problem.Fixable {
+							fixable = " (fixable with --fix)"
+						}
+						fmt.Printf("    Problem: %v%v\n", problem, fixable)
+					}
+				}
+			}
+		}, "user")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().Bool("fix", false, "Automatically fix problems where possible")
+	doctorCmd.Flags().String("user", "all", "Steam user ID to check shortcuts for")
+}
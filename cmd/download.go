@@ -24,7 +24,6 @@ SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"path"
 	"path/filepath"
@@ -34,6 +33,7 @@ import (
 	"github.com/shadowblip/steam-shortcut-manager/pkg/image/kitty"
 	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
 	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam/library"
 	"github.com/shadowblip/steam-shortcut-manager/pkg/steamgriddb"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -76,25 +76,63 @@ var downloadCmd = &cobra.Command{
 		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
 
 		// Ensure we have a SteamGridDB API Key
-		apiKey, _ := cmd.Flags().GetString("api-key")
+		apiKey := getAPIKey(cmd.Flags())
 		if apiKey == "" {
-			cmd.Help()
-			ExitError(fmt.Errorf("API key is required"), format)
+			if format == "term" {
+				cmd.Help()
+			}
+			ExitError(newCommandError(ErrAuth, fmt.Errorf("API key is required (use --api-key or the login command)")), format)
 		}
 
 		// Create a SteamGridDB client
-		client := steamgriddb.NewClient(apiKey)
+		client, err := newGridDBClient(cmd.Flags(), apiKey)
+		if err != nil {
+			ExitError(err, format)
+		}
 
 		// Get all steam users
 		users, err := steam.GetUsers()
 		if err != nil {
 			ExitError(err, format)
 		}
+		opts := newDownloadOptions(cmd.Flags())
+		appId, _ := cmd.Flags().GetInt("app-id")
 
-		// Download for all users
+		// Build the list of installed Steam games if requested
+		var steamApps []*library.Game
+		if ok, _ := cmd.Flags().GetBool("steam-games"); ok {
+			steamApps, err = getSteamAppsToDownload(args, appId)
+			if err != nil {
+				ExitError(err, format)
+			}
+		}
+
+		// Download for all users. Images of Steam games are downloaded once
+		// and linked for every other user.
 		var errors error
 		var results = map[string]map[string]map[string]string{}
+		steamAppImages := map[string]map[string]string{}
 		for _, user := range users {
+			// Download images for installed Steam games
+			if steamApps != nil {
+				results[user] = map[string]map[string]string{}
+				for _, app := range steamApps {
+					var downloaded map[string]string
+					if images, ok := steamAppImages[app.AppID]; ok {
+						downloaded, err = linkDownloadedImages(user, app.AppID, images, opts)
+					} else {
+						downloaded, err = downloadSteamAppImages(client, user, app, opts)
+						steamAppImages[app.AppID] = downloaded
+					}
+					if err != nil {
+						DebugPrintln("Error downloading images:", err)
+						errors = multierror.Append(errors, fmt.Errorf("%v: %v", app.Name, err))
+					}
+					results[user][app.AppID] = downloaded
+				}
+				continue
+			}
+
 			// Build a list of shortcuts we're going to download images for
 			toDownload := []*shortcut.Shortcut{}
 
@@ -108,7 +146,6 @@ var downloadCmd = &cobra.Command{
 				errors = multierror.Append(errors, err)
 				continue
 			}
-			appId, _ := cmd.Flags().GetInt("app-id")
 
 			// If a shortcut name was specified, use that.
 			// NOTE: This is awful. Please forgive me
@@ -137,6 +174,7 @@ var downloadCmd = &cobra.Command{
 				} else {
 					// Otherwise download for all shortcuts
 					for _, sc := range shortcuts.Shortcuts {
+						sc := sc
 						toDownload = append(toDownload, &sc)
 					}
 				}
@@ -145,12 +183,7 @@ var downloadCmd = &cobra.Command{
 			// TODO: Cache and symlink instead of downloading for each user
 			results[user] = map[string]map[string]string{}
 			for _, sc := range toDownload {
-				// Check that we have an API key
-				apiKey, _ := cmd.Flags().GetString("api-key")
-				if apiKey == "" {
-					ExitError(fmt.Errorf("no API key specified"), format)
-				}
-				downloaded, err := downloadImages(client, user, sc)
+				downloaded, err := downloadImages(client, user, sc, opts)
 				if err != nil {
 					DebugPrintln("Error downloading images:", err)
 					errors = multierror.Append(errors, err)
@@ -158,13 +191,9 @@ var downloadCmd = &cobra.Command{
 				results[user][fmt.Sprintf("%v", sc.Appid)] = downloaded
 			}
 		}
-		if errors != nil {
-			ExitError(errors, format)
-		}
 
 		// Print the output
-		switch format {
-		case "term":
+		printOutput(results, func() {
 			for user, apps := range results {
 				fmt.Println("User:", user)
 				for appId, downloads := range apps {
@@ -175,33 +204,208 @@ var downloadCmd = &cobra.Command{
 					}
 				}
 			}
-		case "json":
-			out, err := json.MarshalIndent(results, "", "  ")
+		}, "user", "app", "image", "path")
+
+		// Report the images that could not be downloaded after the ones that
+		// were
+		if errors != nil {
+			ExitError(errors, format)
+		}
+	},
+}
+
+// chimeraDownloadCmd represents the chimera download command
+var chimeraDownloadCmd = &cobra.Command{
+	Use:   "download --api-key=<key> [name...]",
+	Short: "Download SteamGridDB images for Chimera shortcuts",
+	Long: `Downloads the poster, banner, background, logo and icon images from
+SteamGridDB for the given Chimera shortcuts, or for all shortcuts of the
+platform (or of every platform with --all) if no names are given. The
+shortcuts are updated with the paths to the new images.
+
+Existing images are replaced with new downloads. With --only-missing, only
+images that are not set are downloaded, and image files that already exist
+are reused unless --refresh is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		if !chimera.HasChimera() {
+			ExitError(fmt.Errorf("no chimera config found at %v", chimera.GetConfigDir()), format)
+		}
+
+		// Ensure we have a SteamGridDB API Key
+		apiKey := getAPIKey(cmd.Flags())
+		if apiKey == "" {
+			if format == "term" {
+				cmd.Help()
+			}
+			ExitError(newCommandError(ErrAuth, fmt.Errorf("API key is required (use --api-key or the login command)")), format)
+		}
+		client, err := newGridDBClient(cmd.Flags(), apiKey)
+		if err != nil {
+			ExitError(err, format)
+		}
+		opts := newDownloadOptions(cmd.Flags())
+		if !opts.onlyMissing {
+			opts.refresh = true
+		}
+
+		// Get the platforms to download images for
+		platforms := []*chimera.Platform{}
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			names, err := chimera.GetShortcutsPlatforms()
 			if err != nil {
 				ExitError(err, format)
 			}
-			fmt.Println(string(out))
-		default:
-			panic("unknown output format: " + format)
+			for _, name := range names {
+				platform, err := chimera.GetPlatform(name)
+				if err != nil {
+					DebugPrintln("Skipping shortcuts file of unknown platform:", name)
+					continue
+				}
+				platforms = append(platforms, platform)
+			}
+		} else {
+			platforms = append(platforms, getChimeraPlatform(format))
 		}
 
+		// Download images for each selected shortcut
+		var errors error
+		found := map[string]bool{}
+		results := map[string]map[string]map[string]string{}
+		for _, platform := range platforms {
+			if !chimera.HasShortcutsFile(platform.Name) {
+				continue
+			}
+			shortcutsFile := chimera.GetShortcutsFile(platform.Name)
+			shortcuts, err := chimera.LoadShortcuts(shortcutsFile)
+			if err != nil {
+				ExitError(err, format)
+			}
+
+			changed := false
+			results[platform.Name] = map[string]map[string]string{}
+			for _, sc := range shortcuts {
+				if len(args) > 0 && !contains(args, sc.Name) {
+					continue
+				}
+				found[sc.Name] = true
+
+				downloaded, err := downloadChimeraImages(client, platform, sc, "", opts)
+				if err != nil {
+					DebugPrintln("Error downloading images:", err)
+					errors = multierror.Append(errors, fmt.Errorf("%v: %v", sc.Name, err))
+				}
+				for imageType, path := range downloaded {
+					sc.SetImage(imageType, path)
+					changed = true
+				}
+				results[platform.Name][sc.Name] = downloaded
+			}
+
+			// Write the new image paths
+			if !changed {
+				continue
+			}
+			err = chimera.SaveShortcuts(shortcutsFile, shortcuts)
+			if err != nil {
+				ExitError(err, format)
+			}
+		}
+		for _, name := range args {
+			if !found[name] {
+				errors = multierror.Append(errors, fmt.Errorf("no chimera shortcut found with name: %v", name))
+			}
+		}
+
+		// Print the output
+		printOutput(results, func() {
+			for platform, shortcuts := range results {
+				for name, downloads := range shortcuts {
+					fmt.Printf("%v (%v)\n", name, platform)
+					for kind, path := range downloads {
+						fmt.Printf("  %v: %v\n", kind, path)
+						kitty.Display(path)
+					}
+				}
+			}
+		}, "platform", "name", "image", "path")
+		if errors != nil {
+			ExitError(errors, format)
+		}
 	},
 }
 
+// downloadOptions control which images are downloaded from SteamGridDB
+type downloadOptions struct {
+	onlyMissing bool
+	refresh     bool
+	styleGrid   string
+	styleHero   string
+	styleLogo   string
+	styleIcon   string
+}
+
+// newDownloadOptions will return download options from the given command-line
+// flags. Styles that are not flags of the command use their configured value,
+// and other flags that are not defined will use their zero value.
+func newDownloadOptions(flags *pflag.FlagSet) *downloadOptions {
+	opts := &downloadOptions{}
+	opts.onlyMissing, _ = flags.GetBool("only-missing")
+	opts.refresh, _ = flags.GetBool("refresh")
+	opts.styleGrid = getFlagOrConfig(flags, "style-grid")
+	opts.styleHero = getFlagOrConfig(flags, "style-hero")
+	opts.styleLogo = getFlagOrConfig(flags, "style-logo")
+	opts.styleIcon = getFlagOrConfig(flags, "style-icon")
+	return opts
+}
+
+// artworkSource identifies an app on SteamGridDB, either by its SteamGridDB
+// game ID or by its ID on another platform (e.g. a Steam app ID).
+type artworkSource struct {
+	client   *steamgriddb.Client
+	platform string
+	id       string
+}
+
+func (a *artworkSource) grids(filters ...steamgriddb.FilterGrid) (*steamgriddb.GridResponse, error) {
+	if a.platform != "" {
+		return a.client.GetGridsByPlatform(a.platform, a.id, filters...)
+	}
+	return a.client.GetGrids(a.id, filters...)
+}
+
+func (a *artworkSource) heroes(filters ...steamgriddb.FilterHeroes) (*steamgriddb.HeroesResponse, error) {
+	if a.platform != "" {
+		return a.client.GetHeroesByPlatform(a.platform, a.id, filters...)
+	}
+	return a.client.GetHeroes(a.id, filters...)
+}
+
+func (a *artworkSource) logos(filters ...steamgriddb.FilterLogos) (*steamgriddb.LogosResponse, error) {
+	if a.platform != "" {
+		return a.client.GetLogosByPlatform(a.platform, a.id, filters...)
+	}
+	return a.client.GetLogos(a.id, filters...)
+}
+
+func (a *artworkSource) icons(filters ...steamgriddb.FilterIcons) (*steamgriddb.IconsResponse, error) {
+	if a.platform != "" {
+		return a.client.GetIconsByPlatform(a.platform, a.id, filters...)
+	}
+	return a.client.GetIcons(a.id, filters...)
+}
+
 // downloadImages will download images for the given shortcut
 // TODO: Handle errors better
-func downloadImages(client *steamgriddb.Client, user string, sc *shortcut.Shortcut) (map[string]string, error) {
+func downloadImages(client *steamgriddb.Client, user string, sc *shortcut.Shortcut, opts *downloadOptions) (map[string]string, error) {
 	DebugPrintln("Downloading images for:", sc.AppName)
-	// This map will contain the paths to our downloaded images
-	downloaded := map[string]string{}
-	var errors error
+	steamAppID := fmt.Sprintf("%v", sc.Appid)
 
-	// Get the image directory for the user.
-	gridDir, err := steam.GetImagesDir(user)
-	if err != nil {
-		return nil, err
+	// Skip searching if there is nothing to download
+	if opts.onlyMissing && len(missingImages(user, steamAppID)) == 0 {
+		DebugPrintln("All images already exist for:", sc.AppName)
+		return map[string]string{}, nil
 	}
-	DebugPrintln("Discovered images dir:", gridDir)
 
 	// Search for the app images
 	results, err := client.Search(sc.AppName)
@@ -217,204 +421,368 @@ func downloadImages(client *steamgriddb.Client, user string, sc *shortcut.Shortc
 	// Get the first result
 	// TODO: Enable showing different results?
 	gameID := fmt.Sprintf("%v", results.Data[0].ID)
-	steamAppID := fmt.Sprintf("%v", sc.Appid)
+	source := &artworkSource{client: client, id: gameID}
 
-	// Download the grid images. Steam uses a portrait and landscape image
-	// that is displays in the library.
-	grids, err := client.GetGrids(gameID)
-	if err != nil {
-		errors = multierror.Append(errors, err)
-		grids = &steamgriddb.GridResponse{Data: []steamgriddb.GridResponseData{}}
-	}
-	portraitGrids := steamgriddb.FilterGridVertical()(grids)
-	for _, data := range portraitGrids {
-		ext := filepath.Ext(data.URL)
-		imgFile := path.Join(gridDir, fmt.Sprintf("%sp%s", steamAppID, ext))
-		DebugPrintln("Downloading portrait grid image...")
-		err := client.CachedDownload(data.URL, imgFile)
+	return downloadGridImages(source, user, steamAppID, opts)
+}
+
+// downloadSteamAppImages will download images for the given installed Steam
+// game using its Steam app ID.
+func downloadSteamAppImages(client *steamgriddb.Client, user string, app *library.Game, opts *downloadOptions) (map[string]string, error) {
+	DebugPrintln("Downloading images for Steam game:", app.Name)
+	source := &artworkSource{client: client, platform: "steam", id: app.AppID}
+	return downloadGridImages(source, user, app.AppID, opts)
+}
+
+// linkDownloadedImages will link the images that were downloaded for another
+// user into the user's grid directory. With --only-missing, images the user
+// already has are kept.
+func linkDownloadedImages(user, steamAppID string, images map[string]string, opts *downloadOptions) (map[string]string, error) {
+	var errors error
+	missing := missingImages(user, steamAppID)
+	linked := map[string]string{}
+	for name, src := range images {
+		kind := downloadedImageTypes[name]
+		if opts.onlyMissing && !missing[kind] {
+			continue
+		}
+		DebugPrintln("Linking", kind, "image for user", user)
+		dst, err := steam.LinkImage(user, steamAppID, kind, src)
 		if err != nil {
 			errors = multierror.Append(errors, err)
 			continue
 		}
-		downloaded["gridP"] = imgFile
-		break
+		linked[name] = dst
+	}
+	return linked, errors
+}
+
+// missingImages will return the image types that do not exist in the grid
+// directory for the given app id.
+func missingImages(user, steamAppID string) map[steam.ImageType]bool {
+	missing := map[steam.ImageType]bool{}
+	for _, kind := range steam.ImageTypes {
+		if _, err := steam.GetImage(user, steamAppID, kind); err != nil {
+			missing[kind] = true
+		}
 	}
-	landscapeGrids := steamgriddb.FilterGridHorizontal()(grids)
-	for _, data := range landscapeGrids {
-		ext := filepath.Ext(data.URL)
-		imgFile := path.Join(gridDir, fmt.Sprintf("%s%s", steamAppID, ext))
-		DebugPrintln("Downloading landscape grid image...")
-		err := client.CachedDownload(data.URL, imgFile)
+	return missing
+}
+
+// downloadGridImages will download images from the given source into the
+// user's grid directory for the given Steam app id.
+func downloadGridImages(source *artworkSource, user, steamAppID string, opts *downloadOptions) (map[string]string, error) {
+	client := source.client
+	// This map will contain the paths to our downloaded images
+	downloaded := map[string]string{}
+	var errors error
+
+	// Get the image directory for the user.
+	gridDir, err := steam.GetImagesDir(user)
+	if err != nil {
+		return nil, err
+	}
+	DebugPrintln("Discovered images dir:", gridDir)
+
+	// Determine which images we need to download
+	wanted := map[steam.ImageType]bool{}
+	for _, kind := range steam.ImageTypes {
+		wanted[kind] = true
+	}
+	if opts.onlyMissing {
+		wanted = missingImages(user, steamAppID)
+	}
+
+	// Download the grid images. Steam uses a portrait and landscape image
+	// that is displays in the library.
+	if wanted[steam.ImagePortrait] || wanted[steam.ImageLandscape] {
+		gridFilters := []steamgriddb.FilterGrid{}
+		if opts.styleGrid != "" {
+			gridFilters = append(gridFilters, steamgriddb.FilterGridStyle(opts.styleGrid))
+		}
+		grids, err := source.grids(gridFilters...)
 		if err != nil {
 			errors = multierror.Append(errors, err)
-			continue
+			grids = &steamgriddb.GridResponse{Data: []steamgriddb.GridResponseData{}}
+		}
+		portraitGrids := steamgriddb.FilterGridVertical()(grids)
+		for _, data := range portraitGrids {
+			if !wanted[steam.ImagePortrait] {
+				break
+			}
+			ext := filepath.Ext(data.URL)
+			imgFile := path.Join(gridDir, fmt.Sprintf("%sp%s", steamAppID, ext))
+			DebugPrintln("Downloading portrait grid image...")
+			err := client.CachedDownload(data.URL, imgFile)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				continue
+			}
+			downloaded["gridP"] = imgFile
+			break
+		}
+		landscapeGrids := steamgriddb.FilterGridHorizontal()(grids)
+		for _, data := range landscapeGrids {
+			if !wanted[steam.ImageLandscape] {
+				break
+			}
+			ext := filepath.Ext(data.URL)
+			imgFile := path.Join(gridDir, fmt.Sprintf("%s%s", steamAppID, ext))
+			DebugPrintln("Downloading landscape grid image...")
+			err := client.CachedDownload(data.URL, imgFile)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				continue
+			}
+			downloaded["gridL"] = imgFile
+			break
 		}
-		downloaded["gridL"] = imgFile
-		break
 	}
 
 	// Download the hero image. The hero image is used as a banner at the
 	// top of the app page in the Steam UI.
-	heroes, err := client.GetHeroes(gameID)
-	if err != nil {
-		errors = multierror.Append(errors, err)
-		heroes = &steamgriddb.HeroesResponse{Data: []steamgriddb.ImageResponseData{}}
-	}
-	for _, data := range heroes.Data {
-		ext := filepath.Ext(data.URL)
-		imgFile := path.Join(gridDir, fmt.Sprintf("%s_hero%s", steamAppID, ext))
-		DebugPrintln("Downloading hero grid image...")
-		err := client.CachedDownload(data.URL, imgFile)
+	if wanted[steam.ImageHero] {
+		heroFilters := []steamgriddb.FilterHeroes{}
+		if opts.styleHero != "" {
+			heroFilters = append(heroFilters, steamgriddb.FilterHeroesStyle(opts.styleHero))
+		}
+		heroes, err := source.heroes(heroFilters...)
 		if err != nil {
 			errors = multierror.Append(errors, err)
-			continue
+			heroes = &steamgriddb.HeroesResponse{Data: []steamgriddb.ImageResponseData{}}
+		}
+		for _, data := range heroes.Data {
+			ext := filepath.Ext(data.URL)
+			imgFile := path.Join(gridDir, fmt.Sprintf("%s_hero%s", steamAppID, ext))
+			DebugPrintln("Downloading hero grid image...")
+			err := client.CachedDownload(data.URL, imgFile)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				continue
+			}
+			downloaded["hero"] = imgFile
+			break
 		}
-		downloaded["hero"] = imgFile
-		break
 	}
 
 	// Download the logo image. Logo images are used in the Steam overlay menu.
-	logos, err := client.GetLogos(gameID)
-	if err != nil {
-		errors = multierror.Append(errors, err)
-		logos = &steamgriddb.LogosResponse{Data: []steamgriddb.ImageResponseData{}}
-	}
-	for _, data := range logos.Data {
-		ext := filepath.Ext(data.URL)
-		imgFile := path.Join(gridDir, fmt.Sprintf("%s_logo%s", steamAppID, ext))
-		DebugPrintln("Downloading logo grid image...")
-		err := client.CachedDownload(data.URL, imgFile)
+	if wanted[steam.ImageLogo] {
+		logoFilters := []steamgriddb.FilterLogos{}
+		if opts.styleLogo != "" {
+			logoFilters = append(logoFilters, steamgriddb.FilterLogosStyle(opts.styleLogo))
+		}
+		logos, err := source.logos(logoFilters...)
 		if err != nil {
 			errors = multierror.Append(errors, err)
-			continue
+			logos = &steamgriddb.LogosResponse{Data: []steamgriddb.ImageResponseData{}}
+		}
+		for _, data := range logos.Data {
+			ext := filepath.Ext(data.URL)
+			imgFile := path.Join(gridDir, fmt.Sprintf("%s_logo%s", steamAppID, ext))
+			DebugPrintln("Downloading logo grid image...")
+			err := client.CachedDownload(data.URL, imgFile)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				continue
+			}
+			downloaded["logo"] = imgFile
+			break
 		}
-		downloaded["logo"] = imgFile
-		break
 	}
 
 	// Download the icon image. Icon images are used in some part of the UI.
-	icons, err := client.GetIcons(gameID)
-	if err != nil {
-		errors = multierror.Append(errors, err)
-		icons = &steamgriddb.IconsResponse{Data: []steamgriddb.ImageResponseData{}}
-	}
-	for _, data := range icons.Data {
-		ext := filepath.Ext(data.URL)
-		imgFile := path.Join(gridDir, fmt.Sprintf("%s-icon%s", steamAppID, ext))
-		err := client.CachedDownload(data.URL, imgFile)
+	if wanted[steam.ImageIcon] {
+		iconFilters := []steamgriddb.FilterIcons{}
+		if opts.styleIcon != "" {
+			iconFilters = append(iconFilters, steamgriddb.FilterIconsStyle(opts.styleIcon))
+		}
+		icons, err := source.icons(iconFilters...)
 		if err != nil {
 			errors = multierror.Append(errors, err)
-			continue
+			icons = &steamgriddb.IconsResponse{Data: []steamgriddb.ImageResponseData{}}
+		}
+		for _, data := range icons.Data {
+			ext := filepath.Ext(data.URL)
+			imgFile := path.Join(gridDir, fmt.Sprintf("%s-icon%s", steamAppID, ext))
+			err := client.CachedDownload(data.URL, imgFile)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				continue
+			}
+			downloaded["icon"] = imgFile
+			break
 		}
-		downloaded["icon"] = imgFile
-		break
 	}
 
 	return downloaded, errors
 }
 
-// downloadChimeraImages will download images for the given shortcut. This
-// will return the paths of each type of image we downloaded.
-// TODO: Handle errors better
-func downloadChimeraImages(flags *pflag.FlagSet, client *steamgriddb.Client, platform string, sc *chimera.Shortcut) (map[string]string, error) {
-	// Get the download directories
-	posterDir := path.Join(chimera.PosterDir, platform)
-	bannerDir := path.Join(chimera.BannerDir, platform)
-	logoDir := path.Join(chimera.LogoDir, platform)
-	backgroundDir := path.Join(chimera.BackgroundDir, platform)
-
+// downloadChimeraImages will download images for the given Chimera shortcut
+// into the Chimera images directory of its platform, named after the given
+// platform ID of the game if it is known. This will return the paths of each
+// type of image we downloaded, along with any errors for the images that could
+// not be downloaded.
+func downloadChimeraImages(client *steamgriddb.Client, platform *chimera.Platform, sc *chimera.Shortcut, id string, opts *downloadOptions) (map[string]string, error) {
+	DebugPrintln("Downloading images for Chimera shortcut:", sc.Name)
 	// This map will contain the paths to our downloaded images
 	downloaded := map[string]string{}
+	var errors error
 
-	// Set the base file name based on platform
-	var fileBaseName string
-
-	// Shortcuts for different platforms are handled differently
-	switch platform {
-	case "flathub":
-		fileBaseName = flags.Lookup("flatpak-id").Value.String()
-	default:
-		fileBaseName = sc.Name
+	// Determine which images we need to download
+	wanted := []string{}
+	for _, imageType := range chimera.ImageTypes {
+		if opts.onlyMissing && sc.HasImage(imageType) {
+			continue
+		}
+		wanted = append(wanted, imageType)
+	}
+	if len(wanted) == 0 {
+		DebugPrintln("All images already exist for:", sc.Name)
+		return downloaded, nil
 	}
 
 	// Search for the app images
 	results, err := client.Search(sc.Name)
 	if err != nil {
-		return downloaded, nil
+		return nil, err
 	}
-	// TODO: Log or return no image results
 	if len(results.Data) == 0 {
-		return downloaded, nil
+		return nil, fmt.Errorf("no results found for %v", sc.Name)
 	}
+	DebugPrintln(fmt.Sprintf("Found %v results for %s", len(results.Data), sc.Name))
 
 	// Get the first result
 	// TODO: Enable showing different results?
 	gameID := fmt.Sprintf("%v", results.Data[0].ID)
+	source := &artworkSource{client: client, id: gameID}
 
-	// Download the grid image. Grid images are "poster" Chimera images
-	grids, err := client.GetGrids(gameID)
-	if err != nil {
-		return nil, err
-	}
-	posters := steamgriddb.FilterGridVertical()(grids)
-	for _, data := range posters {
-		ext := filepath.Ext(data.URL)
-		imgFile := path.Join(posterDir, fmt.Sprintf("%s%s", fileBaseName, ext))
-		err := client.CachedDownload(data.URL, imgFile)
+	// Images are named after the platform's ID for the game if it is known
+	fileBaseName := platform.ImageName(sc, id)
+
+	for _, imageType := range wanted {
+		urls, err := getChimeraImageURLs(source, imageType, opts)
 		if err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("%v: %w", imageType, err))
+			continue
+		}
+		if len(urls) == 0 {
+			errors = multierror.Append(errors, fmt.Errorf("no %v images found for %v", imageType, sc.Name))
 			continue
 		}
-		downloaded["poster"] = imgFile
-		break
+		for _, url := range urls {
+			ext := filepath.Ext(url)
+			imgFile := path.Join(chimera.GetImageDir(imageType, platform.Name), fileBaseName+ext)
+			DebugPrintln("Downloading", imageType, "image...")
+			if opts.refresh {
+				err = client.Download(url, imgFile)
+			} else {
+				err = client.CachedDownload(url, imgFile)
+			}
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				continue
+			}
+			downloaded[imageType] = imgFile
+			break
+		}
 	}
 
-	// We also need to get the "banner" image which is a grid with
-	// a landscape orientation.
-	banners := steamgriddb.FilterGridHorizontal()(grids)
-	for _, data := range banners {
-		ext := filepath.Ext(data.URL)
-		imgFile := path.Join(bannerDir, fmt.Sprintf("%s%s", fileBaseName, ext))
-		err := client.CachedDownload(data.URL, imgFile)
+	return downloaded, errors
+}
+
+// getChimeraImageURLs will return the URLs of the SteamGridDB images that can
+// be used for the given Chimera image type, in order of preference.
+func getChimeraImageURLs(source *artworkSource, imageType string, opts *downloadOptions) ([]string, error) {
+	urls := []string{}
+	switch imageType {
+	// Grid images are "poster" and "banner" Chimera images
+	case chimera.ImagePoster, chimera.ImageBanner:
+		gridFilters := []steamgriddb.FilterGrid{}
+		if opts.styleGrid != "" {
+			gridFilters = append(gridFilters, steamgriddb.FilterGridStyle(opts.styleGrid))
+		}
+		grids, err := source.grids(gridFilters...)
 		if err != nil {
-			continue
+			return nil, err
+		}
+		orientation := steamgriddb.FilterGridVertical()
+		if imageType == chimera.ImageBanner {
+			orientation = steamgriddb.FilterGridHorizontal()
+		}
+		for _, data := range orientation(grids) {
+			urls = append(urls, data.URL)
 		}
-		downloaded["banner"] = imgFile
-		break
-	}
 
-	// Download the hero image. Hero images are "background" Chimera images
-	heroes, err := client.GetHeroes(gameID)
-	if err != nil {
-		return nil, err
-	}
-	for _, data := range heroes.Data {
-		ext := filepath.Ext(data.URL)
-		imgFile := path.Join(backgroundDir, fmt.Sprintf("%s%s", fileBaseName, ext))
-		err := client.CachedDownload(data.URL, imgFile)
+	// Hero images are "background" Chimera images
+	case chimera.ImageBackground:
+		heroFilters := []steamgriddb.FilterHeroes{}
+		if opts.styleHero != "" {
+			heroFilters = append(heroFilters, steamgriddb.FilterHeroesStyle(opts.styleHero))
+		}
+		heroes, err := source.heroes(heroFilters...)
 		if err != nil {
-			continue
+			return nil, err
+		}
+		for _, data := range heroes.Data {
+			urls = append(urls, data.URL)
+		}
+
+	case chimera.ImageLogo:
+		logoFilters := []steamgriddb.FilterLogos{}
+		if opts.styleLogo != "" {
+			logoFilters = append(logoFilters, steamgriddb.FilterLogosStyle(opts.styleLogo))
+		}
+		logos, err := source.logos(logoFilters...)
+		if err != nil {
+			return nil, err
+		}
+		for _, data := range logos.Data {
+			urls = append(urls, data.URL)
+		}
+
+	case chimera.ImageIcon:
+		iconFilters := []steamgriddb.FilterIcons{}
+		if opts.styleIcon != "" {
+			iconFilters = append(iconFilters, steamgriddb.FilterIconsStyle(opts.styleIcon))
+		}
+		icons, err := source.icons(iconFilters...)
+		if err != nil {
+			return nil, err
+		}
+		for _, data := range icons.Data {
+			urls = append(urls, data.URL)
 		}
-		downloaded["background"] = imgFile
-		break
 	}
 
-	// Download the logo image. Logo images are "logo" Chimera images
-	logos, err := client.GetLogos(gameID)
+	return urls, nil
+}
+
+// getSteamAppsToDownload will return the installed Steam games to download
+// images for, optionally limited to the given name or app id.
+func getSteamAppsToDownload(args []string, appId int) ([]*library.Game, error) {
+	apps, err := steam.GetInstalledGames()
 	if err != nil {
 		return nil, err
 	}
-	for _, data := range logos.Data {
-		ext := filepath.Ext(data.URL)
-		imgFile := path.Join(logoDir, fmt.Sprintf("%s%s", fileBaseName, ext))
-		err := client.CachedDownload(data.URL, imgFile)
-		if err != nil {
+
+	toDownload := []*library.Game{}
+	for _, app := range apps {
+		if app.IsTool() {
+			continue
+		}
+		if len(args) > 0 && app.Name != args[0] {
 			continue
 		}
-		downloaded["logo"] = imgFile
-		break
+		if appId != 0 && app.AppID != fmt.Sprintf("%v", appId) {
+			continue
+		}
+		toDownload = append(toDownload, app)
+	}
+	if len(toDownload) == 0 && (len(args) > 0 || appId != 0) {
+		return nil, fmt.Errorf("no installed Steam game found")
 	}
 
-	return downloaded, nil
+	return toDownload, nil
 }
 
 func init() {
@@ -425,8 +793,28 @@ func init() {
 	// Cobra supports Persistent Flags which will work for this command
 	// and all subcommands, e.g.:
 	downloadCmd.Flags().IntP("app-id", "i", 0, "Steam App ID to download images for")
+	downloadCmd.Flags().Bool("steam-games", false, "Download images for installed Steam games instead of shortcuts")
+	downloadCmd.Flags().Bool("only-missing", false, "Only download images that do not already exist")
+	downloadCmd.Flags().String("style-hero", "", `Optional hero style to download ("alternate" "blurred" "material")`)
+	downloadCmd.Flags().String("style-grid", "", `Optional grid style to download ("alternate" "blurred" "white_logo" "material" "no_logo")`)
+	downloadCmd.Flags().String("style-icon", "", `Optional icon style to download ("official" "custom")`)
+	downloadCmd.Flags().String("style-logo", "", `Optional logo style to download ("official" "white" "black" "custom")`)
+	downloadCmd.Flags().String("nsfw", "", `Whether to download NSFW images ("any" "false" "true", default "false")`)
+	downloadCmd.Flags().String("humor", "", `Whether to download humor images ("any" "false" "true", default "any")`)
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// downloadCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+	chimeraCmd.AddCommand(chimeraDownloadCmd)
+	chimeraDownloadCmd.Flags().StringP("api-key", "k", "", "SteamGridDB API Key (env SSM_API_KEY)")
+	chimeraDownloadCmd.Flags().Bool("all", false, "Download images for the shortcuts of every platform")
+	chimeraDownloadCmd.Flags().Bool("only-missing", false, "Only download images that are not set or do not exist")
+	chimeraDownloadCmd.Flags().Bool("refresh", false, "Download images again even if the image file already exists")
+	chimeraDownloadCmd.Flags().String("style-hero", "", `Optional background (hero) style to download ("alternate" "blurred" "material")`)
+	chimeraDownloadCmd.Flags().String("style-grid", "", `Optional poster and banner (grid) style to download ("alternate" "blurred" "white_logo" "material" "no_logo")`)
+	chimeraDownloadCmd.Flags().String("style-icon", "", `Optional icon style to download ("official" "custom")`)
+	chimeraDownloadCmd.Flags().String("style-logo", "", `Optional logo style to download ("official" "white" "black" "custom")`)
+	chimeraDownloadCmd.Flags().String("nsfw", "", `Whether to download NSFW images ("any" "false" "true", default "false")`)
+	chimeraDownloadCmd.Flags().String("humor", "", `Whether to download humor images ("any" "false" "true", default "any")`)
 }
@@ -0,0 +1,430 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/chimera"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
+	"github.com/spf13/cobra"
+)
+
+// editCmd represents the edit command
+var editCmd = &cobra.Command{
+	Use:   "edit <name|appid>",
+	Short: "Edit an existing Steam shortcut",
+	Long: `Edits an existing Steam shortcut. Only the given flags are changed. If the
+name or executable changes, the shortcut gets a new app id and its artwork,
+collections and compat tool mapping are moved to it.
+
+Use "--compat-tool none" to stop running the shortcut through a compat tool.
+Steam rewrites config.vdf when it exits, so it should not be running when
+changing the compat tool.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		nameOrID := args[0]
+
+		// Resolve the compat tool first so we fail before changing anything
+		var compatTool string
+		setCompatTool := cmd.Flags().Changed("compat-tool")
+		if setCompatTool {
+			name, _ := cmd.Flags().GetString("compat-tool")
+			var err error
+			compatTool, err = resolveCompatTool(name)
+			if err != nil {
+				ExitError(err, format)
+			}
+		}
+
+		// Fetch all users
+		users, err := steam.GetUsers()
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Check to see if we're editing for just one user
+		onlyForUser := cmd.Flags().Lookup("user").Value.String()
+
+		// Edit the shortcut for each user that has it
+		var errors error
+		results := map[string]*shortcut.Shortcut{}
+		for _, user := range users {
+			if !steam.HasShortcuts(user) {
+				continue
+			}
+			if onlyForUser != "all" && onlyForUser != user {
+				continue
+			}
+
+			shortcutsPath, _ := steam.GetShortcutsPath(user)
+			shortcuts, err := shortcut.Load(shortcutsPath)
+			if err != nil {
+				ExitError(err, format)
+			}
+			key, sc, err := shortcuts.Lookup(nameOrID)
+			if err != nil {
+				continue
+			}
+			oldAppID := fmt.Sprintf("%v", sc.Appid)
+
+			editShortcutFromFlags(cmd, sc)
+
+			// Validate the changed fields before writing them
+			if skip, _ := cmd.Flags().GetBool("skip-validation"); !skip {
+				fixed, remaining := shortcut.FixWith(sc, editValidators(cmd)...)
+				for _, problem := range fixed {
+					DebugPrintln("Fixed shortcut problem:", problem)
+				}
+				if len(remaining) > 0 {
+					var problems error
+					for _, problem := range remaining {
+						problems = multierror.Append(problems, fmt.Errorf("%v", problem))
+					}
+					ExitError(problems, format)
+				}
+			}
+
+			// Steam calculates a new app id if the name or executable changed
+			if cmd.Flags().Changed("name") || cmd.Flags().Changed("exe") {
+				sc.Appid = int64(shortcut.CalculateAppID(sc.Exe, sc.AppName))
+			}
+			newAppID := fmt.Sprintf("%v", sc.Appid)
+
+			// Write the changes
+			shortcuts.Shortcuts[key] = *sc
+			err = shortcut.Save(shortcuts, shortcutsPath)
+			if err != nil {
+				ExitError(err, format)
+			}
+
+			// Move artwork and the compat tool mapping to the new app id once
+			// the shortcut has it, and save again if the icon was moved
+			icon := sc.Icon
+			err = moveShortcutAppID(user, sc, oldAppID)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+			}
+			if sc.Icon != icon {
+				shortcuts.Shortcuts[key] = *sc
+				err = shortcut.Save(shortcuts, shortcutsPath)
+				if err != nil {
+					errors = multierror.Append(errors, err)
+				}
+			}
+
+			// Mirror the new tags into Steam library collections
+			if cmd.Flags().Changed("tags") {
+				err = addToTagCollections(user, sc)
+				if err != nil {
+					errors = multierror.Append(errors, err)
+				}
+			}
+
+			if setCompatTool {
+				err := steam.SetCompatTool(newAppID, compatTool)
+				if err != nil {
+					ExitError(err, format)
+				}
+			}
+			results[user] = sc
+		}
+		if len(results) == 0 {
+			ExitError(fmt.Errorf("%w with name or id: %v", shortcut.ErrNotFound, nameOrID), format)
+		}
+
+		// Print the output
+		printOutput(results, func() {
+			for user, sc := range results {
+				fmt.Printf("Edited %v (%v) for user %v\n", sc.AppName, sc.Appid, user)
+			}
+		}, "user")
+
+		if errors != nil {
+			ExitError(errors, format)
+		}
+	},
+}
+
+// moveShortcutAppID will move the artwork, collections and compat tool
+// mapping of the given shortcut from its old app id to its current one, and
+// update its icon if it is one of the moved images. This is needed
+// whenever the name or executable of a shortcut changes.
+func moveShortcutAppID(user string, sc *shortcut.Shortcut, oldAppID string) error {
+	newAppID := fmt.Sprintf("%v", sc.Appid)
+	if newAppID == oldAppID {
+		return nil
+	}
+	DebugPrintln("App id changed from", oldAppID, "to", newAppID)
+
+	var errors error
+	moved, err := steam.MoveImages(user, oldAppID, newAppID)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	// The icon of a shortcut may point to one of the moved images
+	icon := filepath.Clean(shortcut.Unquote(sc.Icon))
+	for _, file := range moved {
+		DebugPrintln("Moved image to", file)
+		oldFile := filepath.Join(filepath.Dir(file), oldAppID+strings.TrimPrefix(filepath.Base(file), newAppID))
+		if sc.Icon != "" && icon == oldFile {
+			DebugPrintln("Updating icon path to", file)
+			sc.Icon = file
+		}
+	}
+
+	// Keep the shortcut in the collections it was in
+	err = moveCollectionsAppID(user, oldAppID, sc.Appid)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	// The compat tool mapping is shared by all users, so it is only moved
+	// for the first user that has the shortcut.
+	tool, err := steam.GetCompatTool(oldAppID)
+	if err != nil && !os.IsNotExist(err) {
+		errors = multierror.Append(errors, err)
+	}
+	if tool != "" {
+		err = steam.SetCompatTool(newAppID, tool)
+		if err == nil {
+			err = steam.SetCompatTool(oldAppID, "")
+		}
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		}
+	}
+
+	return errors
+}
+
+// moveCollectionsAppID will replace the given old app id with the new one in
+// the user's Steam library collections.
+func moveCollectionsAppID(user, oldAppID string, newAppID int64) error {
+	oldID, err := strconv.ParseInt(oldAppID, 10, 64)
+	if err != nil {
+		return err
+	}
+	collections, err := steam.LoadCollections(user)
+	if err != nil {
+		return err
+	}
+	changed, err := collections.ReplaceApp(oldID, newAppID)
+	if err != nil || changed == 0 {
+		return err
+	}
+	DebugPrintln("Moved app id in", changed, "collections")
+	return collections.Save()
+}
+
+// editValidators will return the validators for the shortcut fields that were
+// changed with flags, so fields that were not touched are left as they are.
+func editValidators(cmd *cobra.Command) []shortcut.Validator {
+	flags := cmd.Flags()
+	validators := []shortcut.Validator{}
+	if flags.Changed("exe") {
+		validators = append(validators, shortcut.ValidateExe)
+	}
+	// The start directory defaults to the directory of the executable
+	if flags.Changed("exe") || flags.Changed("start-dir") {
+		validators = append(validators, shortcut.ValidateStartDir)
+	}
+	if flags.Changed("icon") {
+		validators = append(validators, shortcut.ValidateIcon)
+	}
+	if flags.Changed("tags") {
+		validators = append(validators, shortcut.ValidateTags)
+	}
+	return validators
+}
+
+// editShortcutFromFlags will update the given shortcut with the flags that
+// were explicitly set.
+func editShortcutFromFlags(cmd *cobra.Command, sc *shortcut.Shortcut) {
+	flags := cmd.Flags()
+	getString := func(name string) string {
+		res, _ := flags.GetString(name)
+		return res
+	}
+	getBool := func(name string) int {
+		res, _ := flags.GetBool(name)
+		return boolToInt(res)
+	}
+	if flags.Changed("name") {
+		sc.AppName = getString("name")
+	}
+	if flags.Changed("exe") {
+		sc.Exe = getString("exe")
+	}
+	if flags.Changed("launch-options") {
+		sc.LaunchOptions = getString("launch-options")
+	}
+	if flags.Changed("start-dir") {
+		sc.StartDir = getString("start-dir")
+	}
+	if flags.Changed("shortcut-path") {
+		sc.ShortcutPath = getString("shortcut-path")
+	}
+	if flags.Changed("icon") {
+		sc.Icon = getString("icon")
+	}
+	if flags.Changed("flatpak-id") {
+		sc.FlatpakAppID = getString("flatpak-id")
+	}
+	if flags.Changed("allow-desktop-config") {
+		sc.AllowDesktopConfig = getBool("allow-desktop-config")
+	}
+	if flags.Changed("allow-overlay") {
+		sc.AllowOverlay = getBool("allow-overlay")
+	}
+	if flags.Changed("is-hidden") {
+		sc.IsHidden = getBool("is-hidden")
+	}
+	if flags.Changed("openvr") {
+		sc.OpenVR = getBool("openvr")
+	}
+	if flags.Changed("tags") {
+		tags, _ := flags.GetStringSlice("tags")
+		sc.SetTags(tags)
+	}
+}
+
+// chimeraEditCmd represents the chimera edit command
+var chimeraEditCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Edit an existing Chimera shortcut",
+	Long: `Edits an existing Chimera shortcut on the given platform. Only the given
+flags are changed. Image flags can be set to an empty string to remove the
+image from the shortcut.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		name := args[0]
+		if !chimera.HasChimera() {
+			ExitError(fmt.Errorf("no chimera config found at %v", chimera.GetConfigDir()), format)
+		}
+
+		// Get the platform flag
+		platform := getChimeraPlatform(format)
+
+		// Read from the given shortcuts file
+		shortcutsFile := chimera.GetShortcutsFile(platform.Name)
+		DebugPrintln("Using shortcuts file:", shortcutsFile)
+		shortcuts, err := chimera.LoadShortcuts(shortcutsFile)
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Find the shortcut to edit
+		i := chimera.FindShortcut(shortcuts, name)
+		if i < 0 {
+			ExitError(fmt.Errorf("no chimera shortcut found with name: %v", name), format)
+		}
+		sc := shortcuts[i]
+
+		// Don't allow renaming to the name of another shortcut
+		if newName, _ := cmd.Flags().GetString("name"); cmd.Flags().Changed("name") && newName != name {
+			if chimera.FindShortcut(shortcuts, newName) >= 0 {
+				ExitError(fmt.Errorf("%w: %v", chimera.ErrShortcutExists, newName), format)
+			}
+		}
+		editChimeraShortcutFromFlags(cmd, sc)
+
+		// Write the changes
+		err = chimera.SaveShortcuts(shortcutsFile, shortcuts)
+		if err != nil {
+			ExitError(err, format)
+		}
+		printChimeraShortcut(sc, format)
+	},
+}
+
+// editChimeraShortcutFromFlags will update the given Chimera shortcut with the
+// flags that were explicitly set.
+func editChimeraShortcutFromFlags(cmd *cobra.Command, sc *chimera.Shortcut) {
+	flags := cmd.Flags()
+	getString := func(name string) string {
+		res, _ := flags.GetString(name)
+		return res
+	}
+	if flags.Changed("name") {
+		sc.Name = getString("name")
+	}
+	if flags.Changed("exe") {
+		sc.Cmd = getString("exe")
+	}
+	if flags.Changed("start-dir") {
+		sc.Dir = getString("start-dir")
+	}
+	if flags.Changed("is-hidden") {
+		sc.Hidden, _ = flags.GetBool("is-hidden")
+	}
+	if flags.Changed("tags") {
+		sc.Tags, _ = flags.GetStringSlice("tags")
+	}
+	for _, imageType := range chimera.ImageTypes {
+		if flags.Changed(imageType) {
+			sc.SetImage(imageType, getString(imageType))
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+	chimeraCmd.AddCommand(chimeraEditCmd)
+	editCmd.Flags().String("name", "", "New name of the shortcut")
+	editCmd.Flags().String("exe", "", "New executable of the shortcut")
+	editCmd.Flags().Bool("allow-desktop-config", true, "Allow desktop config")
+	editCmd.Flags().Bool("allow-overlay", true, "Allow steam overlay")
+	editCmd.Flags().Bool("is-hidden", false, "Whether or not the shortcut is hidden")
+	editCmd.Flags().String("flatpak-id", "", "Flatpak ID of the shortcut")
+	editCmd.Flags().String("launch-options", "", "Launch options for the shortcut")
+	editCmd.Flags().Bool("openvr", false, "Use OpenVR for the shortcut")
+	editCmd.Flags().String("shortcut-path", "", "Path to the shortcut file for this application")
+	editCmd.Flags().String("start-dir", "", "Working directory where the app is started")
+	editCmd.Flags().String("icon", "", "Path to the icon to use for this application")
+	editCmd.Flags().StringSlice("tags", []string{}, "Comma-separated list of tags")
+	editCmd.Flags().String("compat-tool", "", "Compatibility tool to run the shortcut with (e.g. proton_experimental), or 'none'")
+	editCmd.Flags().String("user", "all", "Steam user ID to edit the shortcut for")
+	editCmd.Flags().Bool("skip-validation", false, "Write the shortcut without validating or fixing it")
+
+	chimeraEditCmd.Flags().String("name", "", "New name of the shortcut")
+	chimeraEditCmd.Flags().String("exe", "", "New command of the shortcut")
+	chimeraEditCmd.Flags().String("start-dir", "", "Working directory where the app is started")
+	chimeraEditCmd.Flags().Bool("is-hidden", false, "Whether or not the shortcut is hidden")
+	chimeraEditCmd.Flags().StringSlice("tags", []string{}, "Comma-separated list of tags")
+	chimeraEditCmd.Flags().String(chimera.ImagePoster, "", "Path to the poster image")
+	chimeraEditCmd.Flags().String(chimera.ImageBanner, "", "Path to the banner image")
+	chimeraEditCmd.Flags().String(chimera.ImageLogo, "", "Path to the logo image")
+	chimeraEditCmd.Flags().String(chimera.ImageBackground, "", "Path to the background image")
+	chimeraEditCmd.Flags().String(chimera.ImageIcon, "", "Path to the icon image")
+}
@@ -1,12 +1,19 @@
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"strings"
 
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/chimera"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/keyring"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/output"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steamgriddb"
 )
 
 type FatalError struct {
@@ -31,17 +38,138 @@ func (m *MultiError) Error() string {
 	return strings.Join(report, "; ")
 }
 
-// ExitError will print an error and exit depending on the output format
+// ErrorCode is the class of an error that a command failed with. Each class
+// exits with a different exit code.
+type ErrorCode string
+
+const (
+	ErrGeneral    ErrorCode = "error"
+	ErrUsage      ErrorCode = "usage"
+	ErrNotFound   ErrorCode = "not_found"
+	ErrConflict   ErrorCode = "conflict"
+	ErrAuth       ErrorCode = "auth"
+	ErrNetwork    ErrorCode = "network"
+	ErrPermission ErrorCode = "permission"
+)
+
+// exitCodes are the process exit codes of each class of error
+var exitCodes = map[ErrorCode]int{
+	ErrGeneral:    1,
+	ErrUsage:      2,
+	ErrNotFound:   3,
+	ErrConflict:   4,
+	ErrAuth:       5,
+	ErrNetwork:    6,
+	ErrPermission: 7,
+}
+
+// ExitCode will return the process exit code for the error class
+func (c ErrorCode) ExitCode() int {
+	if code, ok := exitCodes[c]; ok {
+		return code
+	}
+	return 1
+}
+
+// CommandError is an error with an explicit error class
+type CommandError struct {
+	Code ErrorCode
+	Err  error
+}
+
+// newCommandError will return the given error with the given error class
+func newCommandError(code ErrorCode, err error) *CommandError {
+	return &CommandError{Code: code, Err: err}
+}
+
+func (e *CommandError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorResult is the structured form of an error that is printed in every
+// output format
+type ErrorResult struct {
+	Code     ErrorCode `json:"code"`
+	ExitCode int       `json:"exitCode"`
+	Message  string    `json:"message"`
+	Errors   []string  `json:"errors,omitempty"`
+}
+
+// newErrorResult will return the structured form of the given error. Each
+// error of a multierror is listed separately.
+func newErrorResult(err error) *ErrorResult {
+	code := getErrorCode(err)
+	result := &ErrorResult{Code: code, ExitCode: code.ExitCode(), Message: err.Error()}
+	var merr *multierror.Error
+	if errors.As(err, &merr) {
+		result.Message = fmt.Sprintf("%d errors occurred", len(merr.Errors))
+		for _, e := range merr.Errors {
+			result.Errors = append(result.Errors, e.Error())
+		}
+	}
+	return result
+}
+
+// getErrorCode will return the class of the given error. A multierror has the
+// class of its errors if they all have the same class.
+func getErrorCode(err error) ErrorCode {
+	var cmdErr *CommandError
+	var merr *multierror.Error
+	var netErr net.Error
+	switch {
+	case errors.As(err, &cmdErr):
+		return cmdErr.Code
+	case errors.As(err, &merr):
+		code := ErrGeneral
+		for i, e := range merr.Errors {
+			if i == 0 {
+				code = getErrorCode(e)
+			} else if getErrorCode(e) != code {
+				return ErrGeneral
+			}
+		}
+		return code
+	case errors.Is(err, steamgriddb.ErrUnauthorized):
+		return ErrAuth
+	case errors.Is(err, chimera.ErrShortcutExists):
+		return ErrConflict
+	case errors.Is(err, shortcut.ErrNotFound), errors.Is(err, steam.ErrImageNotFound),
+		errors.Is(err, keyring.ErrNotFound), errors.Is(err, os.ErrNotExist):
+		return ErrNotFound
+	case errors.Is(err, os.ErrPermission):
+		return ErrPermission
+	case errors.As(err, &netErr):
+		return ErrNetwork
+	}
+	return ErrGeneral
+}
+
+// ExitError will print an error and exit depending on the output format. The
+// exit code depends on the class of the error.
 func ExitError(err error, format string) {
-	switch format {
-	case "json":
-		out, _ := json.Marshal(map[string]string{"errors": err.Error()})
-		fmt.Println(string(out))
-		os.Exit(1)
-	default:
-		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+	result := newErrorResult(err)
+	printer, printerErr := getPrinter(format)
+	if printerErr != nil || printer.Format == output.Term {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(result.ExitCode)
+	}
+
+	// Errors are printed as JSON instead of with the user's template
+	if printer.Format == output.Template {
+		printer.Format = output.JSON
+	}
+
+	// Tables and NDJSON print the error as a single row
+	if printer.Format == output.Table || printer.Format == output.NDJSON {
+		printer.Print(result)
+		os.Exit(result.ExitCode)
 	}
+	printer.Print(map[string]*ErrorResult{"error": result})
+	os.Exit(result.ExitCode)
 }
 
 // Print debug messages if debug is enabled
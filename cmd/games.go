@@ -0,0 +1,228 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam/library"
+	"github.com/spf13/cobra"
+)
+
+// LibraryUsage contains the disk usage of a single Steam library folder
+type LibraryUsage struct {
+	*library.Library
+	Games      int   `json:"games"`
+	SizeOnDisk int64 `json:"sizeOnDisk"`
+}
+
+// gamesCmd represents the games command
+var gamesCmd = &cobra.Command{
+	Use:   "games",
+	Short: "Inspect games installed in Steam libraries",
+	Long:  `Inspect games installed in Steam libraries`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// gamesListCmd represents the games list command
+var gamesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List games installed in Steam libraries",
+	Long: `Lists the games installed in every Steam library folder, as described by
+their app manifests. Steam tools such as Proton are hidden unless
+--include-tools is given.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		globs, _ := cmd.Flags().GetStringSlice("glob")
+		libraries, _ := cmd.Flags().GetStringSlice("library")
+		states, _ := cmd.Flags().GetStringSlice("state")
+		includeTools, _ := cmd.Flags().GetBool("include-tools")
+		sortBy, _ := cmd.Flags().GetString("sort")
+
+		// Parse the state filters
+		var wantStates []library.StateFlags
+		for _, name := range states {
+			state, ok := library.ParseState(name)
+			if !ok {
+				ExitError(fmt.Errorf("unknown state: %v", name), format)
+			}
+			wantStates = append(wantStates, state)
+		}
+
+		games, err := steam.GetInstalledGames()
+		if err != nil {
+			ExitError(err, format)
+		}
+		results := []*library.Game{}
+		for _, game := range games {
+			if game.IsTool() && !includeTools {
+				continue
+			}
+			if !matchesAnyGlob(globs, game.Name, game.AppID) {
+				continue
+			}
+			if len(libraries) > 0 && !isInLibrary(game, libraries) {
+				continue
+			}
+			if !hasAnyState(game, wantStates) {
+				continue
+			}
+			results = append(results, game)
+		}
+		err = sortGames(results, sortBy)
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Print the output
+		printOutput(results, func() {
+			for _, game := range results {
+				fmt.Println(game.Name)
+				fmt.Println("  AppId:       ", game.AppID)
+				fmt.Println("  Install Dir: ", game.Path)
+				fmt.Println("  Size:        ", formatBytes(game.SizeOnDisk))
+				fmt.Println("  Build ID:    ", game.BuildID)
+				fmt.Println("  State:       ", game.StateFlags)
+				if !game.LastUpdated.IsZero() {
+					fmt.Println("  Last Updated:", game.LastUpdated.Format("2006-01-02 15:04:05"))
+				}
+			}
+		})
+	},
+}
+
+// gamesLibrariesCmd represents the games libraries command
+var gamesLibrariesCmd = &cobra.Command{
+	Use:   "libraries",
+	Short: "List Steam library folders and their disk usage",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		libraries, err := steam.GetLibraries()
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		results := []*LibraryUsage{}
+		for _, lib := range libraries {
+			games, err := lib.Games()
+			if err != nil {
+				ExitError(err, format)
+			}
+			usage := &LibraryUsage{Library: lib, Games: len(games)}
+			for _, game := range games {
+				usage.SizeOnDisk += game.SizeOnDisk
+			}
+			results = append(results, usage)
+		}
+
+		// Print the output
+		printOutput(results, func() {
+			for _, usage := range results {
+				fmt.Println(usage.Path)
+				if usage.Label != "" {
+					fmt.Println("  Label:", usage.Label)
+				}
+				fmt.Println("  Games:", usage.Games)
+				fmt.Println("  Size: ", formatBytes(usage.SizeOnDisk))
+			}
+		})
+	},
+}
+
+// isInLibrary will return whether or not the game is installed in one of the
+// given library paths.
+func isInLibrary(game *library.Game, libraries []string) bool {
+	for _, lib := range libraries {
+		if filepath.Clean(lib) == filepath.Clean(game.Library) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnyState will return whether or not the game has one of the given state
+// flags set. Every game matches if no states are given.
+func hasAnyState(game *library.Game, states []library.StateFlags) bool {
+	if len(states) == 0 {
+		return true
+	}
+	for _, state := range states {
+		if game.StateFlags.Has(state) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortGames will sort the given games by the given field
+func sortGames(games []*library.Game, by string) error {
+	var less func(a, b *library.Game) bool
+	switch by {
+	case "name":
+		less = func(a, b *library.Game) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case "size":
+		less = func(a, b *library.Game) bool { return a.SizeOnDisk > b.SizeOnDisk }
+	case "updated":
+		less = func(a, b *library.Game) bool { return a.LastUpdated.After(b.LastUpdated) }
+	case "played":
+		less = func(a, b *library.Game) bool { return a.LastPlayed.After(b.LastPlayed) }
+	default:
+		return fmt.Errorf("unknown sort field: %v", by)
+	}
+	sort.SliceStable(games, func(i, j int) bool { return less(games[i], games[j]) })
+	return nil
+}
+
+// formatBytes will return the given size in human readable units
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	rootCmd.AddCommand(gamesCmd)
+	gamesCmd.AddCommand(gamesListCmd)
+	gamesCmd.AddCommand(gamesLibrariesCmd)
+	gamesListCmd.Flags().StringSlice("glob", []string{}, "Only list games whose name or app id matches the given glob(s)")
+	gamesListCmd.Flags().StringSlice("library", []string{}, "Only list games in the given library folder(s)")
+	gamesListCmd.Flags().StringSlice("state", []string{}, "Only list games with the given state(s) (e.g. FullyInstalled, UpdateRequired)")
+	gamesListCmd.Flags().Bool("include-tools", false, "Include Steam tools such as Proton")
+	gamesListCmd.Flags().String("sort", "name", "Sort games by name, size, updated or played")
+}
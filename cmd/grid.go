@@ -0,0 +1,378 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/image/kitty"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
+	"github.com/spf13/cobra"
+)
+
+// gridCmd represents the grid command
+var gridCmd = &cobra.Command{
+	Use:   "grid",
+	Short: "Manage Steam library artwork for shortcuts",
+	Long:  `Manage Steam library artwork for shortcuts`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// gridSetCmd represents the grid set command
+var gridSetCmd = &cobra.Command{
+	Use:   "set <name|appid>",
+	Short: "Install local image files as artwork for a shortcut",
+	Long: `Copies the given image files into the Steam grid directory using the
+file names that Steam looks for.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		nameOrID := args[0]
+
+		// Build a list of images to install
+		toSet := map[steam.ImageType]string{}
+		for _, kind := range steam.ImageTypes {
+			file, _ := cmd.Flags().GetString(string(kind))
+			if file == "" {
+				continue
+			}
+			toSet[kind] = file
+		}
+		if len(toSet) == 0 {
+			if format == "term" {
+				cmd.Help()
+			}
+			ExitError(newCommandError(ErrUsage, fmt.Errorf("at least one image file is required")), format)
+		}
+
+		// Fetch all users
+		users, err := steam.GetUsers()
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Check to see if we're setting for just one user
+		onlyForUser := cmd.Flags().Lookup("user").Value.String()
+
+		results := map[string]map[steam.ImageType]string{}
+		for _, user := range users {
+			if !steam.HasShortcuts(user) {
+				continue
+			}
+			if onlyForUser != "all" && onlyForUser != user {
+				continue
+			}
+
+			shortcutsPath, _ := steam.GetShortcutsPath(user)
+			shortcuts, err := shortcut.Load(shortcutsPath)
+			if err != nil {
+				ExitError(err, format)
+			}
+			key, sc, err := shortcuts.Lookup(nameOrID)
+			if err != nil {
+				DebugPrintln("Skipping user", user+":", err)
+				continue
+			}
+			appId := fmt.Sprintf("%v", sc.Appid)
+
+			// Copy each image into the grid directory
+			results[user] = map[steam.ImageType]string{}
+			for kind, file := range toSet {
+				DebugPrintln("Setting", kind, "image for", sc.AppName)
+				imgFile, err := steam.SetImage(user, appId, kind, file)
+				if err != nil {
+					ExitError(err, format)
+				}
+				results[user][kind] = imgFile
+			}
+
+			// Icons are referenced directly by the shortcut
+			if icon, ok := results[user][steam.ImageIcon]; ok {
+				DebugPrintln("Updating shortcut icon")
+				sc.Icon = icon
+				shortcuts.Shortcuts[key] = *sc
+				err = shortcut.Save(shortcuts, shortcutsPath)
+				if err != nil {
+					ExitError(err, format)
+				}
+			}
+		}
+		if len(results) == 0 {
+			ExitError(fmt.Errorf("%w with name or id: %v", shortcut.ErrNotFound, nameOrID), format)
+		}
+
+		// Print the output
+		printOutput(results, func() {
+			for user, images := range results {
+				fmt.Println("User:", user)
+				for kind, path := range images {
+					fmt.Printf("  %v: %v\n", kind, path)
+					kitty.Display(path)
+				}
+			}
+		}, "user", "image", "path")
+	},
+}
+
+// gridClearCmd represents the grid clear command
+var gridClearCmd = &cobra.Command{
+	Use:   "clear <name|appid>",
+	Short: "Remove artwork for a shortcut",
+	Long: `Removes artwork for a shortcut from the Steam grid directory. If no image
+types are specified, all artwork for the shortcut will be removed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		nameOrID := args[0]
+
+		// Build a list of image types to remove
+		toClear := []steam.ImageType{}
+		for _, kind := range steam.ImageTypes {
+			if ok, _ := cmd.Flags().GetBool(string(kind)); ok {
+				toClear = append(toClear, kind)
+			}
+		}
+		if len(toClear) == 0 {
+			toClear = steam.ImageTypes
+		}
+
+		// Fetch all users
+		users, err := steam.GetUsers()
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Check to see if we're clearing for just one user
+		onlyForUser := cmd.Flags().Lookup("user").Value.String()
+
+		results := map[string][]string{}
+		for _, user := range users {
+			if !steam.HasShortcuts(user) {
+				continue
+			}
+			if onlyForUser != "all" && onlyForUser != user {
+				continue
+			}
+
+			shortcutsPath, _ := steam.GetShortcutsPath(user)
+			shortcuts, err := shortcut.Load(shortcutsPath)
+			if err != nil {
+				ExitError(err, format)
+			}
+			key, sc, err := shortcuts.Lookup(nameOrID)
+			if err != nil {
+				DebugPrintln("Skipping user", user+":", err)
+				continue
+			}
+			appId := fmt.Sprintf("%v", sc.Appid)
+
+			// Remove each image type from the grid directory
+			results[user] = []string{}
+			for _, kind := range toClear {
+				removed, err := steam.RemoveImage(user, appId, kind)
+				if err != nil {
+					ExitError(err, format)
+				}
+				results[user] = append(results[user], removed...)
+
+				// Unset the shortcut icon if it pointed to a removed file
+				if kind == steam.ImageIcon && contains(removed, sc.Icon) {
+					DebugPrintln("Clearing shortcut icon")
+					sc.Icon = ""
+					shortcuts.Shortcuts[key] = *sc
+					err = shortcut.Save(shortcuts, shortcutsPath)
+					if err != nil {
+						ExitError(err, format)
+					}
+				}
+			}
+		}
+		if len(results) == 0 {
+			ExitError(fmt.Errorf("%w with name or id: %v", shortcut.ErrNotFound, nameOrID), format)
+		}
+
+		// Print the output
+		printOutput(results, func() {
+			for user, removed := range results {
+				fmt.Println("User:", user)
+				for _, path := range removed {
+					fmt.Println("  Removed:", path)
+				}
+			}
+		}, "user", "file")
+	},
+}
+
+// gridLogoPositionCmd represents the grid logo-position command
+var gridLogoPositionCmd = &cobra.Command{
+	Use:   "logo-position <name|appid>",
+	Short: "Show or set where the logo is placed over the hero image",
+	Long: `Shows or sets where the logo is placed over the hero image. Without any
+flags, the current logo position is displayed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		nameOrID := args[0]
+		preset, _ := cmd.Flags().GetString("preset")
+		clearPosition, _ := cmd.Flags().GetBool("clear")
+		widthChanged := cmd.Flags().Changed("width")
+		heightChanged := cmd.Flags().Changed("height")
+		if preset != "" && !steam.IsValidLogoPin(preset) {
+			ExitError(fmt.Errorf("unknown logo position preset: %v", preset), format)
+		}
+		for _, name := range []string{"width", "height"} {
+			value, _ := cmd.Flags().GetFloat64(name)
+			if cmd.Flags().Changed(name) && (value <= 0 || value > 100) {
+				ExitError(newCommandError(ErrUsage, fmt.Errorf("--%v must be greater than 0 and at most 100: %v", name, value)), format)
+			}
+		}
+
+		// Fetch all users
+		users, err := steam.GetUsers()
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Check to see if we're managing for just one user
+		onlyForUser := cmd.Flags().Lookup("user").Value.String()
+
+		results := map[string]*steam.LogoPosition{}
+		for _, user := range users {
+			if !steam.HasShortcuts(user) {
+				continue
+			}
+			if onlyForUser != "all" && onlyForUser != user {
+				continue
+			}
+
+			shortcutsPath, _ := steam.GetShortcutsPath(user)
+			shortcuts, err := shortcut.Load(shortcutsPath)
+			if err != nil {
+				ExitError(err, format)
+			}
+			_, sc, err := shortcuts.Lookup(nameOrID)
+			if err != nil {
+				DebugPrintln("Skipping user", user+":", err)
+				continue
+			}
+			appId := fmt.Sprintf("%v", sc.Appid)
+
+			// Remove the logo position if requested
+			if clearPosition {
+				err := steam.RemoveLogoPosition(user, appId)
+				if err != nil {
+					ExitError(err, format)
+				}
+				results[user] = nil
+				continue
+			}
+
+			// Start from the current position so individual values can be
+			// adjusted.
+			position, err := steam.GetLogoPosition(user, appId)
+			if err != nil && !errors.Is(err, steam.ErrLogoPositionNotFound) {
+				ExitError(err, format)
+			}
+			if preset == "" && !widthChanged && !heightChanged {
+				results[user] = position
+				continue
+			}
+			if position == nil {
+				defaultPosition := steam.LogoPositionPresets[steam.LogoPinBottomLeft]
+				position = &defaultPosition
+			}
+			if preset != "" {
+				presetPosition := steam.LogoPositionPresets[preset]
+				position = &presetPosition
+			}
+			if widthChanged {
+				position.WidthPct, _ = cmd.Flags().GetFloat64("width")
+			}
+			if heightChanged {
+				position.HeightPct, _ = cmd.Flags().GetFloat64("height")
+			}
+
+			err = steam.SetLogoPosition(user, appId, position)
+			if err != nil {
+				ExitError(err, format)
+			}
+			results[user] = position
+		}
+		if len(results) == 0 {
+			ExitError(fmt.Errorf("%w with name or id: %v", shortcut.ErrNotFound, nameOrID), format)
+		}
+
+		// Print the output
+		printOutput(results, func() {
+			for user, position := range results {
+				fmt.Println("User:", user)
+				if position == nil {
+					fmt.Println("  Logo Position: default")
+					continue
+				}
+				fmt.Println("  Logo Position:", position.PinnedPosition)
+				fmt.Printf("  Width:  %v%%\n", position.WidthPct)
+				fmt.Printf("  Height: %v%%\n", position.HeightPct)
+			}
+		}, "user")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gridCmd)
+	gridCmd.AddCommand(gridSetCmd)
+	gridCmd.AddCommand(gridClearCmd)
+	gridCmd.AddCommand(gridLogoPositionCmd)
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	gridCmd.PersistentFlags().String("user", "all", "Steam user ID to manage artwork for")
+
+	// Grid set flags
+	gridSetCmd.Flags().String("portrait", "", "Path to a portrait grid image (600x900)")
+	gridSetCmd.Flags().String("landscape", "", "Path to a landscape grid image (920x430)")
+	gridSetCmd.Flags().String("hero", "", "Path to a hero image")
+	gridSetCmd.Flags().String("logo", "", "Path to a logo image")
+	gridSetCmd.Flags().String("icon", "", "Path to an icon image")
+
+	// Grid clear flags
+	gridClearCmd.Flags().Bool("portrait", false, "Remove the portrait grid image")
+	gridClearCmd.Flags().Bool("landscape", false, "Remove the landscape grid image")
+	gridClearCmd.Flags().Bool("hero", false, "Remove the hero image")
+	gridClearCmd.Flags().Bool("logo", false, "Remove the logo image")
+	gridClearCmd.Flags().Bool("icon", false, "Remove the icon image")
+
+	// Grid logo-position flags
+	gridLogoPositionCmd.Flags().String("preset", "", `Logo position preset ("BottomLeft" "UpperLeft" "CenterCenter" "UpperCenter" "BottomCenter")`)
+	gridLogoPositionCmd.Flags().Float64("width", 50, "Logo width as a percentage of the hero image (greater than 0, at most 100)")
+	gridLogoPositionCmd.Flags().Float64("height", 50, "Logo height as a percentage of the hero image (greater than 0, at most 100)")
+	gridLogoPositionCmd.Flags().Bool("clear", false, "Remove the logo position so Steam uses its default")
+	gridLogoPositionCmd.MarkFlagsMutuallyExclusive("clear", "preset")
+	gridLogoPositionCmd.MarkFlagsMutuallyExclusive("clear", "width")
+	gridLogoPositionCmd.MarkFlagsMutuallyExclusive("clear", "height")
+}
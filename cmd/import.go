@@ -0,0 +1,254 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/importer"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steamgriddb"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <source>",
+	Short: "Import games from other launchers as Steam shortcuts",
+	Long: `Imports games installed by other game launchers as Steam shortcuts.
+
+Available sources: ` + strings.Join(importer.Names(), ", "),
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: importer.Names(),
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+
+		// List the available importers if no source was given
+		if len(args) == 0 {
+			printImporters(format)
+			return
+		}
+
+		source, err := importer.Get(args[0])
+		if err != nil {
+			ExitError(err, format)
+		}
+		if !source.Available() {
+			ExitError(fmt.Errorf("%v does not appear to be installed", source.Name()), format)
+		}
+
+		// Discover the games to import. Games that could not be read are
+		// reported along with the imported shortcuts.
+		found, errors := source.Import()
+		if errors != nil && len(found) == 0 {
+			ExitError(errors, format)
+		}
+		globs, _ := cmd.Flags().GetStringSlice("glob")
+		toImport := []*shortcut.Shortcut{}
+		for _, sc := range found {
+			if !matchesAnyGlob(globs, sc.AppName) {
+				continue
+			}
+			toImport = append(toImport, sc)
+		}
+
+		importShortcuts(cmd, toImport, errors, format)
+	},
+}
+
+// printImporters will print the registered importers
+func printImporters(format string) {
+	type importerInfo struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Available   bool   `json:"available"`
+	}
+	infos := []importerInfo{}
+	for _, i := range importer.List() {
+		infos = append(infos, importerInfo{i.Name(), i.Description(), i.Available()})
+	}
+
+	printOutput(infos, func() {
+		for _, info := range infos {
+			available := ""
+			if !info.Available {
+				available = " (not found)"
+			}
+			fmt.Printf("%-10s %v%v\n", info.Name, info.Description, available)
+		}
+	})
+}
+
+// importShortcuts will validate the given shortcuts and add them for each
+// selected Steam user, skipping any that already exist. Entries that were
+// skipped while discovering the shortcuts are reported along with any that
+// fail here. Honors the "user", "dry-run" and "download-images" flags.
+func importShortcuts(cmd *cobra.Command, newShortcuts []*shortcut.Shortcut, errors error, format string) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	// Validate the shortcuts before writing them
+	valid := []*shortcut.Shortcut{}
+	for _, sc := range newShortcuts {
+		_, remaining := shortcut.Fix(sc)
+		if len(remaining) > 0 {
+			errors = multierror.Append(errors, fmt.Errorf("%v: %v", sc.AppName, remaining[0]))
+			continue
+		}
+		sc.Appid = int64(shortcut.CalculateAppID(sc.Exe, sc.AppName))
+		valid = append(valid, sc)
+	}
+
+	// Artwork is downloaded for each new shortcut if requested
+	var client *steamgriddb.Client
+	if download, _ := cmd.Flags().GetBool("download-images"); download && !dryRun {
+		apiKey := getAPIKey(cmd.Flags())
+		if apiKey == "" {
+			ExitError(newCommandError(ErrAuth, fmt.Errorf("no API key specified (use --api-key or the login command)")), format)
+		}
+		var err error
+		client, err = newGridDBClient(cmd.Flags(), apiKey)
+		if err != nil {
+			ExitError(err, format)
+		}
+	}
+	opts := newDownloadOptions(cmd.Flags())
+
+	// Fetch all users
+	users, err := steam.GetUsers()
+	if err != nil {
+		ExitError(err, format)
+	}
+
+	// Check to see if we're importing for just one user
+	onlyForUser := cmd.Flags().Lookup("user").Value.String()
+
+	results := map[string][]*shortcut.Shortcut{}
+	for _, user := range users {
+		if !steam.HasShortcuts(user) {
+			continue
+		}
+		if onlyForUser != "all" && onlyForUser != user {
+			continue
+		}
+
+		shortcutsPath, _ := steam.GetShortcutsPath(user)
+		shortcuts, err := shortcut.Load(shortcutsPath)
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Skip any shortcuts that already exist
+		results[user] = []*shortcut.Shortcut{}
+		for _, sc := range valid {
+			if _, err := shortcuts.LookupByID(sc.Appid); err == nil {
+				DebugPrintln("Shortcut already exists:", sc.AppName)
+				continue
+			}
+			userShortcut := *sc
+			if client != nil {
+				downloaded, err := downloadImages(client, user, &userShortcut, opts)
+				if err != nil {
+					errors = multierror.Append(errors, fmt.Errorf("%v: %v", sc.AppName, err))
+				}
+				if icon, ok := downloaded["icon"]; ok && userShortcut.Icon == "" {
+					userShortcut.Icon = icon
+				}
+			}
+			shortcuts.Add(&userShortcut)
+			results[user] = append(results[user], &userShortcut)
+		}
+		if dryRun || len(results[user]) == 0 {
+			continue
+		}
+
+		// Write the changes
+		err = shortcut.Save(shortcuts, shortcutsPath)
+		if err != nil {
+			ExitError(err, format)
+		}
+	}
+
+	// Print the output
+	printOutput(results, func() {
+		if dryRun {
+			fmt.Println("Dry run: no shortcuts were written")
+		}
+		for _, user := range sortedKeys(results) {
+			imported := results[user]
+			fmt.Println("User:", user)
+			for _, sc := range imported {
+				fmt.Println("  ", sc.AppName)
+				fmt.Println("    AppId:         ", sc.Appid)
+				fmt.Println("    Executable:    ", sc.Exe)
+				fmt.Println("    Launch Options:", sc.LaunchOptions)
+				fmt.Println("    Start Dir:     ", sc.StartDir)
+				fmt.Println("    Icon:          ", sc.Icon)
+				fmt.Println("    Shortcut Path: ", sc.ShortcutPath)
+				fmt.Println("    Tags:          ", strings.Join(sc.TagList(), ", "))
+			}
+		}
+	}, "user")
+
+	// Report entries that could not be imported or downloaded
+	if errors != nil {
+		ExitError(errors, format)
+	}
+}
+
+// matchesAnyGlob will return whether or not the given name matches any of the
+// given globs. An empty list of globs matches everything.
+func matchesAnyGlob(globs []string, names ...string) bool {
+	if len(globs) == 0 {
+		return true
+	}
+	for _, glob := range globs {
+		for _, name := range names {
+			if ok, _ := filepath.Match(glob, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringSlice("glob", []string{}, "Only import games whose name matches one of the given globs")
+	importCmd.Flags().Bool("dry-run", false, "Preview the shortcuts that would be imported without writing them")
+	importCmd.Flags().String("user", "all", "Steam user ID to import the shortcuts for")
+	importCmd.Flags().StringP("api-key", "k", "", "SteamGridDB API Key (env SSM_API_KEY)")
+	importCmd.Flags().BoolP("download-images", "i", false, "Auto-download artwork from SteamGridDB for each game (requires SteamGridDB API Key)")
+	importCmd.Flags().Bool("only-missing", true, "Only download images that do not already exist")
+	importCmd.Flags().String("style-hero", "", `Optional hero style to download ("alternate" "blurred" "material")`)
+	importCmd.Flags().String("style-grid", "", `Optional grid style to download ("alternate" "blurred" "white_logo" "material" "no_logo")`)
+	importCmd.Flags().String("style-icon", "", `Optional icon style to download ("official" "custom")`)
+	importCmd.Flags().String("style-logo", "", `Optional logo style to download ("official" "white" "black" "custom")`)
+	importCmd.Flags().String("nsfw", "", `Whether to download NSFW images ("any" "false" "true", default "false")`)
+	importCmd.Flags().String("humor", "", `Whether to download humor images ("any" "false" "true", default "any")`)
+}
@@ -0,0 +1,334 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
+	"github.com/spf13/cobra"
+)
+
+// LaunchOptionsResult contains the launch options of a single Steam game
+type LaunchOptionsResult struct {
+	AppID         string `json:"appid"`
+	Name          string `json:"name,omitempty"`
+	LaunchOptions string `json:"launchOptions"`
+	Previous      string `json:"previous,omitempty"`
+}
+
+// launchOptionsCmd represents the launch-options command
+var launchOptionsCmd = &cobra.Command{
+	Use:   "launch-options",
+	Short: "Manage launch options of Steam games",
+	Long: `Reads and changes the launch options of Steam games stored in each user's
+localconfig.vdf. Games can be given by app id, or selected in bulk with
+--glob (matching installed game names), --tag (matching collection names)
+or --all. Steam rewrites localconfig.vdf when it exits, so Steam should be
+closed when making changes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// launchOptionsGetCmd represents the launch-options get command
+var launchOptionsGetCmd = &cobra.Command{
+	Use:   "get [appid...]",
+	Short: "Show the launch options of Steam games",
+	Long: `Shows the launch options of the given Steam games. With no games selected,
+every game with launch options is shown.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		users, err := getSelectedUsers(cmd)
+		if err != nil {
+			ExitError(err, format)
+		}
+		names := getInstalledAppNames()
+
+		results := map[string][]*LaunchOptionsResult{}
+		for _, user := range users {
+			config, err := steam.LoadLocalConfig(user)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				ExitError(err, format)
+			}
+			appIds, err := selectLaunchOptionsApps(cmd, user, args)
+			if err != nil {
+				ExitError(err, format)
+			}
+			if appIds == nil {
+				appIds = config.AppIDs()
+			}
+			results[user] = []*LaunchOptionsResult{}
+			for _, appId := range appIds {
+				results[user] = append(results[user], &LaunchOptionsResult{
+					AppID:         appId,
+					Name:          names[appId],
+					LaunchOptions: config.GetLaunchOptions(appId),
+				})
+			}
+		}
+
+		printLaunchOptions(results, format)
+	},
+}
+
+// launchOptionsSetCmd represents the launch-options set command
+var launchOptionsSetCmd = &cobra.Command{
+	Use:   "set [appid...] <options>",
+	Short: "Replace the launch options of Steam games",
+	Long: `Replaces the launch options of Steam games. Use "--" before options that
+start with a dash, e.g.:
+
+  launch-options set 620 -- "-novid -console"`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		options := args[len(args)-1]
+		editLaunchOptions(cmd, args[:len(args)-1], func(existing string) string {
+			return options
+		})
+	},
+}
+
+// launchOptionsAppendCmd represents the launch-options append command
+var launchOptionsAppendCmd = &cobra.Command{
+	Use:   "append [appid...] <options>",
+	Short: "Add to the launch options of Steam games",
+	Long: `Adds the given options to the launch options of Steam games. Anything before
+%command% is added in front of the game's command, and anything after it is
+added to the game's arguments, e.g.:
+
+  launch-options append --all "gamemoderun %command%"
+  launch-options append 620 -- "DXVK_HUD=fps %command% -novid"
+
+Options that are already present are not added again.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		options := args[len(args)-1]
+		editLaunchOptions(cmd, args[:len(args)-1], func(existing string) string {
+			return steam.MergeLaunchOptions(existing, options)
+		})
+	},
+}
+
+// launchOptionsRemoveCmd represents the launch-options remove command
+var launchOptionsRemoveCmd = &cobra.Command{
+	Use:   "remove [appid...] [options]",
+	Short: "Remove launch options from Steam games",
+	Long: `Removes the given options from the launch options of Steam games, e.g.
+"gamemoderun %command%". Use --clear to remove all launch options.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		if clearAll, _ := cmd.Flags().GetBool("clear"); clearAll {
+			editLaunchOptions(cmd, args, func(existing string) string {
+				return ""
+			})
+			return
+		}
+		if len(args) == 0 {
+			ExitError(fmt.Errorf("options to remove are required unless --clear is given"), format)
+		}
+		options := args[len(args)-1]
+		editLaunchOptions(cmd, args[:len(args)-1], func(existing string) string {
+			return steam.StripLaunchOptions(existing, options)
+		})
+	},
+}
+
+// editLaunchOptions will apply the given change to the launch options of the
+// selected games for each selected user.
+func editLaunchOptions(cmd *cobra.Command, args []string, change func(existing string) string) {
+	format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	users, err := getSelectedUsers(cmd)
+	if err != nil {
+		ExitError(err, format)
+	}
+	names := getInstalledAppNames()
+
+	results := map[string][]*LaunchOptionsResult{}
+	for _, user := range users {
+		config, err := steam.LoadLocalConfig(user)
+		if errors.Is(err, os.ErrNotExist) {
+			DebugPrintln("No localconfig.vdf found for user", user)
+			continue
+		}
+		if err != nil {
+			ExitError(err, format)
+		}
+		appIds, err := selectLaunchOptionsApps(cmd, user, args)
+		if err != nil {
+			ExitError(err, format)
+		}
+		if appIds == nil {
+			ExitError(fmt.Errorf("no games selected; give app ids or use --glob, --tag or --all"), format)
+		}
+
+		results[user] = []*LaunchOptionsResult{}
+		for _, appId := range appIds {
+			existing := config.GetLaunchOptions(appId)
+			options := change(existing)
+			if options == existing {
+				continue
+			}
+			config.SetLaunchOptions(appId, options)
+			results[user] = append(results[user], &LaunchOptionsResult{
+				AppID:         appId,
+				Name:          names[appId],
+				LaunchOptions: options,
+				Previous:      existing,
+			})
+		}
+		if dryRun || len(results[user]) == 0 {
+			continue
+		}
+		err = config.Save()
+		if err != nil {
+			ExitError(err, format)
+		}
+	}
+
+	printLaunchOptions(results, format)
+}
+
+// selectLaunchOptionsApps will return the app ids given as arguments or
+// selected with the --glob, --tag and --all flags. Returns nil if no games
+// were selected.
+func selectLaunchOptionsApps(cmd *cobra.Command, user string, args []string) ([]string, error) {
+	globs, _ := cmd.Flags().GetStringSlice("glob")
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	all, _ := cmd.Flags().GetBool("all")
+	if len(args) == 0 && len(globs) == 0 && len(tags) == 0 && !all {
+		return nil, nil
+	}
+
+	selected := map[string]bool{}
+	for _, arg := range args {
+		if _, err := strconv.ParseUint(arg, 10, 32); err != nil {
+			return nil, fmt.Errorf("invalid app id: %v", arg)
+		}
+		selected[arg] = true
+	}
+
+	// Match installed games by name
+	if len(globs) > 0 || all {
+		apps, err := steam.GetInstalledGames()
+		if err != nil {
+			return nil, err
+		}
+		for _, app := range apps {
+			if app.IsTool() {
+				continue
+			}
+			if all || matchesAnyGlob(globs, app.Name, app.AppID) {
+				selected[app.AppID] = true
+			}
+		}
+	}
+
+	// Match games in the given collections
+	if len(tags) > 0 {
+		collections, err := steam.LoadCollections(user)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			collection, err := collections.Get(tag)
+			if err != nil {
+				return nil, err
+			}
+			for _, appId := range collection.Added {
+				// Launch options of non-Steam shortcuts are stored in
+				// shortcuts.vdf instead
+				if appId >= 0x80000000 {
+					continue
+				}
+				selected[fmt.Sprintf("%v", appId)] = true
+			}
+		}
+	}
+
+	appIds := make([]string, 0, len(selected))
+	for appId := range selected {
+		appIds = append(appIds, appId)
+	}
+	sort.Strings(appIds)
+	return appIds, nil
+}
+
+// getInstalledAppNames will return the names of installed Steam games by app
+// id. Games that are not installed will not have a name.
+func getInstalledAppNames() map[string]string {
+	names := map[string]string{}
+	apps, err := steam.GetInstalledGames()
+	if err != nil {
+		DebugPrintln("Unable to read installed apps:", err)
+		return names
+	}
+	for _, app := range apps {
+		names[app.AppID] = app.Name
+	}
+	return names
+}
+
+// printLaunchOptions will print the given launch options results
+func printLaunchOptions(results map[string][]*LaunchOptionsResult, format string) {
+	printOutput(results, func() {
+		for user, apps := range results {
+			if len(apps) == 0 {
+				continue
+			}
+			fmt.Println("User:", user)
+			for _, app := range apps {
+				fmt.Println("  ", app.AppID, app.Name)
+				if app.Previous != "" {
+					fmt.Println("    Previous:      ", app.Previous)
+				}
+				fmt.Println("    Launch Options:", app.LaunchOptions)
+			}
+		}
+	}, "user")
+}
+
+func init() {
+	rootCmd.AddCommand(launchOptionsCmd)
+	launchOptionsCmd.AddCommand(launchOptionsGetCmd)
+	launchOptionsCmd.AddCommand(launchOptionsSetCmd)
+	launchOptionsCmd.AddCommand(launchOptionsAppendCmd)
+	launchOptionsCmd.AddCommand(launchOptionsRemoveCmd)
+	launchOptionsCmd.PersistentFlags().String("user", "all", "Steam user ID to manage launch options for")
+	launchOptionsCmd.PersistentFlags().StringSlice("glob", []string{}, "Select installed games whose name matches the given glob(s)")
+	launchOptionsCmd.PersistentFlags().StringSlice("tag", []string{}, "Select games in the given collection(s)")
+	launchOptionsCmd.PersistentFlags().Bool("all", false, "Select all installed games")
+	for _, c := range []*cobra.Command{launchOptionsSetCmd, launchOptionsAppendCmd, launchOptionsRemoveCmd} {
+		c.Flags().Bool("dry-run", false, "Show the changes without writing them")
+	}
+	launchOptionsRemoveCmd.Flags().Bool("clear", false, "Remove all launch options")
+}
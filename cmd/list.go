@@ -24,8 +24,10 @@ SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/shadowblip/steam-shortcut-manager/pkg/chimera"
 	"github.com/shadowblip/steam-shortcut-manager/pkg/image/kitty"
@@ -34,6 +36,31 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// shortcutsByUser are the Steam shortcuts of each user. Tables have a row
+// for each shortcut.
+type shortcutsByUser map[string]*shortcut.Shortcuts
+
+// Rows will return the shortcuts of each user in the order of their keys
+func (s shortcutsByUser) Rows() interface{} {
+	rows := map[string][]shortcut.Shortcut{}
+	for user, shortcuts := range s {
+		keys := []string{}
+		for key := range shortcuts.Shortcuts {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			a, _ := strconv.Atoi(keys[i])
+			b, _ := strconv.Atoi(keys[j])
+			return a < b
+		})
+		rows[user] = []shortcut.Shortcut{}
+		for _, key := range keys {
+			rows[user] = append(rows[user], shortcuts.Shortcuts[key])
+		}
+	}
+	return rows
+}
+
 // listCmd represents the list command
 var listCmd = &cobra.Command{
 	Use:   "list",
@@ -46,8 +73,14 @@ var listCmd = &cobra.Command{
 			ExitError(err, format)
 		}
 
+		// Compat tools are mapped globally rather than per user
+		compatTools, err := steam.GetCompatToolMapping()
+		if err != nil {
+			DebugPrintln("Unable to read compat tool mapping:", err)
+		}
+
 		// Fetch all shortcuts
-		results := map[string]*shortcut.Shortcuts{}
+		results := shortcutsByUser{}
 		for _, user := range users {
 			if !steam.HasShortcuts(user) {
 				continue
@@ -80,7 +113,10 @@ var listCmd = &cobra.Command{
 				images.Portrait, _ = steam.GetImagePortrait(user, idStr)
 				images.Landscape, _ = steam.GetImageLandscape(user, idStr)
 				images.Hero, _ = steam.GetImageHero(user, idStr)
+				images.Icon, _ = steam.GetImageIcon(user, idStr)
+				images.LogoPosition, _ = steam.GetLogoPosition(user, idStr)
 				sc.Images = images
+				sc.CompatTool = compatTools[idStr]
 				newShortcuts.Add(&sc)
 			}
 
@@ -88,8 +124,7 @@ var listCmd = &cobra.Command{
 		}
 
 		// Print the output
-		switch format {
-		case "term":
+		printOutput(results, func() {
 			for user, shortcuts := range results {
 				if shortcuts.Shortcuts == nil || len(shortcuts.Shortcuts) == 0 {
 					continue
@@ -100,10 +135,16 @@ var listCmd = &cobra.Command{
 					fmt.Println("    AppId:         ", sc.Appid)
 					fmt.Println("    Executable:    ", sc.Exe)
 					fmt.Println("    Launch Options:", sc.LaunchOptions)
+					if sc.CompatTool != "" {
+						fmt.Println("    Compat Tool:   ", sc.CompatTool)
+					}
 					fmt.Println("    Logo Image:    ", sc.Images.Logo)
 					if sc.Images.Logo != "" {
 						kitty.Display(sc.Images.Logo)
 					}
+					if pos := sc.Images.LogoPosition; pos != nil {
+						fmt.Printf("    Logo Position:  %v (%v%% x %v%%)\n", pos.PinnedPosition, pos.WidthPct, pos.HeightPct)
+					}
 					fmt.Println("    Portrait Image:", sc.Images.Portrait)
 					if sc.Images.Portrait != "" {
 						kitty.Display(sc.Images.Portrait)
@@ -122,64 +163,121 @@ var listCmd = &cobra.Command{
 					}
 				}
 			}
-		case "json":
-			out, err := json.MarshalIndent(results, "", "  ")
-			if err != nil {
-				ExitError(err, format)
-			}
-			fmt.Println(string(out))
-		default:
-			panic("unknown output format: " + format)
-		}
+		}, "user")
 	},
 }
 
+// ChimeraListResult is a Chimera shortcut along with the platform it belongs
+// to and which of its images exist.
+type ChimeraListResult struct {
+	*chimera.Shortcut
+	Platform string          `json:"platform"`
+	Images   map[string]bool `json:"images"`
+}
+
 // chimeraListCmd represents the list command
 var chimeraListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List currently registered Chimera shortcuts",
-	Long:  `Lists all of the shortcuts registered in Chimera`,
+	Long: `Lists all of the shortcuts registered in Chimera for the given platform, or
+for every platform with a shortcuts file if --all is given.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
 		if !chimera.HasChimera() {
-			ExitError(fmt.Errorf("no chimera config found at %v", chimera.ConfigDir), format)
+			ExitError(fmt.Errorf("no chimera config found at %v", chimera.GetConfigDir()), format)
 		}
 
-		// Get the platform flag
-		platform := chimeraCmd.PersistentFlags().Lookup("platform").Value.String()
+		// Get the platforms to list
+		var platforms []string
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			var err error
+			platforms, err = chimera.GetShortcutsPlatforms()
+			if err != nil {
+				ExitError(err, format)
+			}
+		} else {
+			platform := getChimeraPlatform(format).Name
 
-		// Ensure the Chimera shortcuts file exists
-		err := chimera.EnsureShortcutsFileExists(platform)
-		if err != nil {
-			ExitError(err, format)
+			// Ensure the Chimera shortcuts file exists
+			err := chimera.EnsureShortcutsFileExists(platform)
+			if err != nil {
+				ExitError(err, format)
+			}
+			platforms = []string{platform}
 		}
 
-		// Read from the given shortcuts file
-		shortcuts, err := chimera.LoadShortcuts(chimera.GetShortcutsFile(platform))
-		if err != nil {
-			ExitError(err, format)
-		}
+		// Get the filters
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		filterHidden := cmd.Flags().Changed("hidden")
+		hidden, _ := cmd.Flags().GetBool("hidden")
 
-		// Print the output
-		switch format {
-		case "term":
-			for _, sc := range shortcuts {
-				fmt.Println(sc.Name)
-				fmt.Println("  Executable:", sc.Cmd)
-			}
-		case "json":
-			out, err := json.MarshalIndent(shortcuts, "", "  ")
+		// Read from each shortcuts file
+		results := []*ChimeraListResult{}
+		for _, platform := range platforms {
+			shortcuts, err := chimera.LoadShortcuts(chimera.GetShortcutsFile(platform))
 			if err != nil {
-				ExitError(err, format)
+				ExitError(fmt.Errorf("unable to load %v shortcuts: %w", platform, err), format)
+			}
+			for _, sc := range shortcuts {
+				if filterHidden && sc.Hidden != hidden {
+					continue
+				}
+				if !hasAnyTag(sc, tags) {
+					continue
+				}
+				result := &ChimeraListResult{
+					Shortcut: sc,
+					Platform: platform,
+					Images:   map[string]bool{},
+				}
+				for _, imageType := range chimera.ImageTypes {
+					result.Images[imageType] = sc.HasImage(imageType)
+				}
+				results = append(results, result)
 			}
-			fmt.Println(string(out))
-		default:
-			panic("unknown output format: " + format)
 		}
 
+		// Print the output
+		printOutput(results, func() {
+			for _, result := range results {
+				fmt.Println(result.Name)
+				fmt.Println("  Platform:  ", result.Platform)
+				fmt.Println("  Executable:", result.Cmd)
+				fmt.Println("  Hidden:    ", result.Hidden)
+				fmt.Println("  Tags:      ", strings.Join(result.Tags, ", "))
+				fmt.Println("  Poster:    ", yesNo(result.Images[chimera.ImagePoster]))
+				fmt.Println("  Banner:    ", yesNo(result.Images[chimera.ImageBanner]))
+				fmt.Println("  Logo:      ", yesNo(result.Images[chimera.ImageLogo]))
+				fmt.Println("  Background:", yesNo(result.Images[chimera.ImageBackground]))
+				fmt.Println("  Icon:      ", yesNo(result.Images[chimera.ImageIcon]))
+			}
+		})
+
 	},
 }
 
+// hasAnyTag will return true if the given Chimera shortcut has any of the
+// given tags, or if no tags are given.
+func hasAnyTag(sc *chimera.Shortcut, tags []string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		if sc.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// yesNo will return "yes" or "no" for the given bool
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
 func init() {
 	rootCmd.AddCommand(listCmd)
 	chimeraCmd.AddCommand(chimeraListCmd)
@@ -194,4 +292,8 @@ func init() {
 	// is called directly, e.g.:
 	// listCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	listCmd.Flags().StringP("app-id", "i", "all", "Only list the given Steam app ID")
+
+	chimeraListCmd.Flags().Bool("all", false, "List the shortcuts of every platform")
+	chimeraListCmd.Flags().StringSlice("tag", []string{}, "Only list shortcuts with any of the given tags")
+	chimeraListCmd.Flags().Bool("hidden", false, "Only list hidden (or with --hidden=false, visible) shortcuts")
 }
@@ -0,0 +1,143 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/keyring"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steamgriddb"
+	"github.com/spf13/cobra"
+)
+
+// LoginResult describes where the SteamGridDB API key was stored
+type LoginResult struct {
+	Store     string `json:"store"`
+	Validated bool   `json:"validated"`
+}
+
+// loginCmd represents the login command
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Store your SteamGridDB API key",
+	Long: `Reads your SteamGridDB API key from standard input, checks that SteamGridDB
+accepts it, and stores it so that commands can use it without --api-key.
+The key is stored in the freedesktop Secret Service (e.g. GNOME Keyring or
+KWallet) using secret-tool. If that is not available, it is stored in
+$XDG_CONFIG_HOME/steam-shortcut-manager/secrets.yaml, which only you can read.
+
+You can create an API key at https://www.steamgriddb.com/profile/preferences/api`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		skipValidation, _ := cmd.Flags().GetBool("skip-validation")
+
+		apiKey, err := readAPIKey()
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Check the key before storing it
+		if !skipValidation {
+			DebugPrintln("Validating API key")
+			err := steamgriddb.NewClient(apiKey).Validate()
+			if errors.Is(err, steamgriddb.ErrUnauthorized) {
+				ExitError(err, format)
+			}
+			if err != nil {
+				ExitError(fmt.Errorf("unable to validate API key (use --skip-validation to store it anyway): %w", err), format)
+			}
+		}
+
+		store, err := keyring.Default().Set(apiKeySecret, apiKey)
+		if err != nil {
+			ExitError(fmt.Errorf("unable to store API key: %w", err), format)
+		}
+		result := &LoginResult{Store: store.Name(), Validated: !skipValidation}
+
+		// Print the output
+		printOutput(result, func() {
+			fmt.Printf("Stored the SteamGridDB API key in %v\n", result.Store)
+		})
+	},
+}
+
+// logoutCmd represents the logout command
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove your stored SteamGridDB API key",
+	Long: `Removes the SteamGridDB API key stored with the login command from the
+Secret Service and the secrets file. An API key in the config file or
+environment is not changed.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		err := keyring.Default().Delete(apiKeySecret)
+		if err != nil {
+			ExitError(fmt.Errorf("unable to remove API key: %w", err), format)
+		}
+
+		// Print the output
+		printOutput(map[string]bool{"removed": true}, func() {
+			fmt.Println("Removed the stored SteamGridDB API key")
+		})
+	},
+}
+
+// readAPIKey will read the API key from standard input, prompting for it if
+// standard input is a terminal.
+func readAPIKey() (string, error) {
+	return readSecret("SteamGridDB API key")
+}
+
+// readSecret will read a secret value from standard input, prompting for it
+// with the given name if standard input is a terminal. Secrets are read this
+// way so they do not end up in the shell history.
+func readSecret(name string) (string, error) {
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprintf(os.Stderr, "%v: ", name)
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	secret := strings.TrimSpace(line)
+	if secret == "" {
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		return "", newCommandError(ErrUsage, fmt.Errorf("no %v given", name))
+	}
+	return secret, nil
+}
+
+func init() {
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+
+	loginCmd.Flags().Bool("skip-validation", false, "Store the API key without checking it with SteamGridDB")
+}
@@ -0,0 +1,84 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/output"
+)
+
+// printOutput will print the given result in the output format selected with
+// --output. The term format is printed by printTerm. Names are the table
+// columns of the keys of nested maps and of plain values, e.g. "user".
+func printOutput(result interface{}, printTerm func(), names ...string) {
+	format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+	printer, err := getPrinter(format)
+	if err != nil {
+		ExitError(newCommandError(ErrUsage, err), format)
+	}
+	if printer.Format == output.Term {
+		printTerm()
+		return
+	}
+	err = printer.Print(result, names...)
+	if err != nil {
+		ExitError(err, format)
+	}
+}
+
+// getPrinter will return a printer for the given output format that uses the
+// template given with --template.
+func getPrinter(format string) (*output.Printer, error) {
+	outputFormat, err := output.ParseFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	text := rootCmd.PersistentFlags().Lookup("template").Value.String()
+	return output.NewPrinter(outputFormat, text, os.Stdout)
+}
+
+// checkOutputFlags will check the --output and --template flags before a
+// command runs. A template selects the template format unless --output is
+// given.
+func checkOutputFlags() {
+	flags := rootCmd.PersistentFlags()
+	if flags.Lookup("template").Changed && !flags.Lookup("output").Changed {
+		flags.Set("output", string(output.Template))
+	}
+	format := flags.Lookup("output").Value.String()
+	if _, err := getPrinter(format); err != nil {
+		ExitError(newCommandError(ErrUsage, err), format)
+	}
+}
+
+// outputFormatNames will return the names of all output formats
+func outputFormatNames() []string {
+	names := []string{}
+	for _, format := range output.Formats {
+		names = append(names, string(format))
+	}
+	return names
+}
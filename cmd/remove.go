@@ -32,6 +32,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// RemoveResult is a shortcut that was removed
+type RemoveResult struct {
+	Name  string `json:"name"`
+	AppID int64  `json:"appid,omitempty"`
+}
+
 // removeCmd represents the remove command
 var removeCmd = &cobra.Command{
 	Use:   "remove <name>",
@@ -40,17 +46,19 @@ var removeCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		name := args[0]
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
 
 		// Fetch all users
 		users, err := steam.GetUsers()
 		if err != nil {
-			panic(err)
+			ExitError(err, format)
 		}
 
 		// Check to see if we're fetching for just one user
 		onlyForUser := cmd.Flags().Lookup("user").Value.String()
 
 		// Fetch all shortcuts
+		results := map[string][]*RemoveResult{}
 		for _, user := range users {
 			if !steam.HasShortcuts(user) {
 				continue
@@ -62,17 +70,22 @@ var removeCmd = &cobra.Command{
 			shortcutsPath, _ := steam.GetShortcutsPath(user)
 			shortcuts, err := shortcut.Load(shortcutsPath)
 			if err != nil {
-				panic(err)
+				ExitError(err, format)
 			}
 
 			// Find the shortcut to remove by name
+			removed := []*RemoveResult{}
 			shortcutsList := []shortcut.Shortcut{}
 			for _, sc := range shortcuts.Shortcuts {
 				if sc.AppName == name {
+					removed = append(removed, &RemoveResult{Name: sc.AppName, AppID: sc.Appid})
 					continue
 				}
 				shortcutsList = append(shortcutsList, sc)
 			}
+			if len(removed) == 0 {
+				continue
+			}
 
 			// Create a new shortcuts object that we will save
 			newShortcuts := &shortcut.Shortcuts{
@@ -85,10 +98,22 @@ var removeCmd = &cobra.Command{
 			// Write the changes
 			err = shortcut.Save(newShortcuts, shortcutsPath)
 			if err != nil {
-				panic(err)
+				ExitError(err, format)
 			}
+			results[user] = removed
+		}
+		if len(results) == 0 {
+			ExitError(fmt.Errorf("%w with name: %v", shortcut.ErrNotFound, name), format)
 		}
 
+		// Print the output
+		printOutput(results, func() {
+			for user, removed := range results {
+				for _, sc := range removed {
+					fmt.Printf("Removed %v (%v) for user %v\n", sc.Name, sc.AppID, user)
+				}
+			}
+		}, "user")
 	},
 }
 
@@ -103,11 +128,11 @@ var chimeraRemoveCmd = &cobra.Command{
 		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
 		DebugPrintln("Using output format:", format)
 		if !chimera.HasChimera() {
-			ExitError(fmt.Errorf("no chimera config found at %v", chimera.ConfigDir), format)
+			ExitError(fmt.Errorf("no chimera config found at %v", chimera.GetConfigDir()), format)
 		}
 
 		// Get the platform flag
-		platform := chimeraCmd.PersistentFlags().Lookup("platform").Value.String()
+		platform := getChimeraPlatform(format).Name
 
 		// Ensure the Chimera shortcuts file exists
 		err := chimera.EnsureShortcutsFileExists(platform)
@@ -129,12 +154,21 @@ var chimeraRemoveCmd = &cobra.Command{
 			}
 			shortcutsList = append(shortcutsList, sc)
 		}
+		if len(shortcutsList) == len(shortcuts) {
+			ExitError(fmt.Errorf("%w with name %v on platform %v", shortcut.ErrNotFound, name, platform), format)
+		}
 
 		// Save the shortcuts
 		err = chimera.SaveShortcuts(chimera.GetShortcutsFile(platform), shortcutsList)
 		if err != nil {
 			ExitError(err, format)
 		}
+
+		// Print the output
+		result := map[string]string{"name": name, "platform": platform}
+		printOutput(result, func() {
+			fmt.Printf("Removed %v from %v\n", name, platform)
+		})
 	},
 }
 
@@ -0,0 +1,143 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/roms"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+	"github.com/spf13/cobra"
+)
+
+// romsCmd represents the roms command
+var romsCmd = &cobra.Command{
+	Use:   "roms <dir>...",
+	Short: "Add Steam shortcuts for ROMs using emulators",
+	Long: `Scans the given directories for ROMs and adds a Steam shortcut for each one
+that launches it with the emulator for its platform.
+
+The platform of each ROM is detected from the name of its directory (e.g.
+"roms/snes/") or from its file extension. Use --platform to force a platform
+and --platforms to load a custom platform table.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+
+		// Load the platform table
+		platformsFile, _ := cmd.Flags().GetString("platforms")
+		platforms, err := roms.LoadPlatforms(platformsFile)
+		if err != nil {
+			ExitError(err, format)
+		}
+		var platform *roms.Platform
+		if name, _ := cmd.Flags().GetString("platform"); name != "" {
+			platform, err = platforms.Get(name)
+			if err != nil {
+				ExitError(err, format)
+			}
+		}
+
+		// Scan each directory for ROMs
+		found := []*roms.ROM{}
+		for _, dir := range args {
+			dir, err := filepath.Abs(dir)
+			if err != nil {
+				ExitError(err, format)
+			}
+			dirROMs, err := roms.Scan(dir, platforms, platform)
+			if err != nil {
+				ExitError(err, format)
+			}
+			found = append(found, dirROMs...)
+		}
+
+		// Create a shortcut for each ROM
+		var errors error
+		globs, _ := cmd.Flags().GetStringSlice("glob")
+		newShortcuts := []*shortcut.Shortcut{}
+		for _, rom := range found {
+			if !matchesAnyGlob(globs, rom.Title, filepath.Base(rom.Path)) {
+				continue
+			}
+			sc, err := rom.Shortcut()
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				continue
+			}
+			newShortcuts = append(newShortcuts, sc)
+		}
+
+		importShortcuts(cmd, newShortcuts, errors, format)
+	},
+}
+
+// romsPlatformsCmd represents the roms platforms command
+var romsPlatformsCmd = &cobra.Command{
+	Use:   "platforms",
+	Short: "List the platforms that ROMs can be added for",
+	Long:  `Lists the built-in and custom platforms that ROMs can be added for`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		platformsFile, _ := cmd.Flags().GetString("platforms")
+		platforms, err := roms.LoadPlatforms(platformsFile)
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Print the output
+		printOutput(platforms.Sorted(), func() {
+			for _, platform := range platforms.Sorted() {
+				fmt.Println(platform.Name)
+				fmt.Println("  Title:     ", platform.Title)
+				fmt.Println("  Extensions:", strings.Join(platform.Extensions, ", "))
+				fmt.Println("  Emulator:  ", platform.Emulator.Name)
+				fmt.Println("  Arguments: ", platform.Args)
+			}
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(romsCmd)
+	romsCmd.AddCommand(romsPlatformsCmd)
+
+	romsCmd.PersistentFlags().String("platforms", "", "Path to a YAML file of custom platforms to add to or replace the built-in ones")
+	romsCmd.Flags().String("platform", "", "Treat every ROM found as belonging to the given platform")
+	romsCmd.Flags().StringSlice("glob", []string{}, "Only add ROMs whose title or file name matches one of the given globs")
+	romsCmd.Flags().Bool("dry-run", false, "Preview the shortcuts that would be added without writing them")
+	romsCmd.Flags().String("user", "all", "Steam user ID to add the shortcuts for")
+	romsCmd.Flags().StringP("api-key", "k", "", "SteamGridDB API Key (env SSM_API_KEY)")
+	romsCmd.Flags().BoolP("download-images", "i", false, "Auto-download artwork from SteamGridDB for each ROM (requires SteamGridDB API Key)")
+	romsCmd.Flags().Bool("only-missing", true, "Only download images that do not already exist")
+	romsCmd.Flags().String("style-hero", "", `Optional hero style to download ("alternate" "blurred" "material")`)
+	romsCmd.Flags().String("style-grid", "", `Optional grid style to download ("alternate" "blurred" "white_logo" "material" "no_logo")`)
+	romsCmd.Flags().String("style-icon", "", `Optional icon style to download ("official" "custom")`)
+	romsCmd.Flags().String("style-logo", "", `Optional logo style to download ("official" "white" "black" "custom")`)
+	romsCmd.Flags().String("nsfw", "", `Whether to download NSFW images ("any" "false" "true", default "false")`)
+	romsCmd.Flags().String("humor", "", `Whether to download humor images ("any" "false" "true", default "any")`)
+}
@@ -24,9 +24,11 @@ SOFTWARE.
 package cmd
 
 import (
-	"fmt"
 	"os"
+	"strings"
 
+	"github.com/shadowblip/steam-shortcut-manager/pkg/chimera"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
 	"github.com/spf13/cobra"
 
 	"github.com/spf13/viper"
@@ -42,12 +44,20 @@ var rootCmd = &cobra.Command{
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+
+	// Errors are printed in the selected output format by Execute
+	SilenceErrors: true,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	cobra.CheckErr(rootCmd.Execute())
+	err := rootCmd.Execute()
+	if err != nil {
+		// Errors returned by cobra are invalid arguments or flags
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		ExitError(newCommandError(ErrUsage, err), format)
+	}
 }
 
 func contains(s []string, str string) bool {
@@ -62,13 +72,24 @@ func contains(s []string, str string) bool {
 
 func init() {
 	cobra.OnInitialize(initConfig)
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		checkOutputFlags()
+	}
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	rootCmd.PersistentFlags().StringP("output", "o", "term", "Output format (json, term)")
+	rootCmd.PersistentFlags().StringP("output", "o", "term", "Output format ("+strings.Join(outputFormatNames(), ", ")+")")
+	rootCmd.PersistentFlags().String("template", "", "Go text/template to print results with, using the keys of the JSON output (implies --output=template)")
+	rootCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return outputFormatNames(), cobra.ShellCompDirectiveNoFileComp
+	})
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.steam-shortcut-manager.yaml)")
+	rootCmd.PersistentFlags().String("chimera-dir", "", "Chimera data directory (default is $XDG_DATA_HOME/chimera, env SSM_CHIMERA_DIR)")
+	rootCmd.PersistentFlags().String("steam-dir", "", "Steam directory (default is ~/.steam/steam, env SSM_STEAM_DIR)")
+	viper.BindPFlag("chimera-dir", rootCmd.PersistentFlags().Lookup("chimera-dir"))
+	viper.BindPFlag("steam-dir", rootCmd.PersistentFlags().Lookup("steam-dir"))
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -91,10 +112,20 @@ func initConfig() {
 		viper.SetConfigName(".steam-shortcut-manager")
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	// Read in environment variables that match, e.g. SSM_API_KEY for "api-key"
+	viper.SetEnvPrefix(configEnvPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	viper.AutomaticEnv()
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+		DebugPrintln("Using config file:", viper.ConfigFileUsed())
 	}
+
+	// Use the Chimera and Steam directories from the flag, environment or config
+	chimera.SetConfigDir(viper.GetString("chimera-dir"))
+	steam.SetBaseDir(viper.GetString("steam-dir"))
+
+	// Use configured defaults for any flags that were not given
+	applyConfig(rootCmd)
 }
@@ -24,9 +24,9 @@ SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"path"
+	"strings"
 
 	"github.com/shadowblip/steam-shortcut-manager/pkg/image"
 	"github.com/shadowblip/steam-shortcut-manager/pkg/steamgriddb"
@@ -57,6 +57,44 @@ type SearchOutput struct {
 	Icons   []steamgriddb.ImageResponseData `json:"icons"`
 }
 
+// searchResults are the search outputs of each SteamGridDB game ID. Tables
+// have a row for each image.
+type searchResults map[string]*SearchOutput
+
+// searchImageRow is a single image of a search result
+type searchImageRow struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Style string `json:"style"`
+	Score int    `json:"score"`
+	Nsfw  bool   `json:"nsfw"`
+	Humor bool   `json:"humor"`
+	URL   string `json:"url"`
+}
+
+// Rows will return the images of each search result
+func (s searchResults) Rows() interface{} {
+	rows := map[string][]*searchImageRow{}
+	for id, result := range s {
+		name := result.Details.Name
+		rows[id] = []*searchImageRow{}
+		for _, grid := range result.Grids {
+			rows[id] = append(rows[id], &searchImageRow{name, "grid", grid.Style, grid.Score, grid.Nsfw, grid.Humor, grid.URL})
+		}
+		images := map[string][]steamgriddb.ImageResponseData{
+			"hero": result.Heroes,
+			"logo": result.Logos,
+			"icon": result.Icons,
+		}
+		for _, kind := range []string{"hero", "logo", "icon"} {
+			for _, image := range images[kind] {
+				rows[id] = append(rows[id], &searchImageRow{name, kind, image.Style, image.Score, image.Nsfw, image.Humor, image.URL})
+			}
+		}
+	}
+	return rows
+}
+
 // Prints the search output to the terminal
 func (s *SearchOutput) Print(client *steamgriddb.Client) {
 	fmt.Println(s.Details.Name)
@@ -136,22 +174,27 @@ func search(cmd *cobra.Command, args []string, kind SearchType) {
 	format := rootCmd.PersistentFlags().Lookup("output").Value.String()
 
 	// Ensure we have a SteamGridDB API Key
-	apiKey, _ := cmd.Flags().GetString("api-key")
+	apiKey := getAPIKey(cmd.Flags())
 	if apiKey == "" {
-		cmd.Help()
-		ExitError(fmt.Errorf("API key is required"), format)
+		if format == "term" {
+			cmd.Help()
+		}
+		ExitError(newCommandError(ErrAuth, fmt.Errorf("API key is required (use --api-key or the login command)")), format)
 	}
 
 	// Create a SteamGridDB Client
-	client := steamgriddb.NewClient(apiKey)
+	client, err := newGridDBClient(cmd.Flags(), apiKey)
+	if err != nil {
+		ExitError(err, format)
+	}
 	results, err := client.Search(args[0])
 	if err != nil {
-		panic(err)
+		ExitError(err, format)
 	}
 
 	// Error if not success
 	if !results.Success {
-		panic(fmt.Errorf("%v", results.Errors))
+		ExitError(fmt.Errorf("SteamGridDB search failed: %v", strings.Join(results.Errors, ", ")), format)
 	}
 
 	// Filter our results
@@ -162,7 +205,7 @@ func search(cmd *cobra.Command, args []string, kind SearchType) {
 	results.Data = results.Data[:maxResults]
 
 	// Create a structure to hold our results
-	searchResult := map[string]*SearchOutput{}
+	searchResult := searchResults{}
 
 	// Get all images for each found result
 	for _, result := range results.Data {
@@ -187,7 +230,7 @@ func search(cmd *cobra.Command, args []string, kind SearchType) {
 			// Get the grids
 			grids, err := client.GetGrids(appID, filters...)
 			if err != nil {
-				panic(err)
+				ExitError(err, format)
 			}
 			num := maxImages
 			if num > len(grids.Data) {
@@ -208,7 +251,7 @@ func search(cmd *cobra.Command, args []string, kind SearchType) {
 			// Get the heroes
 			heroes, err := client.GetHeroes(appID, filters...)
 			if err != nil {
-				panic(err)
+				ExitError(err, format)
 			}
 			num := maxImages
 			if num > len(heroes.Data) {
@@ -229,7 +272,7 @@ func search(cmd *cobra.Command, args []string, kind SearchType) {
 			// Get the logos
 			logos, err := client.GetLogos(appID)
 			if err != nil {
-				panic(err)
+				ExitError(err, format)
 			}
 			num := maxImages
 			if num > len(logos.Data) {
@@ -250,7 +293,7 @@ func search(cmd *cobra.Command, args []string, kind SearchType) {
 			// Get the icons
 			icons, err := client.GetIcons(appID)
 			if err != nil {
-				panic(err)
+				ExitError(err, format)
 			}
 			num := maxImages
 			if num > len(icons.Data) {
@@ -263,20 +306,11 @@ func search(cmd *cobra.Command, args []string, kind SearchType) {
 	}
 
 	// Print the output
-	switch format {
-	case "term":
+	printOutput(searchResult, func() {
 		for _, result := range searchResult {
 			result.Print(client)
 		}
-	case "json":
-		out, err := json.MarshalIndent(searchResult, "", "  ")
-		if err != nil {
-			panic(err)
-		}
-		fmt.Println(string(out))
-	default:
-		panic("unknown output format: " + format)
-	}
+	}, "id")
 }
 
 func getFlagInt(cmd *cobra.Command, name string) int {
@@ -333,4 +367,6 @@ func init() {
 	searchCmd.Flags().String("style-grid", "", `Optional grid style to search for ("alternate" "blurred" "white_logo" "material" "no_logo")`)
 	searchCmd.Flags().String("style-icon", "", `Optional icon style to search for ("official" "custom")`)
 	searchCmd.Flags().String("style-logo", "", `Optional logo style to search for ("official" "white" "black" "custom")`)
+	searchCmd.Flags().String("nsfw", "", `Whether to search for NSFW images ("any" "false" "true", default "false")`)
+	searchCmd.Flags().String("humor", "", `Whether to search for humor images ("any" "false" "true", default "any")`)
 }
@@ -24,7 +24,10 @@ SOFTWARE.
 package cmd
 
 import (
+	"github.com/shadowblip/steam-shortcut-manager/pkg/keyring"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steamgriddb"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // steamgriddbCmd represents the steamgriddb command
@@ -37,6 +40,41 @@ var steamgriddbCmd = &cobra.Command{
 	},
 }
 
+// apiKeySecret is the name of the SteamGridDB API key in the keyring
+const apiKeySecret = "steamgriddb-api-key"
+
+// getAPIKey will return the SteamGridDB API key from the --api-key flag or its
+// configured value. Otherwise the key stored with the login command is used.
+func getAPIKey(flags *pflag.FlagSet) string {
+	if apiKey := getFlagOrConfig(flags, "api-key"); apiKey != "" {
+		return apiKey
+	}
+	apiKey, err := keyring.Default().Get(apiKeySecret)
+	if err != nil {
+		DebugPrintln("Unable to find a stored API key:", err)
+		return ""
+	}
+	return apiKey
+}
+
+// newGridDBClient will return a SteamGridDB client for the given API key that
+// includes NSFW and humor images according to the --nsfw and --humor flags,
+// or their configured defaults.
+func newGridDBClient(flags *pflag.FlagSet, apiKey string) (*steamgriddb.Client, error) {
+	nsfw, err := steamgriddb.ParseContentPolicy(getFlagOrConfig(flags, "nsfw"))
+	if err != nil {
+		return nil, err
+	}
+	humor, err := steamgriddb.ParseContentPolicy(getFlagOrConfig(flags, "humor"))
+	if err != nil {
+		return nil, err
+	}
+	client := steamgriddb.NewClient(apiKey)
+	client.SetContentPolicy(nsfw, humor)
+
+	return client, nil
+}
+
 func init() {
 	rootCmd.AddCommand(steamgriddbCmd)
 
@@ -44,8 +82,7 @@ func init() {
 
 	// Cobra supports Persistent Flags which will work for this command
 	// and all subcommands, e.g.:
-	steamgriddbCmd.PersistentFlags().StringP("api-key", "k", "", "SteamGridDB API Key")
-	steamgriddbCmd.MarkFlagRequired("api-key")
+	steamgriddbCmd.PersistentFlags().StringP("api-key", "k", "", "SteamGridDB API Key (env SSM_API_KEY)")
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
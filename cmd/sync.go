@@ -0,0 +1,587 @@
+/*
+MIT License
+
+Copyright © 2022 William Edwards <shadowapex at gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/chimera"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
+	"github.com/spf13/cobra"
+)
+
+// Sync directions
+const (
+	syncPush = "push"
+	syncPull = "pull"
+)
+
+// Sync actions
+const (
+	syncAdded     = "added"
+	syncUpdated   = "updated"
+	syncUnchanged = "unchanged"
+	syncSkipped   = "skipped"
+	syncConflict  = "conflict"
+)
+
+// SyncResult is the result of syncing a single shortcut
+type SyncResult struct {
+	Name      string   `json:"name"`
+	Platform  string   `json:"platform"`
+	User      string   `json:"user"`
+	Action    string   `json:"action"`
+	Changes   []string `json:"changes,omitempty"`
+	Conflicts []string `json:"conflicts,omitempty"`
+	Images    []string `json:"images,omitempty"`
+}
+
+// chimeraSyncCmd represents the chimera sync command
+var chimeraSyncCmd = &cobra.Command{
+	Use:   "sync [name...]",
+	Short: "Sync Chimera shortcuts with Steam shortcuts",
+	Long: `Syncs Chimera shortcuts with the Steam shortcuts of each user. Shortcuts are
+matched by name.
+
+With "--direction push" the Chimera shortcuts of the platform (or of every
+platform with --all) are added to or updated in shortcuts.vdf, and their
+poster, banner, background and logo are copied to the Steam grid images.
+
+With "--direction pull" the Steam shortcuts are added to or updated in the
+Chimera shortcuts. New shortcuts are added to the given platform and their
+grid images are copied into the Chimera images directory.
+
+The command, start directory, hidden state and tags of each shortcut are
+recorded when it is synced, and both sides are compared against them on the
+next sync. Changes made only on the side being synced from are applied.
+Shortcuts that only changed on the other side are skipped, so those changes
+are not lost, and shortcuts that changed on both sides (or that differ and
+were never synced) are conflicts. Both are reported and skipped by default.
+Use "--on-conflict replace" to overwrite the other side, or
+"--on-conflict error" to stop on conflicts without changing anything.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := rootCmd.PersistentFlags().Lookup("output").Value.String()
+		if !chimera.HasChimera() {
+			ExitError(fmt.Errorf("no chimera config found at %v", chimera.GetConfigDir()), format)
+		}
+
+		// Check the sync options
+		direction, _ := cmd.Flags().GetString("direction")
+		if direction != syncPush && direction != syncPull {
+			ExitError(fmt.Errorf("unknown sync direction: %v (supported: %v, %v)", direction, syncPush, syncPull), format)
+		}
+		onConflict, _ := cmd.Flags().GetString("on-conflict")
+		policy, err := chimera.ParseConflictPolicy(onConflict)
+		if err != nil {
+			ExitError(err, format)
+		}
+		if policy == chimera.ConflictAppend {
+			ExitError(fmt.Errorf("conflict policy %v is not supported by sync", policy), format)
+		}
+		users, err := getSelectedUsers(cmd)
+		if err != nil {
+			ExitError(err, format)
+		}
+
+		// Load the Chimera shortcuts
+		all, _ := cmd.Flags().GetBool("all")
+		syncer, err := newChimeraSyncer(getChimeraPlatform(format), all || direction == syncPull)
+		if err != nil {
+			ExitError(err, format)
+		}
+		syncer.state, err = chimera.LoadSyncState(chimera.GetSyncStateFile())
+		if err != nil {
+			ExitError(err, format)
+		}
+		syncer.users = users
+		syncer.policy = policy
+		syncer.names = args
+		syncer.dryRun, _ = cmd.Flags().GetBool("dry-run")
+		if direction == syncPush && !all {
+			syncer.only = syncer.target.Name
+		}
+
+		// Check for conflicts before changing anything
+		if policy == chimera.ConflictError {
+			dryRun := syncer.dryRun
+			syncer.dryRun = true
+			results, _ := syncer.sync(direction)
+			if conflicts := countSyncConflicts(results); conflicts > 0 {
+				printSyncResults(results, format)
+				ExitError(fmt.Errorf("%v shortcuts have conflicting changes (use --on-conflict to skip or replace them)", conflicts), format)
+			}
+			syncer.dryRun = dryRun
+		}
+
+		results, err := syncer.sync(direction)
+		printSyncResults(results, format)
+		if err != nil {
+			ExitError(err, format)
+		}
+	},
+}
+
+// chimeraSyncer syncs Chimera shortcuts with Steam shortcuts
+type chimeraSyncer struct {
+	users  []string
+	policy chimera.ConflictPolicy
+	names  []string
+	dryRun bool
+
+	// target is the platform new Chimera shortcuts are added to
+	target *chimera.Platform
+	// only limits pushing to the given platform
+	only string
+
+	platforms []*chimera.Platform
+	shortcuts map[string][]*chimera.Shortcut
+
+	// state is the base each shortcut is compared against
+	state *chimera.SyncState
+}
+
+// pushedShortcut is a Steam shortcut that was added or updated by push
+type pushedShortcut struct {
+	key           string
+	sc            *chimera.Shortcut
+	steamShortcut *shortcut.Shortcut
+	oldAppID      string
+	result        *SyncResult
+}
+
+// newChimeraSyncer will load the Chimera shortcuts of the target platform, and
+// of every platform with a shortcuts file if all is true.
+func newChimeraSyncer(target *chimera.Platform, all bool) (*chimeraSyncer, error) {
+	s := &chimeraSyncer{
+		target:    target,
+		shortcuts: map[string][]*chimera.Shortcut{},
+	}
+	names := []string{target.Name}
+	if all {
+		found, err := chimera.GetShortcutsPlatforms()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, found...)
+	}
+	for _, name := range names {
+		if _, loaded := s.shortcuts[name]; loaded {
+			continue
+		}
+		platform, err := chimera.GetPlatform(name)
+		if err != nil {
+			DebugPrintln("Skipping shortcuts file of unknown platform:", name)
+			continue
+		}
+		shortcuts := []*chimera.Shortcut{}
+		if chimera.HasShortcutsFile(name) {
+			shortcuts, err = chimera.LoadShortcuts(chimera.GetShortcutsFile(name))
+			if err != nil {
+				return nil, fmt.Errorf("unable to load %v shortcuts: %w", name, err)
+			}
+		}
+		s.platforms = append(s.platforms, platform)
+		s.shortcuts[name] = shortcuts
+	}
+	return s, nil
+}
+
+// sync will sync the shortcuts in the given direction and record the synced
+// shortcuts for the next sync.
+func (s *chimeraSyncer) sync(direction string) ([]*SyncResult, error) {
+	var results []*SyncResult
+	var err error
+	if direction == syncPull {
+		results, err = s.pull()
+	} else {
+		results, err = s.push()
+	}
+	if s.dryRun {
+		return results, err
+	}
+	if saveErr := s.state.Save(); saveErr != nil {
+		err = multierror.Append(err, saveErr)
+	}
+	return results, err
+}
+
+// compare will return the sync action for the given shortcuts and the fields
+// that differ. Both sides are compared against the last sync, so the other
+// side is only overwritten if just the side being synced from changed.
+func (s *chimeraSyncer) compare(user string, sc *chimera.Shortcut, steamShortcut *shortcut.Shortcut, direction string) (string, []string) {
+	fields := chimera.Diff(sc, steamShortcut)
+	if len(fields) == 0 {
+		return syncUnchanged, nil
+	}
+	sourceChanged, targetChanged := s.state.Changed(user, sc, steamShortcut)
+	if direction == syncPull {
+		sourceChanged, targetChanged = targetChanged, sourceChanged
+	}
+	switch {
+	case s.policy == chimera.ConflictReplace || (sourceChanged && !targetChanged):
+		return syncUpdated, fields
+	case !sourceChanged && !targetChanged:
+		// The fields differ in a way that does not matter on this device,
+		// e.g. a command that is not in PATH
+		return syncUnchanged, nil
+	case !sourceChanged:
+		return syncSkipped, fields
+	}
+	return syncConflict, fields
+}
+
+// record will store the given shortcuts as the base for the next sync
+func (s *chimeraSyncer) record(user string, sc *chimera.Shortcut, steamShortcut *shortcut.Shortcut) {
+	if !s.dryRun {
+		s.state.Record(user, sc, steamShortcut)
+	}
+}
+
+// selected will return whether or not the shortcut with the given name should
+// be synced.
+func (s *chimeraSyncer) selected(name string) bool {
+	if len(s.names) == 0 {
+		return true
+	}
+	for _, selected := range s.names {
+		if selected == name {
+			return true
+		}
+	}
+	return false
+}
+
+// push will add or update the Chimera shortcuts in the Steam shortcuts of
+// each user.
+func (s *chimeraSyncer) push() ([]*SyncResult, error) {
+	var errors error
+	results := []*SyncResult{}
+	for _, user := range s.users {
+		if !steam.HasShortcuts(user) {
+			continue
+		}
+		shortcutsPath, _ := steam.GetShortcutsPath(user)
+		steamShortcuts, err := shortcut.Load(shortcutsPath)
+		if err != nil {
+			return results, err
+		}
+
+		pushed := []*pushedShortcut{}
+		for _, platform := range s.platforms {
+			if s.only != "" && platform.Name != s.only {
+				continue
+			}
+			for _, sc := range s.shortcuts[platform.Name] {
+				if !s.selected(sc.Name) {
+					continue
+				}
+				result := &SyncResult{Name: sc.Name, Platform: platform.Name, User: user, Action: syncUnchanged}
+				results = append(results, result)
+
+				// Add or update the Steam shortcut
+				key, steamShortcut, err := steamShortcuts.Lookup(sc.Name)
+				oldAppID := ""
+				if err != nil {
+					steamShortcut = chimera.ToSteamShortcut(sc)
+					result.Action = syncAdded
+				} else {
+					oldAppID = fmt.Sprintf("%v", steamShortcut.Appid)
+					var fields []string
+					result.Action, fields = s.compare(user, sc, steamShortcut, syncPush)
+					if result.Action != syncUpdated {
+						result.Conflicts = fields
+						if result.Action != syncUnchanged {
+							continue
+						}
+					} else {
+						result.Changes = fields
+						chimera.ApplyToSteamShortcut(sc, steamShortcut)
+					}
+				}
+
+				// Find the images that Steam does not have yet. Images of
+				// a shortcut with a new app id are moved to it after saving.
+				appID := oldAppID
+				if appID == "" {
+					appID = fmt.Sprintf("%v", steamShortcut.Appid)
+				}
+				for _, imageType := range chimera.ImageTypes {
+					if !sc.HasImage(imageType) {
+						continue
+					}
+					kind := chimera.SteamImageTypes[imageType]
+					if _, err := steam.GetImage(user, appID, kind); err == nil && result.Action == syncUnchanged {
+						continue
+					}
+					result.Images = append(result.Images, imageType)
+				}
+				if result.Action == syncUnchanged && len(result.Images) > 0 {
+					result.Action = syncUpdated
+				}
+
+				if result.Action == syncUnchanged {
+					s.record(user, sc, steamShortcut)
+					continue
+				}
+				if s.dryRun {
+					continue
+				}
+				if result.Action == syncAdded {
+					err = steamShortcuts.Add(steamShortcut)
+					if err != nil {
+						return results, err
+					}
+				} else {
+					steamShortcuts.Shortcuts[key] = *steamShortcut
+				}
+				pushed = append(pushed, &pushedShortcut{key, sc, steamShortcut, oldAppID, result})
+			}
+		}
+		if len(pushed) == 0 {
+			continue
+		}
+
+		// Write the changes
+		err = shortcut.Save(steamShortcuts, shortcutsPath)
+		if err != nil {
+			return results, err
+		}
+
+		// Move the artwork of shortcuts with a new app id, then copy the
+		// Chimera images over it
+		iconMoved := false
+		for _, p := range pushed {
+			if p.oldAppID != "" {
+				icon := p.steamShortcut.Icon
+				err := moveShortcutAppID(user, p.steamShortcut, p.oldAppID)
+				if err != nil {
+					errors = multierror.Append(errors, err)
+				}
+				if p.steamShortcut.Icon != icon {
+					steamShortcuts.Shortcuts[p.key] = *p.steamShortcut
+					iconMoved = true
+				}
+			}
+			appID := fmt.Sprintf("%v", p.steamShortcut.Appid)
+			for _, imageType := range p.result.Images {
+				kind := chimera.SteamImageTypes[imageType]
+				_, err := steam.SetImage(user, appID, kind, p.sc.GetImage(imageType))
+				if err != nil {
+					errors = multierror.Append(errors, err)
+				}
+			}
+			s.record(user, p.sc, p.steamShortcut)
+		}
+		if iconMoved {
+			err = shortcut.Save(steamShortcuts, shortcutsPath)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+			}
+		}
+
+		// Mirror the tags into Steam library collections
+		for _, p := range pushed {
+			err = addToTagCollections(user, p.steamShortcut)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+			}
+		}
+	}
+
+	return results, errors
+}
+
+// pull will add or update the Steam shortcuts of each user in the Chimera
+// shortcuts.
+func (s *chimeraSyncer) pull() ([]*SyncResult, error) {
+	var errors error
+	results := []*SyncResult{}
+	changed := map[string]bool{}
+	pulled := map[string]bool{}
+	for _, user := range s.users {
+		if !steam.HasShortcuts(user) {
+			continue
+		}
+		shortcutsPath, _ := steam.GetShortcutsPath(user)
+		steamShortcuts, err := shortcut.Load(shortcutsPath)
+		if err != nil {
+			return results, err
+		}
+
+		// Sync the shortcuts in a stable order
+		keys := make([]string, 0, len(steamShortcuts.Shortcuts))
+		for key := range steamShortcuts.Shortcuts {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return steamShortcuts.Shortcuts[keys[i]].AppName < steamShortcuts.Shortcuts[keys[j]].AppName
+		})
+
+		for _, key := range keys {
+			steamShortcut := steamShortcuts.Shortcuts[key]
+			if !s.selected(steamShortcut.AppName) || pulled[steamShortcut.AppName] {
+				continue
+			}
+			pulled[steamShortcut.AppName] = true
+
+			// Add or update the Chimera shortcut
+			platform, sc := s.find(steamShortcut.AppName)
+			result := &SyncResult{Name: steamShortcut.AppName, User: user, Action: syncUnchanged}
+			results = append(results, result)
+			if sc == nil {
+				platform = s.target
+				sc = chimera.FromSteamShortcut(&steamShortcut)
+				result.Action = syncAdded
+				if !s.dryRun {
+					s.shortcuts[platform.Name] = append(s.shortcuts[platform.Name], sc)
+				}
+			} else {
+				var fields []string
+				result.Platform = platform.Name
+				result.Action, fields = s.compare(user, sc, &steamShortcut, syncPull)
+				if result.Action != syncUpdated {
+					result.Conflicts = fields
+					if result.Action != syncUnchanged {
+						continue
+					}
+				} else {
+					result.Changes = fields
+					if !s.dryRun {
+						pulledShortcut := chimera.FromSteamShortcut(&steamShortcut)
+						sc.Cmd = pulledShortcut.Cmd
+						sc.Dir = pulledShortcut.Dir
+						sc.Hidden = pulledShortcut.Hidden
+						sc.Tags = pulledShortcut.Tags
+					}
+				}
+			}
+			result.Platform = platform.Name
+
+			// Copy the images that Chimera does not have yet
+			appID := fmt.Sprintf("%v", steamShortcut.Appid)
+			for _, imageType := range chimera.ImageTypes {
+				src, err := steam.GetImage(user, appID, chimera.SteamImageTypes[imageType])
+				if err != nil {
+					continue
+				}
+				if sc.HasImage(imageType) && result.Action == syncUnchanged {
+					continue
+				}
+				result.Images = append(result.Images, imageType)
+				if s.dryRun {
+					continue
+				}
+				dst, err := chimera.ImportImage(imageType, platform, sc, "", src)
+				if err != nil {
+					errors = multierror.Append(errors, err)
+					continue
+				}
+				sc.SetImage(imageType, dst)
+			}
+			if result.Action == syncUnchanged && len(result.Images) > 0 {
+				result.Action = syncUpdated
+			}
+			if result.Action != syncUnchanged {
+				changed[platform.Name] = true
+			}
+			s.record(user, sc, &steamShortcut)
+		}
+	}
+	if s.dryRun {
+		return results, errors
+	}
+
+	// Write the changes
+	for _, platform := range s.platforms {
+		if !changed[platform.Name] {
+			continue
+		}
+		err := chimera.EnsureShortcutsFileExists(platform.Name)
+		if err != nil {
+			return results, err
+		}
+		err = chimera.SaveShortcuts(chimera.GetShortcutsFile(platform.Name), s.shortcuts[platform.Name])
+		if err != nil {
+			return results, err
+		}
+	}
+
+	return results, errors
+}
+
+// find will return the Chimera shortcut with the given name and its platform
+func (s *chimeraSyncer) find(name string) (*chimera.Platform, *chimera.Shortcut) {
+	for _, platform := range s.platforms {
+		shortcuts := s.shortcuts[platform.Name]
+		if i := chimera.FindShortcut(shortcuts, name); i >= 0 {
+			return platform, shortcuts[i]
+		}
+	}
+	return nil, nil
+}
+
+// countSyncConflicts will return the number of conflicting shortcuts
+func countSyncConflicts(results []*SyncResult) int {
+	count := 0
+	for _, result := range results {
+		if result.Action == syncConflict {
+			count++
+		}
+	}
+	return count
+}
+
+// printSyncResults will print the given sync results in the given format
+func printSyncResults(results []*SyncResult, format string) {
+	printOutput(results, func() {
+		for _, result := range results {
+			fmt.Printf("%-9s %v (%v, user %v)\n", result.Action, result.Name, result.Platform, result.User)
+			if len(result.Changes) > 0 {
+				fmt.Println("  Changed fields:", strings.Join(result.Changes, ", "))
+			}
+			if len(result.Conflicts) > 0 && result.Action == syncSkipped {
+				fmt.Println("  Changed in the other direction:", strings.Join(result.Conflicts, ", "))
+			} else if len(result.Conflicts) > 0 {
+				fmt.Println("  Conflicting fields:", strings.Join(result.Conflicts, ", "))
+			}
+			if len(result.Images) > 0 {
+				fmt.Println("  Images:", strings.Join(result.Images, ", "))
+			}
+		}
+	})
+}
+
+func init() {
+	chimeraCmd.AddCommand(chimeraSyncCmd)
+	chimeraSyncCmd.Flags().StringP("direction", "d", syncPush, "Direction to sync in: 'push' (Chimera to Steam) or 'pull' (Steam to Chimera)")
+	chimeraSyncCmd.Flags().Bool("all", false, "Push the shortcuts of every platform")
+	chimeraSyncCmd.Flags().String("on-conflict", string(chimera.ConflictSkip), "What to do with shortcuts that changed on both sides (skip, replace, error)")
+	chimeraSyncCmd.Flags().String("user", "all", "Steam user ID to sync the shortcuts of")
+	chimeraSyncCmd.Flags().Bool("dry-run", false, "Show the changes without writing them")
+}
@@ -24,7 +24,6 @@ SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
@@ -44,20 +43,11 @@ var usersCmd = &cobra.Command{
 		}
 
 		// Print the output
-		switch format {
-		case "term":
+		printOutput(users, func() {
 			for _, user := range users {
 				fmt.Println(user)
 			}
-		case "json":
-			out, err := json.MarshalIndent(users, "", "  ")
-			if err != nil {
-				panic(err)
-			}
-			fmt.Println(string(out))
-		default:
-			panic("unknown output format: " + format)
-		}
+		}, "user")
 
 	},
 }
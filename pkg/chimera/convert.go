@@ -0,0 +1,215 @@
+package chimera
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
+)
+
+// SteamImageTypes maps Chimera image types to the Steam grid images they are
+// displayed as.
+var SteamImageTypes = map[string]steam.ImageType{
+	ImagePoster:     steam.ImagePortrait,
+	ImageBanner:     steam.ImageLandscape,
+	ImageBackground: steam.ImageHero,
+	ImageLogo:       steam.ImageLogo,
+	ImageIcon:       steam.ImageIcon,
+}
+
+// ToSteamShortcut will convert the given Chimera shortcut into a Steam
+// shortcut. The first word of the command becomes the executable and the rest
+// become the launch options. Images are not included.
+func ToSteamShortcut(sc *Shortcut) *shortcut.Shortcut {
+	exe, args := SplitCommand(sc.Cmd)
+	if exe != "" && !filepath.IsAbs(exe) {
+		if found, err := exec.LookPath(exe); err == nil {
+			exe = found
+		}
+	}
+
+	steamShortcut := shortcut.NewShortcut(sc.Name, shortcut.Quote(exe), shortcut.DefaultShortcut)
+	steamShortcut.LaunchOptions = args
+	steamShortcut.StartDir = shortcut.Quote(ExpandHome(sc.Dir))
+	steamShortcut.IsHidden = boolToInt(sc.Hidden)
+	steamShortcut.SetTags(sc.Tags)
+	steamShortcut.Appid = int64(shortcut.CalculateAppID(steamShortcut.Exe, steamShortcut.AppName))
+
+	return steamShortcut
+}
+
+// FromSteamShortcut will convert the given Steam shortcut into a Chimera
+// shortcut. Images are not included.
+func FromSteamShortcut(sc *shortcut.Shortcut) *Shortcut {
+	// Executables with spaces are quoted so SplitCommand can split them again
+	cmd := shortcut.Unquote(sc.Exe)
+	if strings.ContainsAny(cmd, " \t") {
+		cmd = shortcut.Quote(cmd)
+	}
+	if sc.LaunchOptions != "" {
+		cmd = cmd + " " + sc.LaunchOptions
+	}
+
+	return &Shortcut{
+		Name:   sc.AppName,
+		Cmd:    cmd,
+		Dir:    shortcut.Unquote(sc.StartDir),
+		Hidden: sc.IsHidden != 0,
+		Tags:   sc.TagList(),
+	}
+}
+
+// ApplyToSteamShortcut will update the given Steam shortcut with the fields
+// of the given Chimera shortcut, keeping Steam-only settings like the overlay
+// and play time. Returns whether or not the app id changed.
+func ApplyToSteamShortcut(sc *Shortcut, steamShortcut *shortcut.Shortcut) bool {
+	converted := ToSteamShortcut(sc)
+	oldAppID := steamShortcut.Appid
+
+	steamShortcut.AppName = converted.AppName
+	steamShortcut.Exe = converted.Exe
+	steamShortcut.LaunchOptions = converted.LaunchOptions
+	steamShortcut.StartDir = converted.StartDir
+	steamShortcut.IsHidden = converted.IsHidden
+	steamShortcut.Tags = converted.Tags
+	steamShortcut.Appid = converted.Appid
+
+	return oldAppID != steamShortcut.Appid
+}
+
+// Diff will return the names of the Chimera fields that differ between the
+// given Chimera and Steam shortcuts.
+func Diff(sc *Shortcut, steamShortcut *shortcut.Shortcut) []string {
+	converted := ToSteamShortcut(sc)
+	fields := []string{}
+	if !sameCommand(converted, steamShortcut) {
+		fields = append(fields, "cmd")
+	}
+	if !sameDir(converted.StartDir, steamShortcut.StartDir) {
+		fields = append(fields, "dir")
+	}
+	if converted.IsHidden != steamShortcut.IsHidden {
+		fields = append(fields, "hidden")
+	}
+	if strings.Join(converted.TagList(), "\n") != strings.Join(steamShortcut.TagList(), "\n") {
+		fields = append(fields, "tags")
+	}
+	return fields
+}
+
+// SplitCommand will split the given command line into the executable and
+// its arguments at the first space or tab. The executable may be wrapped in
+// double quotes.
+func SplitCommand(cmd string) (exe, args string) {
+	cmd = strings.TrimSpace(cmd)
+	if strings.HasPrefix(cmd, `"`) {
+		if end := strings.Index(cmd[1:], `"`); end >= 0 {
+			return cmd[1 : end+1], strings.TrimSpace(cmd[end+2:])
+		}
+	}
+	end := strings.IndexAny(cmd, " \t")
+	if end < 0 {
+		return cmd, ""
+	}
+	return cmd[:end], strings.TrimSpace(cmd[end:])
+}
+
+// GetImageDir will return the directory that holds images of the given type
+// for the given platform.
+func GetImageDir(imageType, platform string) string {
+	return path.Join(GetImagesDir(), imageType, platform)
+}
+
+// ImportImage will copy the given image file into the Chimera images
+// directory for the given image type and platform, named as described by
+// Platform.ImageName. Returns the path to the copied image.
+func ImportImage(imageType string, platform *Platform, sc *Shortcut, id, src string) (string, error) {
+	dst, err := getImagePath(imageType, platform, sc, id, src)
+	if err != nil {
+		return "", err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to copy image %v: %w", src, err)
+	}
+
+	return dst, nil
+}
+
+// LinkImage is like ImportImage, but hard links the given image file so the
+// same download can be shared with Steam. The file is copied if it cannot be
+// linked.
+func LinkImage(imageType string, platform *Platform, sc *Shortcut, id, src string) (string, error) {
+	dst, err := getImagePath(imageType, platform, sc, id, src)
+	if err != nil {
+		return "", err
+	}
+	err = steam.LinkFile(src, dst)
+	if err != nil {
+		return "", fmt.Errorf("unable to link image %v: %w", src, err)
+	}
+
+	return dst, nil
+}
+
+// getImagePath will return the path in the Chimera images directory to use
+// for the given image file, creating the directory if needed.
+func getImagePath(imageType string, platform *Platform, sc *Shortcut, id, src string) (string, error) {
+	dir := GetImageDir(imageType, platform.Name)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(dir, platform.ImageName(sc, id)+strings.ToLower(filepath.Ext(src))), nil
+}
+
+// sameCommand will return whether or not the given Steam shortcuts run the
+// same command.
+func sameCommand(a, b *shortcut.Shortcut) bool {
+	if strings.TrimSpace(a.LaunchOptions) != strings.TrimSpace(b.LaunchOptions) {
+		return false
+	}
+	exeA := shortcut.Unquote(a.Exe)
+	exeB := shortcut.Unquote(b.Exe)
+	if exeA == exeB {
+		return true
+	}
+
+	// Commands that are not in PATH on this device are compared by name
+	return !filepath.IsAbs(exeA) && filepath.Base(exeB) == exeA
+}
+
+// sameDir will return whether or not the given start directories are the same
+func sameDir(a, b string) bool {
+	clean := func(dir string) string {
+		return filepath.Clean(shortcut.Unquote(dir))
+	}
+	return clean(a) == clean(b)
+}
+
+// boolToInt will convert the given bool to 1 or 0
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,181 @@
+package chimera
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		exe  string
+		args string
+	}{
+		{"", "", ""},
+		{"/usr/bin/game", "/usr/bin/game", ""},
+		{"/usr/bin/game --fullscreen -x", "/usr/bin/game", "--fullscreen -x"},
+		{`"/opt/My Game/game" --fullscreen`, "/opt/My Game/game", "--fullscreen"},
+		{`"/opt/My Game/game"`, "/opt/My Game/game", ""},
+		{`"/opt/My Game/game"   --level "Level 1"`, "/opt/My Game/game", `--level "Level 1"`},
+		{"  flatpak\trun com.example.App  ", "flatpak", "run com.example.App"},
+		{`"/unterminated game`, `"/unterminated`, "game"},
+	}
+	for _, tt := range tests {
+		exe, args := SplitCommand(tt.cmd)
+		if exe != tt.exe || args != tt.args {
+			t.Errorf("SplitCommand(%q) = %q, %q, want %q, %q", tt.cmd, exe, args, tt.exe, tt.args)
+		}
+	}
+}
+
+func TestToSteamShortcut(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	sc := &Shortcut{
+		Name:   "My Game",
+		Cmd:    `"/opt/My Game/game" --level "Level 1"`,
+		Dir:    "/opt/My Game",
+		Hidden: true,
+		Tags:   []string{"RPG", "Indie"},
+	}
+	steamShortcut := ToSteamShortcut(sc)
+	if steamShortcut.AppName != "My Game" || steamShortcut.Exe != `"/opt/My Game/game"` || steamShortcut.LaunchOptions != `--level "Level 1"` {
+		t.Errorf("unexpected command: %+v", steamShortcut)
+	}
+	if steamShortcut.StartDir != `"/opt/My Game"` || steamShortcut.IsHidden != 1 {
+		t.Errorf("unexpected shortcut: %+v", steamShortcut)
+	}
+	if !reflect.DeepEqual(steamShortcut.TagList(), sc.Tags) {
+		t.Errorf("unexpected tags: %v", steamShortcut.TagList())
+	}
+	if steamShortcut.Appid != int64(shortcut.CalculateAppID(steamShortcut.Exe, steamShortcut.AppName)) {
+		t.Errorf("unexpected app id: %v", steamShortcut.Appid)
+	}
+}
+
+func TestToSteamShortcutPath(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "game")
+	err := os.WriteFile(exe, []byte("#!/bin/sh\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+
+	// Commands are resolved in PATH
+	steamShortcut := ToSteamShortcut(&Shortcut{Name: "Game", Cmd: "game -x"})
+	if steamShortcut.Exe != shortcut.Quote(exe) || steamShortcut.LaunchOptions != "-x" {
+		t.Errorf("unexpected shortcut: %+v", steamShortcut)
+	}
+
+	// Commands that are not in PATH are kept as they are and compared with
+	// the Steam executable by name
+	sc := &Shortcut{Name: "Other", Cmd: "other -x"}
+	steamShortcut = ToSteamShortcut(sc)
+	if steamShortcut.Exe != `"other"` {
+		t.Errorf("unexpected exe: %v", steamShortcut.Exe)
+	}
+	steamShortcut.Exe = `"/usr/bin/other"`
+	if fields := Diff(sc, steamShortcut); len(fields) != 0 {
+		t.Errorf("unexpected differences: %v", fields)
+	}
+}
+
+func TestFromSteamShortcut(t *testing.T) {
+	tests := []struct {
+		name          string
+		exe           string
+		launchOptions string
+		cmd           string
+	}{
+		{"quoted", `"/usr/bin/game"`, "", "/usr/bin/game"},
+		{"arguments", `"/usr/bin/game"`, "-x -y", "/usr/bin/game -x -y"},
+		{"spaces", `"/opt/My Game/game"`, "", `"/opt/My Game/game"`},
+		{"spaces and arguments", `"/opt/My Game/game"`, `--level "Level 1"`, `"/opt/My Game/game" --level "Level 1"`},
+		{"unquoted spaces", "/opt/My Game/game", "-x", `"/opt/My Game/game" -x`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steamShortcut := shortcut.NewShortcut("Game", tt.exe, shortcut.DefaultShortcut)
+			steamShortcut.LaunchOptions = tt.launchOptions
+			steamShortcut.StartDir = `"/opt/My Game"`
+			steamShortcut.SetTags([]string{"RPG"})
+			sc := FromSteamShortcut(steamShortcut)
+			if sc.Name != "Game" || sc.Cmd != tt.cmd || sc.Dir != "/opt/My Game" || !reflect.DeepEqual(sc.Tags, []string{"RPG"}) {
+				t.Errorf("unexpected shortcut: %+v", sc)
+			}
+		})
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	chimeraShortcuts := []*Shortcut{
+		{Name: "Game", Cmd: "/usr/bin/game", Dir: "/usr/bin", Tags: []string{}},
+		{Name: "Args", Cmd: "/usr/bin/game --fullscreen", Dir: "/usr/bin", Hidden: true, Tags: []string{"RPG", "Indie"}},
+		{Name: "Spaces", Cmd: `"/opt/My Game/game" --level "Level 1"`, Dir: "/opt/My Game", Tags: []string{"Windows"}},
+		{Name: "Quoted only", Cmd: `"/opt/My Game/game"`, Dir: "/opt/My Game", Tags: []string{}},
+	}
+	for _, sc := range chimeraShortcuts {
+		t.Run(sc.Name, func(t *testing.T) {
+			// Chimera to Steam and back
+			steamShortcut := ToSteamShortcut(sc)
+			converted := FromSteamShortcut(steamShortcut)
+			if !reflect.DeepEqual(converted, sc) {
+				t.Errorf("unexpected shortcut:\n%+v\nwant:\n%+v", converted, sc)
+			}
+			if fields := Diff(sc, steamShortcut); len(fields) != 0 {
+				t.Errorf("unexpected differences: %v", fields)
+			}
+
+			// Steam to Chimera and back
+			again := ToSteamShortcut(FromSteamShortcut(steamShortcut))
+			if again.Exe != steamShortcut.Exe || again.LaunchOptions != steamShortcut.LaunchOptions || again.Appid != steamShortcut.Appid {
+				t.Errorf("unexpected shortcut:\n%+v\nwant:\n%+v", again, steamShortcut)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	sc := &Shortcut{Name: "Game", Cmd: "/usr/bin/game -x", Dir: "/usr/bin", Tags: []string{"RPG"}}
+	steamShortcut := ToSteamShortcut(sc)
+	steamShortcut.StartDir = `"/usr/bin/"`
+	if fields := Diff(sc, steamShortcut); len(fields) != 0 {
+		t.Errorf("expected a trailing slash not to matter, got %v", fields)
+	}
+
+	steamShortcut.LaunchOptions = "-y"
+	steamShortcut.StartDir = `"/opt"`
+	steamShortcut.IsHidden = 1
+	steamShortcut.SetTags([]string{"Indie"})
+	if fields := Diff(sc, steamShortcut); !reflect.DeepEqual(fields, []string{"cmd", "dir", "hidden", "tags"}) {
+		t.Errorf("unexpected differences: %v", fields)
+	}
+}
+
+func TestApplyToSteamShortcut(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	steamShortcut := ToSteamShortcut(&Shortcut{Name: "Game", Cmd: "/usr/bin/game"})
+	steamShortcut.AllowOverlay = 0
+	steamShortcut.LastPlayTime = 1700000000
+
+	// Steam-only settings are kept
+	if ApplyToSteamShortcut(&Shortcut{Name: "Game", Cmd: "/usr/bin/game -x"}, steamShortcut) {
+		t.Error("expected the app id not to change")
+	}
+	if steamShortcut.LaunchOptions != "-x" || steamShortcut.AllowOverlay != 0 || steamShortcut.LastPlayTime != 1700000000 {
+		t.Errorf("unexpected shortcut: %+v", steamShortcut)
+	}
+
+	if !ApplyToSteamShortcut(&Shortcut{Name: "Game", Cmd: `"/opt/My Game/game"`}, steamShortcut) {
+		t.Error("expected the app id to change")
+	}
+	if steamShortcut.Exe != `"/opt/My Game/game"` || steamShortcut.LaunchOptions != "" {
+		t.Errorf("unexpected shortcut: %+v", steamShortcut)
+	}
+}
@@ -4,24 +4,50 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
 )
 
-var homeDir, _ = os.UserHomeDir()
+// configDir overrides the Chimera data directory when set
+var configDir string
 
-var ConfigDir = path.Join(homeDir, ".local/share/chimera")
-var ShortcutsDir = path.Join(ConfigDir, "shortcuts")
-var ImagesDir = path.Join(ConfigDir, "images")
+// SetConfigDir will use the given directory as the Chimera data directory
+// instead of the default. A leading "~" is expanded to the home directory.
+// An empty directory restores the default.
+func SetConfigDir(dir string) {
+	configDir = ExpandHome(dir)
+}
 
-var BannerDir = path.Join(ImagesDir, "banner")
-var LogoDir = path.Join(ImagesDir, "logo")
-var PosterDir = path.Join(ImagesDir, "poster")
-var BackgroundDir = path.Join(ImagesDir, "background")
+// GetConfigDir will return the Chimera data directory. Unless it was set with
+// SetConfigDir, this is "chimera" in $XDG_DATA_HOME (~/.local/share).
+func GetConfigDir() string {
+	if configDir != "" {
+		return configDir
+	}
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		homeDir, _ := os.UserHomeDir()
+		dataHome = path.Join(homeDir, ".local", "share")
+	}
+	return path.Join(dataHome, "chimera")
+}
 
-var SupportedPlatforms = []string{"flathub"}
+// GetShortcutsDir will return the directory with the Chimera shortcuts files
+func GetShortcutsDir() string {
+	return path.Join(GetConfigDir(), "shortcuts")
+}
+
+// GetImagesDir will return the directory with the Chimera images
+func GetImagesDir() string {
+	return path.Join(GetConfigDir(), "images")
+}
 
 // HasChimera will return whether or not Chimera has a configuration directory
 func HasChimera() bool {
-	if _, err := os.Stat(ConfigDir); !os.IsNotExist(err) {
+	if _, err := os.Stat(GetConfigDir()); !os.IsNotExist(err) {
 		return true
 	}
 	return false
@@ -50,16 +76,44 @@ func EnsureShortcutsFileExists(platform string) error {
 // GetShortcutsFile will return the path to the shortcuts file for the given
 // platform (e.g. flathub)
 func GetShortcutsFile(platform string) string {
-	return path.Join(ShortcutsDir, fmt.Sprintf("chimera.%s.yaml", platform))
+	return path.Join(GetShortcutsDir(), fmt.Sprintf("chimera.%s.yaml", platform))
 }
 
-// IsPlatformSupported will return whether or not the given Chimera platform
-// is supported by the shortcut manager.
-func IsPlatformSupported(platform string) bool {
-	for _, plat := range SupportedPlatforms {
-		if plat == platform {
-			return true
-		}
+// GetShortcutsFilePlatform will return the platform of the given shortcuts
+// file based on its name (e.g. chimera.flathub.yaml).
+func GetShortcutsFilePlatform(file string) (*Platform, error) {
+	name := path.Base(file)
+	if !strings.HasPrefix(name, "chimera.") || !strings.HasSuffix(name, ".yaml") {
+		return nil, fmt.Errorf("unable to get chimera platform of shortcuts file %v: expected a name like chimera.<platform>.yaml", file)
 	}
-	return false
+	return GetPlatform(strings.TrimSuffix(strings.TrimPrefix(name, "chimera."), ".yaml"))
+}
+
+// GetShortcutsPlatforms will return the platforms that have a shortcuts file
+// in the Chimera shortcuts directory.
+func GetShortcutsPlatforms() ([]string, error) {
+	files, err := filepath.Glob(path.Join(GetShortcutsDir(), "chimera.*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	platforms := []string{}
+	for _, file := range files {
+		name := strings.TrimSuffix(strings.TrimPrefix(path.Base(file), "chimera."), ".yaml")
+		platforms = append(platforms, name)
+	}
+	sort.Strings(platforms)
+	return platforms, nil
+}
+
+// HasShortcutsFile will return whether or not the shortcuts file for the given
+// platform exists.
+func HasShortcutsFile(platform string) bool {
+	_, err := os.Stat(GetShortcutsFile(platform))
+	return err == nil
+}
+
+// ExpandHome will replace a leading "~" in the given path with the user's
+// home directory.
+func ExpandHome(dir string) string {
+	return steam.ExpandHome(dir)
 }
@@ -0,0 +1,219 @@
+package chimera
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/fileutil"
+	"gopkg.in/yaml.v3"
+)
+
+// shortcutKeys are the YAML keys of the fields in a Shortcut. Any other keys
+// in a shortcut are kept as they are when saving.
+var shortcutKeys = getShortcutKeys()
+
+// LoadShortcuts will load all Chimera shortcuts from the given YAML file.
+func LoadShortcuts(path string) ([]*Shortcut, error) {
+	doc, err := readDocument(path)
+	if err != nil {
+		return nil, err
+	}
+
+	shortcuts := []*Shortcut{}
+	for _, node := range doc.Content[0].Content {
+		sc := &Shortcut{}
+		err := node.Decode(sc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shortcut in %v at line %v: %w", path, node.Line, err)
+		}
+		sc.node = node
+		shortcuts = append(shortcuts, sc)
+	}
+	return shortcuts, nil
+}
+
+// SaveShortcuts will save the given Chimera shortcuts to the given path.
+// Shortcuts that were loaded from a file keep their comments, key order and
+// any keys that are not part of Shortcut. The file is replaced atomically.
+func SaveShortcuts(path string, shortcuts []*Shortcut) error {
+	doc, err := readDocument(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if doc == nil {
+		doc = newDocument()
+	}
+
+	// Replace the list of shortcuts, keeping the comments of the document
+	list := doc.Content[0]
+	list.Content = []*yaml.Node{}
+	for _, sc := range shortcuts {
+		node, err := sc.toNode()
+		if err != nil {
+			return err
+		}
+		list.Content = append(list.Content, node)
+	}
+	if len(list.Content) > 0 {
+		list.Style = 0
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	err = encoder.Encode(doc)
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err != nil {
+		return err
+	}
+	return fileutil.ReplaceFile(path, buf.Bytes(), 0644)
+}
+
+// toNode will return the YAML mapping for the shortcut. If the shortcut was
+// loaded from a file, its original mapping is updated so unknown keys,
+// comments and ordering are kept.
+func (s *Shortcut) toNode() (*yaml.Node, error) {
+	encoded := &yaml.Node{}
+	err := encoded.Encode(s)
+	if err != nil {
+		return nil, err
+	}
+	if s.node == nil || s.node.Kind != yaml.MappingNode {
+		s.node = encoded
+		return encoded, nil
+	}
+
+	// Update or remove the known keys of the original mapping
+	node := s.node
+	values := mappingValues(encoded)
+	content := []*yaml.Node{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !shortcutKeys[key.Value] {
+			content = append(content, key, value)
+			continue
+		}
+		newValue, ok := values[key.Value]
+		if !ok {
+			continue
+		}
+		delete(values, key.Value)
+		if !nodesEqual(value, newValue) {
+			newValue.HeadComment = value.HeadComment
+			newValue.LineComment = value.LineComment
+			newValue.FootComment = value.FootComment
+			value = newValue
+		}
+		content = append(content, key, value)
+	}
+
+	// Add the keys that were not in the original mapping, unless they are
+	// empty so that unchanged shortcuts stay the same.
+	for i := 0; i+1 < len(encoded.Content); i += 2 {
+		key, value := encoded.Content[i], encoded.Content[i+1]
+		if _, ok := values[key.Value]; ok && !isEmptyNode(value) {
+			content = append(content, key, value)
+		}
+	}
+	node.Content = content
+
+	return node, nil
+}
+
+// readDocument will read the YAML document at the given path. Empty files
+// return a document with an empty list of shortcuts.
+func readDocument(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &yaml.Node{}
+	err = yaml.Unmarshal(data, doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return newDocument(), nil
+	}
+
+	// A document with only comments or "null" has no shortcuts
+	root := doc.Content[0]
+	if root.Kind == yaml.ScalarNode && root.ShortTag() == "!!null" {
+		list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		list.HeadComment = root.HeadComment
+		list.FootComment = root.FootComment
+		doc.Content[0] = list
+		return doc, nil
+	}
+	if root.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("invalid shortcuts file %v: expected a list of shortcuts", path)
+	}
+	return doc, nil
+}
+
+// newDocument will return a YAML document with an empty list of shortcuts
+func newDocument() *yaml.Node {
+	return &yaml.Node{
+		Kind:    yaml.DocumentNode,
+		Content: []*yaml.Node{{Kind: yaml.SequenceNode, Tag: "!!seq"}},
+	}
+}
+
+// mappingValues will return the values of the given mapping node by key
+func mappingValues(node *yaml.Node) map[string]*yaml.Node {
+	values := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		values[node.Content[i].Value] = node.Content[i+1]
+	}
+	return values
+}
+
+// nodesEqual will return whether or not the given nodes hold the same values,
+// ignoring their style and comments.
+func nodesEqual(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || len(a.Content) != len(b.Content) {
+		return false
+	}
+	if a.Kind == yaml.ScalarNode && (a.Value != b.Value || a.ShortTag() != b.ShortTag()) {
+		return false
+	}
+	for i := range a.Content {
+		if !nodesEqual(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmptyNode will return whether or not the given node holds a zero value
+func isEmptyNode(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value == "" || node.ShortTag() == "!!null" || (node.ShortTag() == "!!bool" && node.Value == "false")
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(node.Content) == 0
+	}
+	return false
+}
+
+// getShortcutKeys will return the YAML keys of the fields in a Shortcut
+func getShortcutKeys() map[string]bool {
+	keys := map[string]bool{}
+	t := reflect.TypeOf(Shortcut{})
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("yaml")
+		name := strings.Split(tag, ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		keys[name] = true
+	}
+	return keys
+}
@@ -0,0 +1,274 @@
+package chimera
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// copyFixture will copy the given test fixture into a temporary directory and
+// return its path.
+func copyFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), name)
+	err = os.WriteFile(file, data, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func readFile(t *testing.T, file string) string {
+	t.Helper()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestLoadShortcuts(t *testing.T) {
+	shortcuts, err := LoadShortcuts(filepath.Join("testdata", "chimera.flathub.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shortcuts) != 2 {
+		t.Fatalf("expected 2 shortcuts, got %v", len(shortcuts))
+	}
+	sc := shortcuts[1]
+	if sc.Name != "SuperTux" || sc.Cmd != "flatpak run org.supertuxproject.SuperTux" {
+		t.Errorf("unexpected shortcut: %+v", sc)
+	}
+	if !reflect.DeepEqual(sc.Tags, []string{"Flathub", "Platformer"}) {
+		t.Errorf("unexpected tags: %v", sc.Tags)
+	}
+	if sc.Logo != "" || !strings.HasSuffix(sc.Poster, "org.supertuxproject.SuperTux.png") {
+		t.Errorf("unexpected images: %+v", sc)
+	}
+}
+
+func TestLoadShortcutsEmpty(t *testing.T) {
+	for name, content := range map[string]string{
+		"empty":    "",
+		"null":     "null\n",
+		"comments": "# no shortcuts yet\n",
+		"list":     "[]\n",
+	} {
+		file := filepath.Join(t.TempDir(), "chimera.manual.yaml")
+		err := os.WriteFile(file, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shortcuts, err := LoadShortcuts(file)
+		if err != nil {
+			t.Errorf("%v: %v", name, err)
+			continue
+		}
+		if len(shortcuts) != 0 {
+			t.Errorf("%v: expected no shortcuts, got %v", name, len(shortcuts))
+		}
+	}
+}
+
+func TestLoadShortcutsInvalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "chimera.manual.yaml")
+	err := os.WriteFile(file, []byte("name: not a list\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadShortcuts(file); err == nil {
+		t.Error("expected an error for a file that is not a list")
+	}
+}
+
+func TestSaveShortcutsUnchanged(t *testing.T) {
+	file := copyFixture(t, "chimera.manual.yaml")
+	shortcuts, err := LoadShortcuts(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SaveShortcuts(file, shortcuts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := readFile(t, file)
+	for _, want := range []string{
+		"# Shortcuts added by hand.",
+		"# Streaming\n",
+		"# Windows games are run through Proton\n",
+		`launch_options: "--fullscreen"`,
+		`"Streaming" # shown in the Streaming collection`,
+		`compat_tool: "proton_8"`,
+		`compat_config: "noesync"`,
+	} {
+		if !strings.Contains(saved, want) {
+			t.Errorf("saved file is missing %q:\n%v", want, saved)
+		}
+	}
+
+	// Saving again should not change anything
+	shortcuts, err = LoadShortcuts(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SaveShortcuts(file, shortcuts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again := readFile(t, file); again != saved {
+		t.Errorf("saving twice changed the file:\n%v\n---\n%v", saved, again)
+	}
+}
+
+func TestSaveShortcutsNoNewKeys(t *testing.T) {
+	file := copyFixture(t, "chimera.flathub.yaml")
+	shortcuts, err := LoadShortcuts(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SaveShortcuts(file, shortcuts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Chimera does not write a "dir" for Flathub shortcuts
+	saved := readFile(t, file)
+	if strings.Contains(saved, "dir:") {
+		t.Errorf("empty keys were added to unchanged shortcuts:\n%v", saved)
+	}
+	loaded, err := LoadShortcuts(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range shortcuts {
+		shortcuts[i].node, loaded[i].node = nil, nil
+	}
+	if !reflect.DeepEqual(shortcuts, loaded) {
+		t.Errorf("shortcuts changed after saving:\n%v", saved)
+	}
+}
+
+func TestSaveShortcutsEdit(t *testing.T) {
+	file := copyFixture(t, "chimera.manual.yaml")
+	shortcuts, err := LoadShortcuts(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Edit, remove and add shortcuts
+	shortcuts[1].Hidden = false
+	shortcuts[1].Poster = "/tmp/poster.png"
+	shortcuts[1].Tags = []string{"Proton"}
+	shortcuts = append(shortcuts[1:], NewShortcut("New Game", "/usr/bin/new-game"))
+	err = SaveShortcuts(file, shortcuts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := readFile(t, file)
+
+	loaded, err := LoadShortcuts(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 2 || loaded[0].Name != "Windows Game" || loaded[1].Name != "New Game" {
+		t.Fatalf("unexpected shortcuts: %v", saved)
+	}
+	if loaded[0].Hidden || loaded[0].Poster != "/tmp/poster.png" || !reflect.DeepEqual(loaded[0].Tags, []string{"Proton"}) {
+		t.Errorf("edit was not saved: %+v", loaded[0])
+	}
+	if strings.Contains(saved, "Steam Link") {
+		t.Errorf("removed shortcut is still in the file:\n%v", saved)
+	}
+
+	// Unknown keys and comments of the edited shortcut are kept in order
+	want := []string{
+		"# Windows games are run through Proton",
+		"name: \"Windows Game\"",
+		"compat_tool: \"proton_8\"",
+		"compat_config: \"noesync\"",
+		"hidden: false",
+		"poster: /tmp/poster.png",
+		"name: New Game",
+	}
+	last := -1
+	for _, s := range want {
+		i := strings.Index(saved, s)
+		if i < 0 {
+			t.Errorf("saved file is missing %q:\n%v", s, saved)
+			continue
+		}
+		if i < last {
+			t.Errorf("%q is out of order:\n%v", s, saved)
+		}
+		last = i
+	}
+}
+
+func TestSaveShortcutsRemovesEmptyImages(t *testing.T) {
+	file := copyFixture(t, "chimera.flathub.yaml")
+	shortcuts, err := LoadShortcuts(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shortcuts[0].Background = ""
+	err = SaveShortcuts(file, shortcuts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := readFile(t, file)
+	if strings.Contains(saved, "background:") {
+		t.Errorf("empty background was not removed:\n%v", saved)
+	}
+	if !strings.Contains(saved, "banner: /home/gamer/.local/share/chimera/images/banner/flathub/com.moonlight_stream.Moonlight.png") {
+		t.Errorf("banner was not kept:\n%v", saved)
+	}
+}
+
+func TestSaveShortcutsNewFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "chimera.manual.yaml")
+	err := SaveShortcuts(file, []*Shortcut{NewShortcut("Game", "/usr/bin/game", DefaultShortcut)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadShortcuts(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 1 || loaded[0].Name != "Game" || !loaded[0].HasTag("ChimeraOS Playable") {
+		t.Errorf("unexpected shortcuts: %+v", loaded)
+	}
+}
+
+func TestSaveShortcutsAtomic(t *testing.T) {
+	file := copyFixture(t, "chimera.flathub.yaml")
+	shortcuts, err := LoadShortcuts(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SaveShortcuts(file, shortcuts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The file mode is kept and no temporary files are left behind
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected file mode 0600, got %v", info.Mode().Perm())
+	}
+	entries, err := os.ReadDir(filepath.Dir(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the shortcuts file, got %v entries", len(entries))
+	}
+}
@@ -0,0 +1,227 @@
+package chimera
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/roms"
+)
+
+// Kinds of Chimera platforms
+const (
+	// KindStore platforms launch games installed from a store or app repo
+	KindStore = "store"
+	// KindEmulator platforms launch ROMs with an emulator
+	KindEmulator = "emulator"
+	// KindManual platforms launch any command
+	KindManual = "manual"
+)
+
+// Fields that identify a game on a platform. These are also the names of the
+// command-line flags that set them.
+const (
+	FieldFlatpakID = "flatpak-id"
+	FieldGOGID     = "gog-id"
+	FieldEpicID    = "epic-id"
+	FieldROM       = "rom"
+	FieldCore      = "core"
+)
+
+// unsafeFileChars matches characters that should not be used in image names
+var unsafeFileChars = regexp.MustCompile(`[/\\:*?"<>|]+`)
+
+// Platform is a Chimera shortcuts platform. Each platform has its own
+// chimera.<platform>.yaml shortcuts file and image directories.
+type Platform struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+	Kind  string `json:"kind"`
+	// IDField is the field that identifies a game on the platform. Images
+	// are named after it.
+	IDField string `json:"idField,omitempty"`
+	// Fields are the fields that are required to add a shortcut
+	Fields []string `json:"fields"`
+
+	// command builds the shortcut command from the platform's fields. If it
+	// is nil, the command must always be given.
+	command func(fields map[string]string) (string, error)
+}
+
+// platforms are all supported Chimera platforms by name
+var platforms = map[string]*Platform{}
+
+// SupportedPlatforms are the names of all supported Chimera platforms
+var SupportedPlatforms = registerPlatforms()
+
+// GetPlatform will return the supported platform with the given name
+func GetPlatform(name string) (*Platform, error) {
+	if !IsPlatformSupported(name) {
+		return nil, fmt.Errorf("unsupported chimera platform: %v (supported: %v)", name, strings.Join(SupportedPlatforms, ", "))
+	}
+	return platforms[name], nil
+}
+
+// GetPlatforms will return all supported platforms sorted by name
+func GetPlatforms() []*Platform {
+	list := make([]*Platform, 0, len(SupportedPlatforms))
+	for _, name := range SupportedPlatforms {
+		list = append(list, platforms[name])
+	}
+	return list
+}
+
+// IsPlatformSupported will return whether or not the given Chimera platform
+// is supported by the shortcut manager.
+func IsPlatformSupported(platform string) bool {
+	_, ok := platforms[platform]
+	return ok
+}
+
+// AllFields will return the fields used by any platform
+func AllFields() []string {
+	seen := map[string]bool{}
+	fields := []string{}
+	for _, platform := range GetPlatforms() {
+		for _, field := range platform.Fields {
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// HasField will return whether or not the platform uses the given field
+func (p *Platform) HasField(field string) bool {
+	for _, f := range p.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateFields will check that all fields required by the platform are set
+// and that no fields of other platforms are set.
+func (p *Platform) ValidateFields(fields map[string]string) error {
+	for _, field := range p.Fields {
+		if fields[field] == "" {
+			return fmt.Errorf("%v is required for %v platform", field, p.Name)
+		}
+	}
+	for field, value := range fields {
+		if value != "" && !p.HasField(field) {
+			return fmt.Errorf("%v is not used by %v platform", field, p.Name)
+		}
+	}
+	return nil
+}
+
+// Command will return the command that launches the game with the given
+// fields. Returns an error if the platform cannot build a command.
+func (p *Platform) Command(fields map[string]string) (string, error) {
+	if p.command == nil {
+		return "", fmt.Errorf("a command is required for %v platform", p.Name)
+	}
+	return p.command(fields)
+}
+
+// ImageName will return the base file name, without an extension, that
+// Chimera uses for the images of the given shortcut. The images are named
+// after the given value of the platform's ID field (e.g. a Flatpak ID or ROM
+// path) if it is known, and after the shortcut name otherwise.
+func (p *Platform) ImageName(sc *Shortcut, id string) string {
+	if p.IDField != "" && id != "" {
+		if p.IDField == FieldROM {
+			return strings.TrimSuffix(filepath.Base(id), filepath.Ext(id))
+		}
+		return id
+	}
+	return unsafeFileChars.ReplaceAllString(sc.Name, "_")
+}
+
+// registerPlatforms will add the built-in platforms and return their names
+func registerPlatforms() []string {
+	register := func(p *Platform) {
+		platforms[p.Name] = p
+	}
+	register(&Platform{
+		Name:    "flathub",
+		Title:   "Flathub",
+		Kind:    KindStore,
+		IDField: FieldFlatpakID,
+		Fields:  []string{FieldFlatpakID},
+		command: func(fields map[string]string) (string, error) {
+			return "flatpak run " + fields[FieldFlatpakID], nil
+		},
+	})
+	register(&Platform{
+		Name:    "gog",
+		Title:   "GOG",
+		Kind:    KindStore,
+		IDField: FieldGOGID,
+		Fields:  []string{FieldGOGID},
+	})
+	register(&Platform{
+		Name:    "epic-store",
+		Title:   "Epic Games Store",
+		Kind:    KindStore,
+		IDField: FieldEpicID,
+		Fields:  []string{FieldEpicID},
+		command: func(fields map[string]string) (string, error) {
+			return "legendary launch " + fields[FieldEpicID], nil
+		},
+	})
+	register(&Platform{
+		Name:    "retroarch",
+		Title:   "RetroArch",
+		Kind:    KindEmulator,
+		IDField: FieldROM,
+		Fields:  []string{FieldROM, FieldCore},
+		command: func(fields map[string]string) (string, error) {
+			return fmt.Sprintf("retroarch -L %v %v", fields[FieldCore], quote(fields[FieldROM])), nil
+		},
+	})
+	register(&Platform{
+		Name:  "manual",
+		Title: "Manual",
+		Kind:  KindManual,
+	})
+
+	// Each emulated system has its own platform
+	for _, system := range roms.DefaultPlatforms().Sorted() {
+		system := system
+		register(&Platform{
+			Name:    system.Name,
+			Title:   system.Title,
+			Kind:    KindEmulator,
+			IDField: FieldROM,
+			Fields:  []string{FieldROM},
+			command: func(fields map[string]string) (string, error) {
+				rom := &roms.ROM{Path: fields[FieldROM], Title: roms.CleanTitle(fields[FieldROM]), Platform: system}
+				sc, err := rom.Shortcut()
+				if err != nil {
+					return "", err
+				}
+				return strings.TrimSpace(sc.Exe + " " + sc.LaunchOptions), nil
+			},
+		})
+	}
+
+	names := make([]string, 0, len(platforms))
+	for name := range platforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// quote will wrap the given string in double quotes for a shell command
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
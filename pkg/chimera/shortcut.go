@@ -1,34 +1,86 @@
 package chimera
 
 import (
-	"io/ioutil"
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
-// LoadShortcuts will load all Chimera shortcuts from the given YAML file.
-func LoadShortcuts(path string) ([]*Shortcut, error) {
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return nil, err
+// Image types of a Chimera shortcut
+const (
+	ImagePoster     = "poster"
+	ImageBanner     = "banner"
+	ImageLogo       = "logo"
+	ImageBackground = "background"
+	ImageIcon       = "icon"
+)
+
+// ImageTypes are all the image types a Chimera shortcut can have
+var ImageTypes = []string{ImagePoster, ImageBanner, ImageLogo, ImageBackground, ImageIcon}
+
+// ConflictPolicy decides what happens when adding a shortcut with the same
+// name as an existing one.
+type ConflictPolicy string
+
+// Supported conflict policies
+const (
+	ConflictError   ConflictPolicy = "error"
+	ConflictSkip    ConflictPolicy = "skip"
+	ConflictReplace ConflictPolicy = "replace"
+	ConflictAppend  ConflictPolicy = "append"
+)
+
+// ConflictPolicies are all the supported conflict policies
+var ConflictPolicies = []ConflictPolicy{ConflictError, ConflictSkip, ConflictReplace, ConflictAppend}
+
+// ErrShortcutExists is returned when adding a shortcut that already exists
+var ErrShortcutExists = errors.New("shortcut already exists")
+
+// ParseConflictPolicy will return the conflict policy with the given name
+func ParseConflictPolicy(name string) (ConflictPolicy, error) {
+	names := []string{}
+	for _, policy := range ConflictPolicies {
+		if string(policy) == name {
+			return policy, nil
+		}
+		names = append(names, string(policy))
 	}
+	return "", fmt.Errorf("unknown conflict policy: %v (supported: %v)", name, strings.Join(names, ", "))
+}
 
-	var shortcuts []*Shortcut
-	err = yaml.Unmarshal(data, &shortcuts)
-	if err != nil {
-		return nil, err
+// FindShortcut will return the index of the shortcut with the given name, or
+// -1 if there is none.
+func FindShortcut(shortcuts []*Shortcut, name string) int {
+	for i, sc := range shortcuts {
+		if sc.Name == name {
+			return i
+		}
 	}
-	return shortcuts, nil
+	return -1
 }
 
-// SaveShortcuts will save the given Chimera shortcuts to the given path.
-func SaveShortcuts(path string, shortcuts []*Shortcut) error {
-	bytes, err := yaml.Marshal(shortcuts)
-	if err != nil {
-		return err
+// AddShortcut will add the given shortcut to the list of shortcuts using the
+// given policy if a shortcut with the same name already exists. It returns
+// the new list of shortcuts and whether or not the shortcut was added. A
+// replaced shortcut keeps the comments and unknown keys it was loaded with.
+func AddShortcut(shortcuts []*Shortcut, sc *Shortcut, policy ConflictPolicy) ([]*Shortcut, bool, error) {
+	i := FindShortcut(shortcuts, sc.Name)
+	if i < 0 || policy == ConflictAppend {
+		return append(shortcuts, sc), true, nil
 	}
-	return os.WriteFile(path, bytes, 0644)
+	switch policy {
+	case ConflictSkip:
+		return shortcuts, false, nil
+	case ConflictReplace:
+		node := shortcuts[i].node
+		*shortcuts[i] = *sc
+		shortcuts[i].node = node
+		return shortcuts, true, nil
+	}
+	return shortcuts, false, fmt.Errorf("%w: %v", ErrShortcutExists, sc.Name)
 }
 
 // ShortcutSetting is a function that mutates a Chimera Shortcut
@@ -59,8 +111,67 @@ type Shortcut struct {
 	Cmd        string   `yaml:"cmd" json:"cmd"`
 	Dir        string   `yaml:"dir" json:"dir"`
 	Hidden     bool     `yaml:"hidden" json:"hidden"`
+	Icon       string   `yaml:"icon,omitempty" json:"icon,omitempty"`
 	Logo       string   `yaml:"logo,omitempty" json:"logo,omitempty"`
 	Name       string   `yaml:"name" json:"name"`
 	Poster     string   `yaml:"poster,omitempty" json:"poster,omitempty"`
 	Tags       []string `yaml:"tags" json:"tags"`
+
+	// node is the YAML mapping the shortcut was loaded from. It holds the
+	// comments and fields that are not part of this structure.
+	node *yaml.Node
+}
+
+// GetImage will return the path to the image of the given type
+func (s *Shortcut) GetImage(imageType string) string {
+	switch imageType {
+	case ImagePoster:
+		return s.Poster
+	case ImageBanner:
+		return s.Banner
+	case ImageLogo:
+		return s.Logo
+	case ImageBackground:
+		return s.Background
+	case ImageIcon:
+		return s.Icon
+	}
+	return ""
+}
+
+// SetImage will set the path to the image of the given type
+func (s *Shortcut) SetImage(imageType, path string) {
+	switch imageType {
+	case ImagePoster:
+		s.Poster = path
+	case ImageBanner:
+		s.Banner = path
+	case ImageLogo:
+		s.Logo = path
+	case ImageBackground:
+		s.Background = path
+	case ImageIcon:
+		s.Icon = path
+	}
+}
+
+// HasImage will return whether or not the image of the given type is set and
+// exists on disk.
+func (s *Shortcut) HasImage(imageType string) bool {
+	file := s.GetImage(imageType)
+	if file == "" {
+		return false
+	}
+	_, err := os.Stat(file)
+	return err == nil
+}
+
+// HasTag will return whether or not the shortcut has the given tag
+func (s *Shortcut) HasTag(tag string) bool {
+	for _, t := range s.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,71 @@
+package chimera
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAddShortcut(t *testing.T) {
+	tests := []struct {
+		policy  ConflictPolicy
+		want    []string
+		added   bool
+		wantErr error
+	}{
+		{ConflictError, []string{"/usr/bin/old"}, false, ErrShortcutExists},
+		{ConflictSkip, []string{"/usr/bin/old"}, false, nil},
+		{ConflictReplace, []string{"/usr/bin/new"}, true, nil},
+		{ConflictAppend, []string{"/usr/bin/old", "/usr/bin/new"}, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			shortcuts := []*Shortcut{NewShortcut("Game", "/usr/bin/old")}
+			shortcuts, added, err := AddShortcut(shortcuts, NewShortcut("Game", "/usr/bin/new"), tt.policy)
+			if !errors.Is(err, tt.wantErr) || added != tt.added {
+				t.Fatalf("unexpected result: %v, %v", added, err)
+			}
+			cmds := []string{}
+			for _, sc := range shortcuts {
+				cmds = append(cmds, sc.Cmd)
+			}
+			if strings.Join(cmds, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("unexpected shortcuts: %v", cmds)
+			}
+		})
+	}
+}
+
+func TestAddShortcutReplaceKeepsNode(t *testing.T) {
+	file := copyFixture(t, "chimera.manual.yaml")
+	shortcuts, err := LoadShortcuts(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replacement := NewShortcut("Windows Game", "/home/gamer/Games/game/new.exe", DefaultShortcut)
+	shortcuts, _, err = AddShortcut(shortcuts, replacement, ConflictReplace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SaveShortcuts(file, shortcuts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The new fields are written along with the comments and unknown keys
+	// of the replaced shortcut
+	saved := readFile(t, file)
+	for _, s := range []string{
+		"# Windows games are run through Proton",
+		"cmd: /home/gamer/Games/game/new.exe",
+		"compat_tool: \"proton_8\"",
+		"ChimeraOS Playable",
+	} {
+		if !strings.Contains(saved, s) {
+			t.Errorf("saved file is missing %q:\n%v", s, saved)
+		}
+	}
+	if strings.Contains(saved, "game.exe") {
+		t.Errorf("replaced command is still in the file:\n%v", saved)
+	}
+}
@@ -0,0 +1,117 @@
+package chimera
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/fileutil"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+	"gopkg.in/yaml.v3"
+)
+
+// SyncState records the fields of each shortcut as they were when it was
+// last synced with Steam. Sync compares both sides against this base to tell
+// which side changed since then.
+type SyncState struct {
+	path string
+	// Shortcuts holds the base of each synced shortcut by user and name
+	Shortcuts map[string]*SyncBase `yaml:"shortcuts"`
+}
+
+// SyncBase holds hashes of the synced fields of a shortcut on each side
+type SyncBase struct {
+	Chimera string `yaml:"chimera"`
+	Steam   string `yaml:"steam"`
+}
+
+// GetSyncStateFile will return the default sync state file in
+// $XDG_STATE_HOME (~/.local/state).
+func GetSyncStateFile() string {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		homeDir, _ := os.UserHomeDir()
+		stateHome = path.Join(homeDir, ".local", "state")
+	}
+	return path.Join(stateHome, "steam-shortcut-manager", "chimera-sync.yaml")
+}
+
+// LoadSyncState will load the sync state from the given file. If the file
+// does not exist yet, an empty state is returned.
+func LoadSyncState(file string) (*SyncState, error) {
+	state := &SyncState{path: file, Shortcuts: map[string]*SyncBase{}}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(data, state)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %v: %v", file, err)
+	}
+	if state.Shortcuts == nil {
+		state.Shortcuts = map[string]*SyncBase{}
+	}
+	return state, nil
+}
+
+// Save will write the sync state back to its file
+func (s *SyncState) Save() error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(path.Dir(s.path), 0755)
+	if err != nil {
+		return err
+	}
+	return fileutil.ReplaceFile(s.path, data, 0644)
+}
+
+// Changed will return which sides of the given shortcut changed since it was
+// last synced for the given user. Both sides are considered changed if the
+// shortcut was never synced.
+func (s *SyncState) Changed(user string, sc *Shortcut, steamShortcut *shortcut.Shortcut) (chimeraChanged, steamChanged bool) {
+	base, ok := s.Shortcuts[syncKey(user, sc.Name)]
+	if !ok {
+		return true, true
+	}
+	return base.Chimera != hashChimera(sc), base.Steam != hashSteam(steamShortcut)
+}
+
+// Record will store the given shortcut as the base for the next sync
+func (s *SyncState) Record(user string, sc *Shortcut, steamShortcut *shortcut.Shortcut) {
+	s.Shortcuts[syncKey(user, sc.Name)] = &SyncBase{
+		Chimera: hashChimera(sc),
+		Steam:   hashSteam(steamShortcut),
+	}
+}
+
+// syncKey will return the key of the given shortcut in the sync state
+func syncKey(user, name string) string {
+	return user + "/" + name
+}
+
+// hashChimera will return a hash of the synced fields of a Chimera shortcut
+func hashChimera(sc *Shortcut) string {
+	return hashFields(sc.Cmd, sc.Dir, sc.Hidden, strings.Join(sc.Tags, "\n"))
+}
+
+// hashSteam will return a hash of the synced fields of a Steam shortcut
+func hashSteam(sc *shortcut.Shortcut) string {
+	return hashFields(sc.Exe, sc.LaunchOptions, sc.StartDir, sc.IsHidden, strings.Join(sc.TagList(), "\n"))
+}
+
+// hashFields will return a hex encoded hash of the given values
+func hashFields(values ...interface{}) string {
+	data, _ := json.Marshal(values)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,70 @@
+package chimera
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSyncState(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	file := filepath.Join(t.TempDir(), "state", "chimera-sync.yaml")
+	state, err := LoadSyncState(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Shortcuts that were never synced have changed on both sides
+	sc := &Shortcut{Name: "Game", Cmd: "/usr/bin/game", Dir: "/usr/bin"}
+	steamShortcut := ToSteamShortcut(sc)
+	if chimeraChanged, steamChanged := state.Changed("123", sc, steamShortcut); !chimeraChanged || !steamChanged {
+		t.Errorf("unexpected result: %v, %v", chimeraChanged, steamChanged)
+	}
+
+	state.Record("123", sc, steamShortcut)
+	if chimeraChanged, steamChanged := state.Changed("123", sc, steamShortcut); chimeraChanged || steamChanged {
+		t.Errorf("unexpected result: %v, %v", chimeraChanged, steamChanged)
+	}
+	if chimeraChanged, steamChanged := state.Changed("456", sc, steamShortcut); !chimeraChanged || !steamChanged {
+		t.Error("expected the base to be per user")
+	}
+
+	// The base is kept when saved and loaded again
+	err = state.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err = LoadSyncState(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each side is compared with its own base
+	sc.Cmd = "/usr/bin/game -x"
+	if chimeraChanged, steamChanged := state.Changed("123", sc, steamShortcut); !chimeraChanged || steamChanged {
+		t.Errorf("expected only Chimera to change: %v, %v", chimeraChanged, steamChanged)
+	}
+	sc.Cmd = "/usr/bin/game"
+	steamShortcut.SetTags([]string{"RPG"})
+	if chimeraChanged, steamChanged := state.Changed("123", sc, steamShortcut); chimeraChanged || !steamChanged {
+		t.Errorf("expected only Steam to change: %v, %v", chimeraChanged, steamChanged)
+	}
+
+	// Tags that are missing or empty are the same
+	sc.Tags = []string{}
+	steamShortcut.SetTags(nil)
+	if chimeraChanged, steamChanged := state.Changed("123", sc, steamShortcut); chimeraChanged || steamChanged {
+		t.Errorf("unexpected result: %v, %v", chimeraChanged, steamChanged)
+	}
+}
+
+func TestLoadSyncStateInvalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "chimera-sync.yaml")
+	err := os.WriteFile(file, []byte("shortcuts: [\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSyncState(file); err == nil {
+		t.Error("expected an error for an invalid state file")
+	}
+}
@@ -0,0 +1,150 @@
+package desktop
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Entry is a parsed XDG desktop entry (.desktop file)
+// https://specifications.freedesktop.org/desktop-entry-spec/latest/
+type Entry struct {
+	// Path to the .desktop file
+	File       string   `json:"file"`
+	ID         string   `json:"id"`
+	Type       string   `json:"type"`
+	Name       string   `json:"name"`
+	Exec       string   `json:"exec"`
+	Path       string   `json:"path"`
+	Icon       string   `json:"icon"`
+	Categories []string `json:"categories"`
+	NoDisplay  bool     `json:"noDisplay"`
+	Hidden     bool     `json:"hidden"`
+	// FlatpakID is set by Flatpak for exported desktop entries
+	FlatpakID string `json:"flatpakId,omitempty"`
+}
+
+// IsVisible will return whether or not the entry would be shown in an
+// application menu.
+func (e *Entry) IsVisible() bool {
+	return e.Type == "Application" && !e.NoDisplay && !e.Hidden
+}
+
+// HasCategory will return whether or not the entry is in the given category.
+// Categories are compared case-insensitively.
+func (e *Entry) HasCategory(category string) bool {
+	for _, c := range e.Categories {
+		if strings.EqualFold(c, category) {
+			return true
+		}
+	}
+	return false
+}
+
+// Load will parse the given .desktop file
+func Load(file string) (*Entry, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entry := &Entry{
+		File:       file,
+		ID:         strings.TrimSuffix(filepath.Base(file), ".desktop"),
+		Categories: []string{},
+	}
+	inEntryGroup := false
+	foundGroup := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Only the "Desktop Entry" group is used. Other groups such as
+		// "Desktop Action" define additional actions.
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inEntryGroup = line == "[Desktop Entry]"
+			foundGroup = foundGroup || inEntryGroup
+			continue
+		}
+		if !inEntryGroup {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Type":
+			entry.Type = value
+		case "Name":
+			entry.Name = unescapeString(value)
+		case "Exec":
+			entry.Exec = unescapeString(value)
+		case "Path":
+			entry.Path = unescapeString(value)
+		case "Icon":
+			entry.Icon = unescapeString(value)
+		case "Categories":
+			for _, c := range strings.Split(value, ";") {
+				if c == "" {
+					continue
+				}
+				entry.Categories = append(entry.Categories, c)
+			}
+		case "NoDisplay":
+			entry.NoDisplay = value == "true"
+		case "Hidden":
+			entry.Hidden = value == "true"
+		case "X-Flatpak":
+			entry.FlatpakID = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !foundGroup {
+		return nil, fmt.Errorf("no [Desktop Entry] group found in %v", file)
+	}
+
+	return entry, nil
+}
+
+// unescapeString will unescape a desktop entry string value
+func unescapeString(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 's':
+			sb.WriteByte(' ')
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case '\\':
+			sb.WriteByte('\\')
+		default:
+			sb.WriteByte('\\')
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
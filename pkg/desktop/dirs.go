@@ -0,0 +1,77 @@
+package desktop
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DataDirs will return the XDG data directories to search, in order of
+// preference. This includes the Flatpak exports directories.
+func DataDirs() []string {
+	homeDir, _ := os.UserHomeDir()
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = path.Join(homeDir, ".local", "share")
+	}
+
+	dirs := []string{
+		dataHome,
+		path.Join(dataHome, "flatpak", "exports", "share"),
+		"/var/lib/flatpak/exports/share",
+	}
+	dataDirs := os.Getenv("XDG_DATA_DIRS")
+	if dataDirs == "" {
+		dataDirs = "/usr/local/share:/usr/share"
+	}
+	for _, dir := range strings.Split(dataDirs, ":") {
+		if dir == "" || containsString(dirs, dir) {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+
+	return dirs
+}
+
+// ApplicationDirs will return the directories that contain .desktop files
+func ApplicationDirs() []string {
+	dirs := []string{}
+	for _, dataDir := range DataDirs() {
+		dirs = append(dirs, path.Join(dataDir, "applications"))
+	}
+	return dirs
+}
+
+// Discover will return the paths to all .desktop files in the given
+// directories. If the same desktop file ID exists in multiple directories,
+// only the first one is returned.
+func Discover(dirs []string) ([]string, error) {
+	files := []string{}
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		matches, err := filepath.Glob(path.Join(dir, "*.desktop"))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			id := filepath.Base(match)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			files = append(files, match)
+		}
+	}
+	return files, nil
+}
+
+func containsString(s []string, str string) bool {
+	for _, v := range s {
+		if v == str {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package desktop
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseExec will split the entry's Exec value into arguments and expand or
+// remove any field codes (e.g. %U). The string escapes of the desktop file
+// (e.g. "\s") were already removed by Load, so only the quoting rules of the
+// Exec key are applied here.
+func (e *Entry) ParseExec() ([]string, error) {
+	args, err := splitExec(e.Exec)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", e.File, err)
+	}
+
+	result := []string{}
+	for _, arg := range args {
+		// Field codes that expand to a list of files or URLs are removed
+		// entirely when they are the whole argument. Deprecated field codes
+		// are removed as well.
+		switch arg {
+		case "%f", "%F", "%u", "%U", "%d", "%D", "%n", "%N", "%v", "%m":
+			continue
+		case "%i":
+			if e.Icon != "" {
+				result = append(result, "--icon", e.Icon)
+			}
+			continue
+		}
+		result = append(result, expandFieldCodes(arg, e))
+	}
+	result = removeFileForwarding(result)
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%v: empty Exec", e.File)
+	}
+
+	return result, nil
+}
+
+// removeFileForwarding will remove the "@@u" and "@@" markers that Flatpak
+// puts around file arguments when the field codes between them were removed.
+func removeFileForwarding(args []string) []string {
+	result := []string{}
+	for i := 0; i < len(args); i++ {
+		if (args[i] == "@@" || args[i] == "@@u") && i+1 < len(args) && args[i+1] == "@@" {
+			i++
+			continue
+		}
+		result = append(result, args[i])
+	}
+	return result
+}
+
+// expandFieldCodes will expand or remove field codes inside of an argument
+func expandFieldCodes(arg string, e *Entry) string {
+	if !strings.Contains(arg, "%") {
+		return arg
+	}
+	var sb strings.Builder
+	for i := 0; i < len(arg); i++ {
+		if arg[i] != '%' || i+1 >= len(arg) {
+			sb.WriteByte(arg[i])
+			continue
+		}
+		i++
+		switch arg[i] {
+		case '%':
+			sb.WriteByte('%')
+		case 'c':
+			sb.WriteString(e.Name)
+		case 'k':
+			sb.WriteString(e.File)
+		}
+	}
+	return sb.String()
+}
+
+// splitExec will split the given Exec value into arguments using the quoting
+// rules from the desktop entry spec. Inside of double quotes, a backslash
+// escapes a double quote, backtick, dollar sign or backslash. Any other
+// backslash is kept as it is.
+func splitExec(exec string) ([]string, error) {
+	args := []string{}
+	var sb strings.Builder
+	inArg := false
+	inQuotes := false
+	for i := 0; i < len(exec); i++ {
+		c := exec[i]
+		switch {
+		case inQuotes && c == '\\' && i+1 < len(exec) && strings.IndexByte("\"`$\\", exec[i+1]) >= 0:
+			i++
+			sb.WriteByte(exec[i])
+		case c == '"':
+			inQuotes = !inQuotes
+			inArg = true
+		case !inQuotes && (c == ' ' || c == '\t'):
+			if inArg {
+				args = append(args, sb.String())
+				sb.Reset()
+				inArg = false
+			}
+		default:
+			sb.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in Exec: %v", exec)
+	}
+	if inArg {
+		args = append(args, sb.String())
+	}
+
+	return args, nil
+}
+
+// JoinArgs will join the given arguments into a single string, quoting any
+// arguments that contain whitespace or quotes.
+func JoinArgs(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'") {
+			arg = `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+		}
+		quoted = append(quoted, arg)
+	}
+	return strings.Join(quoted, " ")
+}
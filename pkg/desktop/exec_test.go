@@ -0,0 +1,103 @@
+package desktop
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseExec(t *testing.T) {
+	entry := &Entry{Name: "Game", Icon: "game", File: "/usr/share/applications/game.desktop"}
+	tests := []struct {
+		name string
+		exec string
+		want []string
+	}{
+		{"plain", "/usr/bin/game --fullscreen", []string{"/usr/bin/game", "--fullscreen"}},
+		{"file and url codes", "game %f %F %u %U", []string{"game"}},
+		{"deprecated codes", "game %d %D %n %N %v %m", []string{"game"}},
+		{"icon", "game %i", []string{"game", "--icon", "game"}},
+		{"name and file", "game --title=%c %k", []string{"game", "--title=Game", "/usr/share/applications/game.desktop"}},
+		{"literal percent", "game %% --volume=50%%", []string{"game", "%", "--volume=50%"}},
+		{"codes inside an argument", "game --file=%f", []string{"game", "--file="}},
+		{"quoted arguments", `"/opt/My Game/game" "a b"  c`, []string{"/opt/My Game/game", "a b", "c"}},
+		{"escapes in quotes", `game "\"q\" \` + "`" + `b\` + "`" + ` \$HOME \\ \n"`, []string{"game", "\"q\" `b` $HOME \\ \\n"}},
+		{"backslash outside quotes", `game C:\Games`, []string{"game", `C:\Games`}},
+		{"empty quoted argument", `game ""`, []string{"game", ""}},
+		{
+			"flatpak file forwarding",
+			"/usr/bin/flatpak run --branch=stable --arch=x86_64 --command=game --file-forwarding org.example.Game @@u %U @@",
+			[]string{"/usr/bin/flatpak", "run", "--branch=stable", "--arch=x86_64", "--command=game", "--file-forwarding", "org.example.Game"},
+		},
+		{
+			"flatpak file forwarding of files",
+			"/usr/bin/flatpak run --file-forwarding org.example.Game @@ %F @@ --new-window",
+			[]string{"/usr/bin/flatpak", "run", "--file-forwarding", "org.example.Game", "--new-window"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := *entry
+			e.Exec = tt.exec
+			got, err := e.ParseExec()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExecIconMissing(t *testing.T) {
+	e := &Entry{Exec: "game %i"}
+	got, err := e.ParseExec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []string{"game"}) {
+		t.Errorf("expected %%i to be removed without an icon, got %q", got)
+	}
+}
+
+func TestParseExecErrors(t *testing.T) {
+	for name, exec := range map[string]string{
+		"empty":            "",
+		"only field codes": "%U %i",
+		"unterminated":     `"/usr/bin/game --fullscreen`,
+	} {
+		e := &Entry{Exec: exec}
+		if _, err := e.ParseExec(); err == nil {
+			t.Errorf("%v: expected an error for %q", name, exec)
+		}
+	}
+}
+
+func TestLoadExecEscapes(t *testing.T) {
+	// The string escapes of the file are removed before the Exec quoting
+	// rules, so "\\\\" in the file is a single backslash in the argument
+	entry, err := Load(filepath.Join("testdata", "escapes.desktop"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Name != "Escaped Game" || !reflect.DeepEqual(entry.Categories, []string{"Game"}) {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	got, err := entry.ParseExec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/opt/My Games/game", "--config", `C:\Games\config.ini`, "--title", `Say "hi"`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestJoinArgs(t *testing.T) {
+	args := []string{"--title", `Say "hi"`, "", "a b", "plain"}
+	want := `--title "Say \"hi\"" "" "a b" plain`
+	if got := JoinArgs(args); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
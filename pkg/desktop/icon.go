@@ -0,0 +1,61 @@
+package desktop
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// iconSizes is the order of icon theme sizes to search for. Larger icons are
+// preferred since Steam scales them down.
+var iconSizes = []string{
+	"512x512", "256x256", "192x192", "128x128", "96x96", "64x64", "48x48", "32x32",
+}
+
+// iconExtensions is the order of icon file extensions to search for
+var iconExtensions = []string{"png", "xpm", "svg"}
+
+// ErrIconNotFound indicates that a themed icon could not be resolved
+var ErrIconNotFound = errors.New("icon not found")
+
+// ResolveIcon will resolve the given icon name or path to an icon file. Icon
+// names are looked up in the hicolor icon theme and pixmaps directories.
+func ResolveIcon(icon string) (string, error) {
+	if icon == "" {
+		return "", ErrIconNotFound
+	}
+	if filepath.IsAbs(icon) {
+		if _, err := os.Stat(icon); err != nil {
+			return "", ErrIconNotFound
+		}
+		return icon, nil
+	}
+
+	// Look in the icon theme directories
+	for _, dataDir := range DataDirs() {
+		themeDir := path.Join(dataDir, "icons", "hicolor")
+		for _, size := range iconSizes {
+			for _, ext := range iconExtensions {
+				file := path.Join(themeDir, size, "apps", icon+"."+ext)
+				if _, err := os.Stat(file); err == nil {
+					return file, nil
+				}
+			}
+		}
+		file := path.Join(themeDir, "scalable", "apps", icon+".svg")
+		if _, err := os.Stat(file); err == nil {
+			return file, nil
+		}
+	}
+
+	// Fall back to the legacy pixmaps directory
+	for _, ext := range iconExtensions {
+		file := path.Join("/usr/share/pixmaps", icon+"."+ext)
+		if _, err := os.Stat(file); err == nil {
+			return file, nil
+		}
+	}
+
+	return "", ErrIconNotFound
+}
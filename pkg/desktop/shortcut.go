@@ -0,0 +1,45 @@
+package desktop
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+)
+
+// Shortcut will return a new Steam shortcut that launches the desktop entry
+func (e *Entry) Shortcut() (*shortcut.Shortcut, error) {
+	args, err := e.ParseExec()
+	if err != nil {
+		return nil, err
+	}
+
+	// Steam requires an absolute path to the executable
+	exe := args[0]
+	if !filepath.IsAbs(exe) {
+		found, err := exec.LookPath(exe)
+		if err != nil {
+			return nil, fmt.Errorf("%v: executable not found: %v", e.File, exe)
+		}
+		exe = found
+	}
+	startDir := e.Path
+	if startDir == "" {
+		startDir = filepath.Dir(exe) + "/"
+	}
+	icon, _ := ResolveIcon(e.Icon)
+
+	shortcutConfiger := func(s *shortcut.Shortcut) {
+		s.LaunchOptions = JoinArgs(args[1:])
+		s.StartDir = shortcut.Quote(startDir)
+		s.ShortcutPath = e.File
+		s.FlatpakAppID = e.FlatpakID
+		s.Icon = icon
+		s.Tags = map[string]interface{}{}
+		s.Appid = int64(shortcut.CalculateAppID(s.Exe, s.AppName))
+	}
+	sc := shortcut.NewShortcut(e.Name, shortcut.Quote(exe), shortcut.DefaultShortcut, shortcutConfiger)
+
+	return sc, nil
+}
@@ -0,0 +1,39 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFile will atomically replace the given file with the given data. The
+// data is written to a temporary file in the same directory first and renamed
+// so readers never see a partially written file. The file always gets the
+// given permissions, even if it already exists with wider ones.
+func WriteFile(file string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(perm)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// ReplaceFile is like WriteFile, but an existing file keeps its permissions.
+// New files are created with the given permissions.
+func ReplaceFile(file string, data []byte, perm os.FileMode) error {
+	if info, err := os.Stat(file); err == nil {
+		perm = info.Mode().Perm()
+	}
+	return WriteFile(file, data, perm)
+}
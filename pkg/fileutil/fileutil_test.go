@@ -0,0 +1,81 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// checkFile will check the contents and permissions of the given file, and
+// that no temporary files were left next to it.
+func checkFile(t *testing.T, file, data string, perm os.FileMode) {
+	t.Helper()
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != data {
+		t.Errorf("unexpected data: %q", content)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != perm {
+		t.Errorf("unexpected permissions: %v", info.Mode().Perm())
+	}
+	files, err := os.ReadDir(filepath.Dir(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("unexpected files: %v", files)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "secrets.yaml")
+	err := WriteFile(file, []byte("a"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFile(t, file, "a", 0600)
+
+	// Existing files get the given permissions
+	err = os.Chmod(file, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = WriteFile(file, []byte("b"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFile(t, file, "b", 0600)
+}
+
+func TestReplaceFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "shortcuts.yaml")
+	err := ReplaceFile(file, []byte("a"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFile(t, file, "a", 0644)
+
+	// Existing files keep their permissions
+	err = os.Chmod(file, 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ReplaceFile(file, []byte("b"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFile(t, file, "b", 0640)
+}
+
+func TestWriteFileMissingDir(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "missing", "file")
+	if err := WriteFile(file, []byte("a"), 0644); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
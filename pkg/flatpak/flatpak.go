@@ -0,0 +1,179 @@
+package flatpak
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultBinary is the path to the flatpak binary if it cannot be found in
+// PATH.
+const DefaultBinary = "/usr/bin/flatpak"
+
+// Installation is a Flatpak installation directory
+type Installation struct {
+	// Name is either "user" or "system"
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// Installations will return the user and system Flatpak installations, in the
+// order that "flatpak run" searches them.
+func Installations() []*Installation {
+	homeDir, _ := os.UserHomeDir()
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = path.Join(homeDir, ".local", "share")
+	}
+	systemDir := os.Getenv("FLATPAK_SYSTEM_DIR")
+	if systemDir == "" {
+		systemDir = "/var/lib/flatpak"
+	}
+	return []*Installation{
+		{Name: "user", Path: path.Join(dataHome, "flatpak")},
+		{Name: "system", Path: systemDir},
+	}
+}
+
+// App is an installed Flatpak application
+type App struct {
+	ID           string        `json:"id"`
+	Arch         string        `json:"arch"`
+	Branch       string        `json:"branch"`
+	Command      string        `json:"command"`
+	Installation *Installation `json:"installation"`
+	// DesktopFile is the path to the exported desktop file, if any
+	DesktopFile string `json:"desktopFile"`
+}
+
+// GetApp will return the installed Flatpak application with the given ID
+func GetApp(id string) (*App, error) {
+	for _, installation := range Installations() {
+		app, err := installation.GetApp(id)
+		if err != nil {
+			continue
+		}
+		return app, nil
+	}
+	return nil, fmt.Errorf("flatpak not installed: %v", id)
+}
+
+// GetApp will return the Flatpak application with the given ID from this
+// installation.
+func (i *Installation) GetApp(id string) (*App, error) {
+	appDir := path.Join(i.Path, "app", id)
+
+	// The "current" symlink points to "<arch>/<branch>"
+	target, err := os.Readlink(path.Join(appDir, "current"))
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(filepath.Clean(target), string(filepath.Separator))
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("unexpected flatpak deployment for %v: %v", id, target)
+	}
+	app := &App{
+		ID:           id,
+		Arch:         parts[len(parts)-2],
+		Branch:       parts[len(parts)-1],
+		Installation: i,
+	}
+
+	// Read the command to run from the metadata
+	metadata, err := readMetadata(path.Join(appDir, "current", "active", "metadata"))
+	if err != nil {
+		return nil, err
+	}
+	app.Command = metadata["Application"]["command"]
+
+	desktopFile := path.Join(i.Path, "exports", "share", "applications", id+".desktop")
+	if _, err := os.Stat(desktopFile); err == nil {
+		app.DesktopFile = desktopFile
+	}
+
+	return app, nil
+}
+
+// LaunchOptions will return the arguments to pass to the flatpak binary to
+// run the application.
+func (a *App) LaunchOptions() string {
+	args := []string{
+		"run",
+		"--branch=" + a.Branch,
+		"--arch=" + a.Arch,
+	}
+	if a.Command != "" {
+		args = append(args, "--command="+a.Command)
+	}
+	args = append(args, a.ID)
+	return strings.Join(args, " ")
+}
+
+// Binary will return the path to the flatpak binary
+func Binary() string {
+	if found, err := exec.LookPath("flatpak"); err == nil {
+		return found
+	}
+	return DefaultBinary
+}
+
+// readMetadata will read the given Flatpak metadata keyfile into a map of
+// groups to keys and values.
+func readMetadata(file string) (map[string]map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	groups := map[string]map[string]string{}
+	group := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			group = line[1 : len(line)-1]
+			groups[group] = map[string]string{}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || group == "" {
+			continue
+		}
+		groups[group][strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return groups, scanner.Err()
+}
+
+// FindCommand will return the executable and leading arguments needed to run
+// a program that may be installed as the given Flatpak app or natively as one
+// of the given binaries. The Flatpak is preferred if it is installed. If the
+// program cannot be found, the first binary is returned.
+func FindCommand(appID, flatpakCommand string, binaries ...string) (string, string) {
+	if appID != "" {
+		if app, err := GetApp(appID); err == nil {
+			args := "run"
+			if flatpakCommand != "" {
+				args += " --command=" + flatpakCommand
+			}
+			return Binary(), args + " " + app.ID
+		}
+	}
+	for _, binary := range binaries {
+		if found, err := exec.LookPath(binary); err == nil {
+			return found, ""
+		}
+	}
+	if len(binaries) == 0 {
+		return "", ""
+	}
+	return binaries[0], ""
+}
@@ -0,0 +1,51 @@
+package flatpak
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/desktop"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+)
+
+// Shortcut will return a new Steam shortcut that runs the Flatpak
+// application. If name is empty, the name from the exported desktop file is
+// used.
+func (a *App) Shortcut(name string) (*shortcut.Shortcut, error) {
+	binary := Binary()
+	launchOptions := a.LaunchOptions()
+	icon := ""
+
+	// Prefer the launch options from the exported desktop file since they
+	// include options such as file forwarding.
+	if a.DesktopFile != "" {
+		entry, err := desktop.Load(a.DesktopFile)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			name = entry.Name
+		}
+		args, err := entry.ParseExec()
+		if err == nil && len(args) > 1 && strings.HasSuffix(args[0], "flatpak") {
+			launchOptions = desktop.JoinArgs(args[1:])
+		}
+		icon, _ = desktop.ResolveIcon(entry.Icon)
+	}
+	if name == "" {
+		name = a.ID
+	}
+
+	shortcutConfiger := func(s *shortcut.Shortcut) {
+		s.LaunchOptions = launchOptions
+		s.StartDir = shortcut.Quote(filepath.Dir(binary) + "/")
+		s.FlatpakAppID = a.ID
+		s.ShortcutPath = a.DesktopFile
+		s.Icon = icon
+		s.Tags = map[string]interface{}{}
+		s.Appid = int64(shortcut.CalculateAppID(s.Exe, s.AppName))
+	}
+	sc := shortcut.NewShortcut(name, shortcut.Quote(binary), shortcut.DefaultShortcut, shortcutConfiger)
+
+	return sc, nil
+}
@@ -0,0 +1,95 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	Register(&Bottles{})
+}
+
+// Bottles imports programs added to bottles managed by Bottles
+// https://usebottles.com
+type Bottles struct{}
+
+// Name of the importer
+func (b *Bottles) Name() string {
+	return "bottles"
+}
+
+// Description of the importer
+func (b *Bottles) Description() string {
+	return "Programs added to Bottles"
+}
+
+// Available returns whether or not Bottles has a bottles directory
+func (b *Bottles) Available() bool {
+	_, ok := b.bottlesDir()
+	return ok
+}
+
+// bottleConfig is the subset of bottle.yml that is needed to launch programs
+type bottleConfig struct {
+	Name             string `yaml:"Name"`
+	ExternalPrograms map[string]struct {
+		Name       string `yaml:"name"`
+		Executable string `yaml:"executable"`
+		Removed    bool   `yaml:"removed"`
+	} `yaml:"External_Programs"`
+}
+
+// Import returns a shortcut for each program in each bottle. Bottles whose
+// config cannot be read are returned as errors.
+func (b *Bottles) Import() ([]*shortcut.Shortcut, error) {
+	bottlesDir, ok := b.bottlesDir()
+	if !ok {
+		return nil, fmt.Errorf("no Bottles directory found")
+	}
+	l := findLauncher("com.usebottles.bottles", "bottles-cli", "bottles-cli", "/usr/bin/bottles-cli")
+
+	configs, err := filepath.Glob(path.Join(bottlesDir, "*", "bottle.yml"))
+	if err != nil {
+		return nil, err
+	}
+	var errors error
+	shortcuts := []*shortcut.Shortcut{}
+	for _, file := range configs {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+		var config bottleConfig
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("unable to parse %v: %w", file, err))
+			continue
+		}
+		if config.Name == "" {
+			config.Name = filepath.Base(filepath.Dir(file))
+		}
+		for _, program := range config.ExternalPrograms {
+			if program.Removed || program.Name == "" {
+				continue
+			}
+			args := fmt.Sprintf("run -b %q -p %q", config.Name, program.Name)
+			shortcuts = append(shortcuts, l.newShortcut(program.Name, args, "Bottles", config.Name))
+		}
+	}
+	sortShortcuts(shortcuts)
+
+	return shortcuts, errors
+}
+
+func (b *Bottles) bottlesDir() (string, bool) {
+	return firstExisting(
+		path.Join(dataHome(), "bottles", "bottles"),
+		flatpakDir("com.usebottles.bottles", "data", "bottles", "bottles"),
+	)
+}
@@ -0,0 +1,199 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+)
+
+func init() {
+	Register(&Heroic{})
+}
+
+// Heroic imports Epic, GOG and Amazon games installed by the Heroic Games
+// Launcher.
+// https://github.com/Heroic-Games-Launcher/HeroicGamesLauncher
+type Heroic struct{}
+
+// Name of the importer
+func (h *Heroic) Name() string {
+	return "heroic"
+}
+
+// Description of the importer
+func (h *Heroic) Description() string {
+	return "Epic, GOG and Amazon games installed with the Heroic Games Launcher"
+}
+
+// Available returns whether or not Heroic has a config directory
+func (h *Heroic) Available() bool {
+	_, ok := h.configDir()
+	return ok
+}
+
+// Import returns a shortcut for each installed Heroic game. Stores that are
+// not set up are skipped, but stores that cannot be read are returned as
+// errors.
+func (h *Heroic) Import() ([]*shortcut.Shortcut, error) {
+	configDir, ok := h.configDir()
+	if !ok {
+		return nil, fmt.Errorf("no Heroic config directory found")
+	}
+	l := findLauncher("com.heroicgameslauncher.hgl", "", "heroic", "/opt/Heroic/heroic")
+
+	var errors error
+	shortcuts := []*shortcut.Shortcut{}
+	for _, store := range []struct {
+		runner string
+		tag    string
+		games  func(string) (map[string]string, error)
+	}{
+		{"legendary", "Epic Games", heroicLegendaryGames},
+		{"gog", "GOG", heroicGOGGames},
+		{"nile", "Amazon", heroicNileGames},
+	} {
+		games, err := store.games(configDir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("unable to read %v games: %w", store.tag, err))
+			continue
+		}
+		for appName, title := range games {
+			args := fmt.Sprintf(`--no-gui --no-sandbox "heroic://launch/%s/%s"`, store.runner, appName)
+			shortcuts = append(shortcuts, l.newShortcut(title, args, "Heroic", store.tag))
+		}
+	}
+	sortShortcuts(shortcuts)
+
+	return shortcuts, errors
+}
+
+func (h *Heroic) configDir() (string, bool) {
+	return firstExisting(
+		path.Join(configHome(), "heroic"),
+		flatpakDir("com.heroicgameslauncher.hgl", "config", "heroic"),
+	)
+}
+
+// heroicLegendaryGames returns installed Epic games as a map of app names to
+// titles.
+func heroicLegendaryGames(configDir string) (map[string]string, error) {
+	data, err := os.ReadFile(path.Join(configDir, "legendaryConfig", "legendary", "installed.json"))
+	if err != nil {
+		return nil, err
+	}
+	var installed map[string]struct {
+		AppName string `json:"app_name"`
+		Title   string `json:"title"`
+		IsDLC   bool   `json:"is_dlc"`
+	}
+	if err := json.Unmarshal(data, &installed); err != nil {
+		return nil, err
+	}
+
+	games := map[string]string{}
+	for appName, game := range installed {
+		if game.IsDLC {
+			continue
+		}
+		if game.AppName != "" {
+			appName = game.AppName
+		}
+		games[appName] = game.Title
+	}
+	return games, nil
+}
+
+// heroicGOGGames returns installed GOG games as a map of app names to titles
+func heroicGOGGames(configDir string) (map[string]string, error) {
+	data, err := os.ReadFile(path.Join(configDir, "gog_store", "installed.json"))
+	if err != nil {
+		return nil, err
+	}
+	var installed struct {
+		Installed []struct {
+			AppName string `json:"appName"`
+			IsDLC   bool   `json:"is_dlc"`
+		} `json:"installed"`
+	}
+	if err := json.Unmarshal(data, &installed); err != nil {
+		return nil, err
+	}
+
+	// Titles are stored in the library cache
+	titles := map[string]string{}
+	if data, err := os.ReadFile(path.Join(configDir, "store_cache", "gog_library.json")); err == nil {
+		var library struct {
+			Games []struct {
+				AppName string `json:"app_name"`
+				Title   string `json:"title"`
+			} `json:"games"`
+		}
+		if json.Unmarshal(data, &library) == nil {
+			for _, game := range library.Games {
+				titles[game.AppName] = game.Title
+			}
+		}
+	}
+
+	games := map[string]string{}
+	for _, game := range installed.Installed {
+		if game.IsDLC {
+			continue
+		}
+		title := titles[game.AppName]
+		if title == "" {
+			title = game.AppName
+		}
+		games[game.AppName] = title
+	}
+	return games, nil
+}
+
+// heroicNileGames returns installed Amazon games as a map of app names to
+// titles.
+func heroicNileGames(configDir string) (map[string]string, error) {
+	nileDir := path.Join(configDir, "nile_config", "nile")
+	data, err := os.ReadFile(path.Join(nileDir, "installed.json"))
+	if err != nil {
+		return nil, err
+	}
+	var installed []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &installed); err != nil {
+		return nil, err
+	}
+
+	// Titles are stored in the library
+	titles := map[string]string{}
+	if data, err := os.ReadFile(path.Join(nileDir, "library.json")); err == nil {
+		var library []struct {
+			ID      string `json:"id"`
+			Product struct {
+				Title string `json:"title"`
+			} `json:"product"`
+		}
+		if json.Unmarshal(data, &library) == nil {
+			for _, game := range library {
+				titles[game.ID] = game.Product.Title
+			}
+		}
+	}
+
+	games := map[string]string{}
+	for _, game := range installed {
+		title := titles[game.ID]
+		if title == "" {
+			title = game.ID
+		}
+		games[game.ID] = title
+	}
+	return games, nil
+}
@@ -0,0 +1,148 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/flatpak"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+)
+
+// Importer discovers games installed by another launcher and returns Steam
+// shortcuts that launch them.
+type Importer interface {
+	// Name is the name used to select the importer (e.g. "heroic")
+	Name() string
+	// Description is a short, human readable description of the importer
+	Description() string
+	// Available returns whether or not the launcher appears to be installed
+	Available() bool
+	// Import returns a shortcut for each game found, sorted by name. Games
+	// that could be read are returned along with an error for any that
+	// could not.
+	Import() ([]*shortcut.Shortcut, error)
+}
+
+var importers = map[string]Importer{}
+
+// Register will make the given importer available by name. Importers register
+// themselves in an init function.
+func Register(i Importer) {
+	importers[i.Name()] = i
+}
+
+// Get will return the importer with the given name
+func Get(name string) (Importer, error) {
+	i, ok := importers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown importer: %v", name)
+	}
+	return i, nil
+}
+
+// List will return all registered importers sorted by name
+func List() []Importer {
+	list := make([]Importer, 0, len(importers))
+	for _, i := range importers {
+		list = append(list, i)
+	}
+	sort.Slice(list, func(a, b int) bool { return list[a].Name() < list[b].Name() })
+	return list
+}
+
+// Names will return the names of all registered importers
+func Names() []string {
+	names := []string{}
+	for _, i := range List() {
+		names = append(names, i.Name())
+	}
+	return names
+}
+
+// launcher is how to execute a game launcher that may be installed natively
+// or as a Flatpak.
+type launcher struct {
+	// exe is the path to the executable to run
+	exe string
+	// args are arguments that must come before any launcher arguments (e.g.
+	// "run com.example.App" for Flatpaks)
+	args string
+}
+
+// findLauncher will look for the given Flatpak app ID first, then the given
+// binaries in PATH, falling back to the first binary.
+func findLauncher(flatpakID, flatpakCommand string, binaries ...string) *launcher {
+	exe, args := flatpak.FindCommand(flatpakID, flatpakCommand, binaries...)
+	return &launcher{exe: exe, args: args}
+}
+
+// newShortcut will return a shortcut that runs the launcher with the given
+// arguments. Empty tags (e.g. a game without a runner) are skipped.
+func (l *launcher) newShortcut(name, args string, tags ...string) *shortcut.Shortcut {
+	launchOptions := args
+	if l.args != "" {
+		launchOptions = l.args + " " + args
+	}
+	exe := shortcut.Quote(l.exe)
+	nonEmptyTags := []string{}
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) != "" {
+			nonEmptyTags = append(nonEmptyTags, tag)
+		}
+	}
+	shortcutConfiger := func(s *shortcut.Shortcut) {
+		s.LaunchOptions = launchOptions
+		s.StartDir = shortcut.Quote(path.Dir(l.exe) + "/")
+		s.SetTags(nonEmptyTags)
+		s.Appid = int64(shortcut.CalculateAppID(s.Exe, s.AppName))
+	}
+	return shortcut.NewShortcut(name, exe, shortcut.DefaultShortcut, shortcutConfiger)
+}
+
+// sortShortcuts will sort the given shortcuts by name
+func sortShortcuts(shortcuts []*shortcut.Shortcut) {
+	sort.SliceStable(shortcuts, func(a, b int) bool {
+		return strings.ToLower(shortcuts[a].AppName) < strings.ToLower(shortcuts[b].AppName)
+	})
+}
+
+// firstExisting will return the first of the given paths that exists
+func firstExisting(paths ...string) (string, bool) {
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// homeDir will return the user's home directory
+func homeDir() string {
+	dir, _ := os.UserHomeDir()
+	return dir
+}
+
+// configHome will return the XDG config directory
+func configHome() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	return path.Join(homeDir(), ".config")
+}
+
+// dataHome will return the XDG data directory
+func dataHome() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	return path.Join(homeDir(), ".local", "share")
+}
+
+// flatpakDir will return the per-app data directory that Flatpak uses for
+// the given app ID.
+func flatpakDir(appID string, parts ...string) string {
+	return path.Join(append([]string{homeDir(), ".var", "app", appID}, parts...)...)
+}
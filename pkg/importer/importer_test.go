@@ -0,0 +1,203 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+)
+
+// setupHome will point the home, XDG and PATH directories at empty temporary
+// directories, with the given directories used instead if they are not empty.
+func setupHome(t *testing.T, configHome, dataHome string) {
+	t.Helper()
+	if configHome == "" {
+		configHome = t.TempDir()
+	}
+	if dataHome == "" {
+		dataHome = t.TempDir()
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("XDG_DATA_HOME", dataHome)
+}
+
+// testdata will return the absolute path to the given test fixture
+func testdata(t *testing.T, parts ...string) string {
+	t.Helper()
+	dir, err := filepath.Abs(filepath.Join(append([]string{"testdata"}, parts...)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// summary is the part of an imported shortcut that the tests check
+type summary struct {
+	Name          string
+	LaunchOptions string
+	Tags          []string
+}
+
+func summarize(shortcuts []*shortcut.Shortcut) []summary {
+	result := []summary{}
+	for _, sc := range shortcuts {
+		result = append(result, summary{sc.AppName, sc.LaunchOptions, sc.TagList()})
+	}
+	return result
+}
+
+func TestHeroic(t *testing.T) {
+	setupHome(t, testdata(t), "")
+	h := &Heroic{}
+	if !h.Available() {
+		t.Fatal("expected Heroic to be available")
+	}
+	shortcuts, err := h.Import()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// DLC is skipped and titles fall back to the app name
+	launch := func(runner, appName string) string {
+		return `--no-gui --no-sandbox "heroic://launch/` + runner + "/" + appName + `"`
+	}
+	want := []summary{
+		{"1207658930", launch("gog", "1207658930"), []string{"Heroic", "GOG"}},
+		{"amzn1.adg.product.2", launch("nile", "amzn1.adg.product.2"), []string{"Heroic", "Amazon"}},
+		{"Blasphemous", launch("nile", "amzn1.adg.product.1"), []string{"Heroic", "Amazon"}},
+		{"Fortnite", launch("legendary", "Fortnite"), []string{"Heroic", "Epic Games"}},
+		{"Rocket League", launch("legendary", "Sugar"), []string{"Heroic", "Epic Games"}},
+		{"Unreal Tournament 2004", launch("gog", "1207658924"), []string{"Heroic", "GOG"}},
+	}
+	if got := summarize(shortcuts); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected shortcuts:\n%+v\nwant:\n%+v", got, want)
+	}
+	for _, sc := range shortcuts {
+		if sc.Exe != `"heroic"` || sc.Appid != int64(shortcut.CalculateAppID(sc.Exe, sc.AppName)) {
+			t.Errorf("unexpected shortcut: %+v", sc)
+		}
+	}
+}
+
+func TestHeroicStoreErrors(t *testing.T) {
+	configHome := t.TempDir()
+	setupHome(t, configHome, "")
+	legendary := filepath.Join(configHome, "heroic", "legendaryConfig", "legendary")
+	err := os.MkdirAll(legendary, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(testdata(t, "heroic", "legendaryConfig", "legendary", "installed.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(legendary, "installed.json"), data, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gog := filepath.Join(configHome, "heroic", "gog_store")
+	err = os.MkdirAll(gog, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(gog, "installed.json"), []byte("{bad"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stores that are not set up are skipped, but broken ones are reported
+	// along with the games that could be read
+	shortcuts, err := (&Heroic{}).Import()
+	if err == nil || !strings.Contains(err.Error(), "unable to read GOG games") {
+		t.Errorf("expected an error for the GOG store, got %v", err)
+	}
+	if strings.Contains(err.Error(), "Amazon") {
+		t.Errorf("expected the missing Amazon store to be skipped: %v", err)
+	}
+	if len(shortcuts) != 2 {
+		t.Errorf("expected the Epic games to be imported, got %+v", summarize(shortcuts))
+	}
+}
+
+func TestLutrisGameConfigs(t *testing.T) {
+	setupHome(t, "", testdata(t))
+	SQLiteBinary = "missing-sqlite3"
+	t.Cleanup(func() { SQLiteBinary = "sqlite3" })
+
+	l := &Lutris{}
+	if !l.Available() {
+		t.Fatal("expected Lutris to be available")
+	}
+	shortcuts, err := l.Import()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Games are read from the YAML configs when there is no database, with
+	// names from the config or the slug
+	want := []summary{
+		{"Broken", "lutris:rungame/broken", []string{"Lutris"}},
+		{"Celeste", "lutris:rungame/celeste", []string{"Lutris", "linux"}},
+		{"The Witcher 3", "lutris:rungame/the-witcher-3", []string{"Lutris", "wine"}},
+	}
+	if got := summarize(shortcuts); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected shortcuts:\n%+v\nwant:\n%+v", got, want)
+	}
+}
+
+func TestLutrisDatabase(t *testing.T) {
+	setupHome(t, "", testdata(t))
+
+	// A stand-in for sqlite3 that returns a game without a runner
+	tool := filepath.Join(t.TempDir(), "sqlite3")
+	rows := "1\x1fCeleste\x1flinux\x1e2\x1fManual Game\x1f\x1e"
+	err := os.WriteFile(tool, []byte("#!/bin/sh\nprintf '"+rows+"'\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SQLiteBinary = tool
+	t.Cleanup(func() { SQLiteBinary = "sqlite3" })
+
+	shortcuts, err := (&Lutris{}).Import()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []summary{
+		{"Celeste", "lutris:rungameid/1", []string{"Lutris", "linux"}},
+		{"Manual Game", "lutris:rungameid/2", []string{"Lutris"}},
+	}
+	if got := summarize(shortcuts); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected shortcuts:\n%+v\nwant:\n%+v", got, want)
+	}
+}
+
+func TestTitleFromSlug(t *testing.T) {
+	tests := map[string]string{
+		"the-witcher-3": "The Witcher 3",
+		"celeste":       "Celeste",
+		"double--dash-": "Double  Dash ",
+		"":              "",
+		"already-Upper": "Already Upper",
+	}
+	for slug, want := range tests {
+		if got := titleFromSlug(slug); got != want {
+			t.Errorf("titleFromSlug(%q) = %q, want %q", slug, got, want)
+		}
+	}
+}
+
+func TestNewShortcutSkipsEmptyTags(t *testing.T) {
+	l := &launcher{exe: "/usr/bin/flatpak", args: "run com.example.App"}
+	sc := l.newShortcut("Game", "--launch 1", "Launcher", "", " ")
+	if !reflect.DeepEqual(sc.TagList(), []string{"Launcher"}) {
+		t.Errorf("unexpected tags: %v", sc.TagList())
+	}
+	if sc.LaunchOptions != "run com.example.App --launch 1" || sc.StartDir != `"/usr/bin/"` {
+		t.Errorf("unexpected shortcut: %+v", sc)
+	}
+}
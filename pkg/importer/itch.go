@@ -0,0 +1,67 @@
+package importer
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+)
+
+func init() {
+	Register(&Itch{})
+}
+
+// Itch imports games installed with the itch app
+// https://itch.io/app
+type Itch struct{}
+
+// Name of the importer
+func (i *Itch) Name() string {
+	return "itch"
+}
+
+// Description of the importer
+func (i *Itch) Description() string {
+	return "Games installed with the itch app"
+}
+
+// Available returns whether or not the itch app has a database
+func (i *Itch) Available() bool {
+	_, ok := i.database()
+	return ok
+}
+
+// Import returns a shortcut for each installed itch game
+func (i *Itch) Import() ([]*shortcut.Shortcut, error) {
+	database, ok := i.database()
+	if !ok {
+		return nil, fmt.Errorf("no itch database found")
+	}
+	l := findLauncher("io.itch.itch", "", "itch", path.Join(homeDir(), ".itch", "itch"))
+
+	// Each installed game is a "cave" which can be launched by its ID
+	rows, err := querySQLite(
+		database,
+		"SELECT caves.id, games.title FROM caves JOIN games ON games.id = caves.game_id ORDER BY games.title",
+	)
+	if err != nil {
+		return nil, err
+	}
+	shortcuts := []*shortcut.Shortcut{}
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		args := fmt.Sprintf("itch://caves/%s/launch", row[0])
+		shortcuts = append(shortcuts, l.newShortcut(row[1], args, "itch.io"))
+	}
+
+	return shortcuts, nil
+}
+
+func (i *Itch) database() (string, bool) {
+	return firstExisting(
+		path.Join(configHome(), "itch", "db", "butler.db"),
+		flatpakDir("io.itch.itch", "config", "itch", "db", "butler.db"),
+	)
+}
@@ -0,0 +1,153 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	Register(&Lutris{})
+}
+
+// lutrisConfigSuffix matches the timestamp Lutris appends to game config
+// file names (e.g. "celeste-1650000000.yml").
+var lutrisConfigSuffix = regexp.MustCompile(`-[0-9]+$`)
+
+// Lutris imports games installed with Lutris
+// https://lutris.net
+type Lutris struct{}
+
+// Name of the importer
+func (l *Lutris) Name() string {
+	return "lutris"
+}
+
+// Description of the importer
+func (l *Lutris) Description() string {
+	return "Games installed with Lutris"
+}
+
+// Available returns whether or not Lutris has a data directory
+func (l *Lutris) Available() bool {
+	_, ok := l.dataDir()
+	return ok
+}
+
+// Import returns a shortcut for each installed Lutris game. Games are read
+// from the pga.db database, falling back to the YAML game configs if the
+// database cannot be read.
+func (l *Lutris) Import() ([]*shortcut.Shortcut, error) {
+	dataDir, ok := l.dataDir()
+	if !ok {
+		return nil, fmt.Errorf("no Lutris data directory found")
+	}
+	launcher := findLauncher("net.lutris.Lutris", "", "lutris", "/usr/bin/lutris")
+
+	// Read installed games from the database
+	rows, err := querySQLite(
+		path.Join(dataDir, "pga.db"),
+		"SELECT id, name, runner FROM games WHERE installed = 1 ORDER BY name",
+	)
+	if err == nil {
+		shortcuts := []*shortcut.Shortcut{}
+		for _, row := range rows {
+			if len(row) < 3 {
+				continue
+			}
+			args := fmt.Sprintf("lutris:rungameid/%s", row[0])
+			shortcuts = append(shortcuts, launcher.newShortcut(row[1], args, "Lutris", row[2]))
+		}
+		return shortcuts, nil
+	}
+
+	// Fall back to the game configs
+	configs, globErr := l.gameConfigs()
+	if globErr != nil || len(configs) == 0 {
+		return nil, err
+	}
+	shortcuts := []*shortcut.Shortcut{}
+	for _, config := range configs {
+		slug := lutrisConfigSuffix.ReplaceAllString(strings.TrimSuffix(filepath.Base(config), ".yml"), "")
+		name, runner := readLutrisConfig(config)
+		if name == "" {
+			name = titleFromSlug(slug)
+		}
+		tags := []string{"Lutris"}
+		if runner != "" {
+			tags = append(tags, runner)
+		}
+		args := fmt.Sprintf("lutris:rungame/%s", slug)
+		shortcuts = append(shortcuts, launcher.newShortcut(name, args, tags...))
+	}
+	sortShortcuts(shortcuts)
+	return shortcuts, nil
+}
+
+func (l *Lutris) dataDir() (string, bool) {
+	return firstExisting(
+		path.Join(dataHome(), "lutris"),
+		flatpakDir("net.lutris.Lutris", "data", "lutris"),
+	)
+}
+
+// gameConfigs will return the paths to all Lutris game config files
+func (l *Lutris) gameConfigs() ([]string, error) {
+	configs := []string{}
+	for _, dir := range []string{
+		path.Join(configHome(), "lutris", "games"),
+		path.Join(dataHome(), "lutris", "games"),
+		flatpakDir("net.lutris.Lutris", "config", "lutris", "games"),
+		flatpakDir("net.lutris.Lutris", "data", "lutris", "games"),
+	} {
+		matches, err := filepath.Glob(path.Join(dir, "*.yml"))
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, matches...)
+	}
+	return configs, nil
+}
+
+// readLutrisConfig will return the game name and runner from the given game
+// config, if they are set.
+func readLutrisConfig(file string) (string, string) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", ""
+	}
+	var config map[string]interface{}
+	if yaml.Unmarshal(data, &config) != nil {
+		return "", ""
+	}
+	name := ""
+	if game, ok := config["game"].(map[string]interface{}); ok {
+		name, _ = game["name"].(string)
+	}
+	runner := ""
+	for _, key := range []string{"wine", "linux", "dosbox", "libretro", "mame", "scummvm"} {
+		if _, ok := config[key]; ok {
+			runner = key
+			break
+		}
+	}
+	return name, runner
+}
+
+// titleFromSlug will convert a slug such as "the-witcher-3" into a title
+func titleFromSlug(slug string) string {
+	words := strings.Split(slug, "-")
+	for i, word := range words {
+		if word == "" {
+			continue
+		}
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,43 @@
+package importer
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// SQLiteBinary is the sqlite3 command-line tool used to read launcher
+// databases.
+var SQLiteBinary = "sqlite3"
+
+const (
+	sqliteFieldSeparator  = "\x1f"
+	sqliteRecordSeparator = "\x1e"
+)
+
+// querySQLite will run the given query against the given database file in
+// read-only mode and return the resulting rows.
+func querySQLite(database, query string) ([][]string, error) {
+	if _, err := exec.LookPath(SQLiteBinary); err != nil {
+		return nil, fmt.Errorf("%v is required to read %v", SQLiteBinary, database)
+	}
+	out, err := exec.Command(
+		SQLiteBinary,
+		"-readonly", "-batch", "-noheader",
+		"-separator", sqliteFieldSeparator,
+		"-newline", sqliteRecordSeparator,
+		database, query,
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to query %v: %v", database, err)
+	}
+
+	rows := [][]string{}
+	for _, record := range strings.Split(string(out), sqliteRecordSeparator) {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+		rows = append(rows, strings.Split(record, sqliteFieldSeparator))
+	}
+	return rows, nil
+}
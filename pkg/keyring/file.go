@@ -0,0 +1,115 @@
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/fileutil"
+	"gopkg.in/yaml.v3"
+)
+
+// FileStore stores secrets in a YAML file that only the current user can
+// read. It is used when the Secret Service is not available.
+type FileStore struct {
+	Path string
+}
+
+// NewFileStore will return a store that keeps secrets in the given file
+func NewFileStore(file string) *FileStore {
+	return &FileStore{Path: file}
+}
+
+// DefaultFile will return the default secrets file in $XDG_CONFIG_HOME
+// (~/.config).
+func DefaultFile() string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, _ := os.UserHomeDir()
+		configHome = path.Join(homeDir, ".config")
+	}
+	return path.Join(configHome, "steam-shortcut-manager", "secrets.yaml")
+}
+
+// Name will return the name of the store
+func (f *FileStore) Name() string {
+	return f.Path
+}
+
+// Get will return the secret with the given key
+func (f *FileStore) Get(key string) (string, error) {
+	secrets, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[key]
+	if !ok || secret == "" {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// Set will store the given secret with the given key
+func (f *FileStore) Set(key, secret string) error {
+	secrets, err := f.load()
+	if err != nil {
+		return err
+	}
+	secrets[key] = secret
+	return f.save(secrets)
+}
+
+// Delete will remove the secret with the given key. The file is removed when
+// it has no more secrets.
+func (f *FileStore) Delete(key string) error {
+	secrets, err := f.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[key]; !ok {
+		return nil
+	}
+	delete(secrets, key)
+	if len(secrets) == 0 {
+		return os.Remove(f.Path)
+	}
+	return f.save(secrets)
+}
+
+// load will read all secrets from the file. A missing file has no secrets.
+func (f *FileStore) load() (map[string]string, error) {
+	secrets := map[string]string{}
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(data, &secrets)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secrets file %v: %w", f.Path, err)
+	}
+	if secrets == nil {
+		secrets = map[string]string{}
+	}
+	return secrets, nil
+}
+
+// save will write the given secrets to the file, making sure that only the
+// current user can read it.
+func (f *FileStore) save(secrets map[string]string) error {
+	data, err := yaml.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(path.Dir(f.Path), 0700)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so the secrets are never readable by
+	// others, even if an existing file has a wider mode
+	return fileutil.WriteFile(f.Path, data, 0600)
+}
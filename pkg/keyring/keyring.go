@@ -0,0 +1,106 @@
+package keyring
+
+import (
+	"errors"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// ErrNotFound indicates that no secret is stored with the given key.
+var ErrNotFound = errors.New("secret not found")
+
+// Store is a place where secrets like API keys can be stored
+type Store interface {
+	// Name will return a human-readable name of the store
+	Name() string
+	// Get will return the secret with the given key, or ErrNotFound
+	Get(key string) (string, error)
+	// Set will store the given secret with the given key
+	Set(key, secret string) error
+	// Delete will remove the secret with the given key. Deleting a secret
+	// that does not exist is not an error.
+	Delete(key string) error
+}
+
+// Keyring looks up secrets in a list of stores in order of preference
+type Keyring struct {
+	Stores []Store
+}
+
+// New will return a keyring that uses the given stores in order of preference
+func New(stores ...Store) *Keyring {
+	return &Keyring{Stores: stores}
+}
+
+// Default will return a keyring that uses the freedesktop Secret Service and
+// falls back to a file in the user's config directory.
+func Default() *Keyring {
+	return New(NewSecretService(), NewFileStore(DefaultFile()))
+}
+
+// Get will return the secret with the given key from the first store that
+// has it. Stores that cannot be used are skipped, and are only an error if no
+// store could be used.
+func (k *Keyring) Get(key string) (string, error) {
+	var errs error
+	usable := false
+	for _, store := range k.Stores {
+		secret, err := store.Get(key)
+		if err == nil {
+			return secret, nil
+		}
+		if errors.Is(err, ErrNotFound) {
+			usable = true
+			continue
+		}
+		errs = multierror.Append(errs, err)
+	}
+	if !usable && errs != nil {
+		return "", errs
+	}
+	return "", ErrNotFound
+}
+
+// Set will store the given secret in the first store that can be used and
+// remove it from the stores after it. Returns the store the secret was saved
+// in.
+func (k *Keyring) Set(key, secret string) (Store, error) {
+	var errs error
+	for i, store := range k.Stores {
+		err := store.Set(key, secret)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		// Older copies in less preferred stores would be unused
+		for _, other := range k.Stores[i+1:] {
+			other.Delete(key)
+		}
+		return store, nil
+	}
+	if errs == nil {
+		errs = errors.New("no secret stores available")
+	}
+	return nil, errs
+}
+
+// Delete will remove the secret with the given key from all stores. Stores
+// that cannot be used are only an error if the secret was not removed from
+// any other store.
+func (k *Keyring) Delete(key string) error {
+	var errs error
+	deleted := false
+	for _, store := range k.Stores {
+		err := store.Delete(key)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		deleted = true
+	}
+	if deleted {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,182 @@
+package keyring
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeSecretTool is a stand-in for secret-tool that keeps each secret in a
+// file named after its key in $SECRETS_DIR.
+const fakeSecretTool = `#!/bin/sh
+for arg; do key="$arg"; done
+case "$1" in
+lookup) cat "$SECRETS_DIR/$key" 2>/dev/null || exit 1 ;;
+store) cat > "$SECRETS_DIR/$key" ;;
+clear) rm -f "$SECRETS_DIR/$key" ;;
+esac
+`
+
+// newFakeSecretService will return a Secret Service store that uses a
+// stand-in for secret-tool, along with the directory its secrets are in.
+func newFakeSecretService(t *testing.T) (*SecretService, string) {
+	t.Helper()
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "secret-tool")
+	err := os.WriteFile(tool, []byte(fakeSecretTool), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secretsDir := filepath.Join(dir, "secrets")
+	err = os.Mkdir(secretsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SECRETS_DIR", secretsDir)
+
+	return &SecretService{Tool: tool}, secretsDir
+}
+
+func TestSecretService(t *testing.T) {
+	store, dir := newFakeSecretService(t)
+	if _, err := store.Get("api-key"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	err := store.Set("api-key", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret, err := store.Get("api-key"); err != nil || secret != "secret" {
+		t.Errorf("expected stored secret, got %q (%v)", secret, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "api-key")); err != nil {
+		t.Errorf("secret was not passed to secret-tool: %v", err)
+	}
+	err = store.Delete("api-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("api-key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestSecretServiceStoreError(t *testing.T) {
+	tool := filepath.Join(t.TempDir(), "secret-tool")
+	err := os.WriteFile(tool, []byte("#!/bin/sh\nexit 1\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &SecretService{Tool: tool}
+
+	// Only a failed lookup or clear means that nothing was found
+	err = store.Set("api-key", "secret")
+	if err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("expected store to fail, got %v", err)
+	}
+	if _, err := store.Get("api-key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "steam-shortcut-manager", "secrets.yaml")
+	store := NewFileStore(file)
+	if _, err := store.Get("api-key"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	err := store.Set("api-key", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected file mode 0600, got %v", info.Mode().Perm())
+	}
+
+	// Existing files with a wider mode are replaced
+	err = os.Chmod(file, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Set("api-key", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err = os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected file mode 0600 after rewrite, got %v", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(file))
+	if len(entries) != 1 {
+		t.Errorf("expected temporary files to be removed, found %v files", len(entries))
+	}
+	if secret, err := store.Get("api-key"); err != nil || secret != "secret" {
+		t.Errorf("expected stored secret, got %q (%v)", secret, err)
+	}
+	err = store.Delete("api-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("empty secrets file was not removed: %v", err)
+	}
+}
+
+func TestKeyringFallback(t *testing.T) {
+	file := NewFileStore(filepath.Join(t.TempDir(), "secrets.yaml"))
+	missing := &SecretService{Tool: filepath.Join(t.TempDir(), "missing-secret-tool")}
+	keyring := New(missing, file)
+
+	if _, err := keyring.Get("api-key"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	store, err := keyring.Set("api-key", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store != file {
+		t.Errorf("expected the secret to be stored in the file, got %v", store.Name())
+	}
+	if secret, err := keyring.Get("api-key"); err != nil || secret != "secret" {
+		t.Errorf("expected stored secret, got %q (%v)", secret, err)
+	}
+	err = keyring.Delete("api-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keyring.Get("api-key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestKeyringPrefersSecretService(t *testing.T) {
+	secretService, _ := newFakeSecretService(t)
+	file := NewFileStore(filepath.Join(t.TempDir(), "secrets.yaml"))
+	err := file.Set("api-key", "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyring := New(secretService, file)
+
+	store, err := keyring.Set("api-key", "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store != secretService {
+		t.Errorf("expected the secret to be stored in the Secret Service, got %v", store.Name())
+	}
+	if _, err := file.Get("api-key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("old secret was not removed from the file: %v", err)
+	}
+	if secret, err := keyring.Get("api-key"); err != nil || secret != "new" {
+		t.Errorf("expected new secret, got %q (%v)", secret, err)
+	}
+}
@@ -0,0 +1,91 @@
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// secretService is the "service" attribute of the secrets we store
+const secretService = "steam-shortcut-manager"
+
+// SecretService stores secrets in the freedesktop Secret Service (e.g. GNOME
+// Keyring or KWallet) using the secret-tool command from libsecret.
+type SecretService struct {
+	// Tool is the secret-tool command to run. It can be replaced with a
+	// stand-in that implements the same arguments, e.g. for testing.
+	Tool string
+}
+
+// NewSecretService will return a store that uses secret-tool from the PATH
+func NewSecretService() *SecretService {
+	return &SecretService{Tool: "secret-tool"}
+}
+
+// Name will return the name of the store
+func (s *SecretService) Name() string {
+	return "Secret Service"
+}
+
+// Get will look up the secret with the given key
+func (s *SecretService) Get(key string) (string, error) {
+	out, err := s.run(nil, "lookup", "service", secretService, "key", key)
+	if err != nil {
+		return "", err
+	}
+
+	// secret-tool exits successfully with no output on some versions
+	secret := strings.TrimSuffix(out, "\n")
+	if secret == "" {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// Set will store the given secret with the given key
+func (s *SecretService) Set(key, secret string) error {
+	label := fmt.Sprintf("--label=%v %v", secretService, key)
+	_, err := s.run(strings.NewReader(secret), "store", label, "service", secretService, "key", key)
+	return err
+}
+
+// Delete will remove the secret with the given key
+func (s *SecretService) Delete(key string) error {
+	_, err := s.run(nil, "clear", "service", secretService, "key", key)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// run will run secret-tool with the given arguments and return its output.
+// secret-tool lookup and clear exit with an error and no message if nothing
+// was found.
+func (s *SecretService) run(stdin *strings.Reader, args ...string) (string, error) {
+	cmd := exec.Command(s.Tool, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" && (args[0] == "lookup" || args[0] == "clear") {
+			return "", ErrNotFound
+		}
+		if msg == "" {
+			msg = exitErr.Error()
+		}
+		return "", fmt.Errorf("%v %v failed: %v", s.Tool, args[0], msg)
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to use the Secret Service: %w", err)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,182 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format for command results
+type Format string
+
+const (
+	// Term is the human-readable format that each command prints itself
+	Term Format = "term"
+	// JSON prints the result as an indented JSON document
+	JSON Format = "json"
+	// YAML prints the result as a YAML document
+	YAML Format = "yaml"
+	// Table prints the result as aligned columns
+	Table Format = "table"
+	// NDJSON prints one JSON object per line for each row of the result
+	NDJSON Format = "ndjson"
+	// Template prints the result with a Go text/template
+	Template Format = "template"
+)
+
+// Formats are all supported output formats
+var Formats = []Format{Term, JSON, YAML, Table, NDJSON, Template}
+
+// ParseFormat will return the output format with the given name
+func ParseFormat(name string) (Format, error) {
+	names := []string{}
+	for _, format := range Formats {
+		if string(format) == name {
+			return format, nil
+		}
+		names = append(names, string(format))
+	}
+	return "", fmt.Errorf("unknown output format: %v (supported: %v)", name, strings.Join(names, ", "))
+}
+
+// Printer prints results in a structured output format. Results are printed
+// as they would be encoded to JSON, so every format uses the same keys.
+type Printer struct {
+	Format   Format
+	Template *template.Template
+	Out      io.Writer
+}
+
+// NewPrinter will return a printer for the given format. The template text is
+// required for the Template format.
+func NewPrinter(format Format, text string, out io.Writer) (*Printer, error) {
+	printer := &Printer{Format: format, Out: out}
+	if format != Template {
+		return printer, nil
+	}
+	if text == "" {
+		return nil, fmt.Errorf("the template output format requires --template")
+	}
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	printer.Template = tmpl
+
+	return printer, nil
+}
+
+// Print will print the given result. Names are used in the Table and NDJSON
+// formats as the column names of the keys of nested maps, followed by the
+// column name for plain values, e.g. "user" for a map of results by user.
+func (p *Printer) Print(result interface{}, names ...string) error {
+	switch p.Format {
+	case JSON:
+		out, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.Out, string(out))
+		return err
+	case YAML:
+		return p.printYAML(result)
+	case Table:
+		rows, err := Flatten(result, names...)
+		if err != nil {
+			return err
+		}
+		return printTable(p.Out, rows)
+	case NDJSON:
+		rows, err := Flatten(result, names...)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			_, err := fmt.Fprintln(p.Out, string(row.JSON()))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case Template:
+		data, err := toData(result)
+		if err != nil {
+			return err
+		}
+		err = p.Template.Execute(p.Out, data)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.Out)
+		return err
+	}
+	return fmt.Errorf("output format %v is printed by the command", p.Format)
+}
+
+// printYAML will print the given result as YAML, keeping the order of the
+// keys in its JSON encoding.
+func (p *Printer) printYAML(result interface{}) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	doc := &yaml.Node{}
+	err = yaml.Unmarshal(data, doc)
+	if err != nil {
+		return err
+	}
+	clearStyle(doc)
+
+	encoder := yaml.NewEncoder(p.Out)
+	encoder.SetIndent(2)
+	err = encoder.Encode(doc)
+	if err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// clearStyle will remove the JSON flow style and quoting from the given YAML
+// nodes so they are printed in block style.
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
+
+// toData will convert the given result into the plain maps, lists and values
+// of its JSON encoding, so templates use the same keys as the JSON output.
+func toData(result interface{}) (interface{}, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err = decoder.Decode(&value)
+	return value, err
+}
+
+// templateFuncs are extra functions that are available in templates
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		out, err := json.Marshal(v)
+		return string(out), err
+	},
+	"join": func(sep string, v []interface{}) string {
+		parts := []string{}
+		for _, item := range v {
+			parts = append(parts, fmt.Sprint(item))
+		}
+		return strings.Join(parts, sep)
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
@@ -0,0 +1,203 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type testShortcut struct {
+	Name  string   `json:"name"`
+	AppID int64    `json:"appid"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+type testShortcuts []*testShortcut
+
+func (s testShortcuts) Rows() interface{} {
+	return []string{"rows"}
+}
+
+// printResult will print the given result in the given format and return the
+// output.
+func printResult(t *testing.T, format Format, text string, result interface{}, names ...string) string {
+	t.Helper()
+	var out bytes.Buffer
+	printer, err := NewPrinter(format, text, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = printer.Print(result, names...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name   string
+		result interface{}
+		names  []string
+		want   []string
+	}{
+		{
+			name:   "plain value",
+			result: "foo",
+			want:   []string{`{"value":"foo"}`},
+		},
+		{
+			name:   "list of values",
+			result: []int{1, 2},
+			names:  []string{"id"},
+			want:   []string{`{"id":1}`, `{"id":2}`},
+		},
+		{
+			name:   "struct keeps field order",
+			result: &testShortcut{Name: "Foo", AppID: 1, Tags: []string{"a"}},
+			want:   []string{`{"name":"Foo","appid":1,"tags":["a"]}`},
+		},
+		{
+			name:   "nil pointer",
+			result: (*testShortcut)(nil),
+			want:   []string{},
+		},
+		{
+			name: "map of lists by sorted key",
+			result: map[string][]*testShortcut{
+				"2": {{Name: "Bar", AppID: 2}},
+				"1": {{Name: "Foo", AppID: 1}, {Name: "Baz", AppID: 3}},
+			},
+			names: []string{"user"},
+			want: []string{
+				`{"user":"1","name":"Foo","appid":1}`,
+				`{"user":"1","name":"Baz","appid":3}`,
+				`{"user":"2","name":"Bar","appid":2}`,
+			},
+		},
+		{
+			name:   "nested maps of values",
+			result: map[string]map[string]string{"1": {"hero": "a.png", "logo": "b.png"}},
+			names:  []string{"user", "image", "path"},
+			want: []string{
+				`{"user":"1","image":"hero","path":"a.png"}`,
+				`{"user":"1","image":"logo","path":"b.png"}`,
+			},
+		},
+		{
+			name:   "default key names",
+			result: map[string]int{"a": 1},
+			want:   []string{`{"key":"a","value":1}`},
+		},
+		{
+			name:   "tabular results",
+			result: testShortcuts{{Name: "Foo"}},
+			names:  []string{"id"},
+			want:   []string{`{"id":"rows"}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := Flatten(tt.result, tt.names...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, row := range rows {
+				got = append(got, string(row.JSON()))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("unexpected rows:\n%v\nwant:\n%v", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestPrintTable(t *testing.T) {
+	type tableRow struct {
+		Name   string            `json:"name"`
+		Tags   []string          `json:"tags,omitempty"`
+		Images map[string]string `json:"images,omitempty"`
+	}
+	result := map[string][]*tableRow{
+		"1": {{Name: "Foo", Tags: []string{"a", "b"}}},
+		"2": {{Name: "Two\twords", Images: map[string]string{"hero": "x"}}},
+	}
+	got := printResult(t, Table, "", result, "user")
+	want := strings.Join([]string{
+		"USER  NAME       TAGS  IMAGES",
+		"1     Foo        a,b   ",
+		`2     Two words        {"hero":"x"}`,
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("unexpected table:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestPrintNDJSON(t *testing.T) {
+	result := map[string][]*testShortcut{"1": {{Name: "Foo", AppID: 1}, {Name: "Bar", AppID: 2}}}
+	got := printResult(t, NDJSON, "", result, "user")
+	want := `{"user":"1","name":"Foo","appid":1}` + "\n" + `{"user":"1","name":"Bar","appid":2}` + "\n"
+	if got != want {
+		t.Errorf("unexpected ndjson:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestPrintYAML(t *testing.T) {
+	tests := []struct {
+		name   string
+		result interface{}
+		want   string
+	}{
+		{
+			name:   "strings that look like other types stay quoted",
+			result: map[string]string{"bool": "true", "number": "123", "empty": "", "null": "null", "text": "foo"},
+			want:   "bool: \"true\"\nempty: \"\"\n\"null\": \"null\"\nnumber: \"123\"\ntext: foo\n",
+		},
+		{
+			name:   "numeric keys are quoted",
+			result: map[string]int{"123": 1},
+			want:   "\"123\": 1\n",
+		},
+		{
+			name:   "struct keeps field order in block style",
+			result: &testShortcut{Name: "Foo", AppID: 1, Tags: []string{"a"}},
+			want:   "name: Foo\nappid: 1\ntags:\n  - a\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := printResult(t, YAML, "", tt.result)
+			if got != tt.want {
+				t.Errorf("unexpected yaml:\n%v\nwant:\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintTemplate(t *testing.T) {
+	result := map[string]*testShortcut{"1": {Name: "Foo", AppID: 3810698013, Tags: []string{"a", "b"}}}
+	got := printResult(t, Template, `{{range $user, $sc := .}}{{$user}} {{upper $sc.name}} {{$sc.appid}} {{join "," $sc.tags}}{{end}}`, result)
+	want := "1 FOO 3810698013 a,b\n"
+	if got != want {
+		t.Errorf("unexpected template output: %q, want %q", got, want)
+	}
+
+	_, err := NewPrinter(Template, "", &bytes.Buffer{})
+	if err == nil {
+		t.Error("expected an error for a missing template")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, format := range Formats {
+		got, err := ParseFormat(string(format))
+		if err != nil || got != format {
+			t.Errorf("unable to parse %v: %v", format, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
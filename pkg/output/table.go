@@ -0,0 +1,246 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Field is a single named value in a row
+type Field struct {
+	Name  string
+	Value json.RawMessage
+}
+
+// Row is a single row of a flattened result, with its fields in order
+type Row []Field
+
+// JSON will return the row as a JSON object
+func (r Row) JSON() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, field := range r {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		name, _ := json.Marshal(field.Name)
+		buf.Write(name)
+		buf.WriteString(":")
+		buf.Write(field.Value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes()
+}
+
+// Tabular is a result that is printed as different rows than its structure
+// in the Table and NDJSON formats, e.g. one row per shortcut for shortcuts
+// that are grouped by user.
+type Tabular interface {
+	Rows() interface{}
+}
+
+// Flatten will turn the given result into rows. Each item of a list and each
+// value of a map becomes a row, and the keys of the maps that lead to it are
+// added as fields named after the given names ("key" by default). Structs
+// become rows with a field for each of their JSON keys, and plain values
+// become a field named after the next name ("value" by default).
+func Flatten(result interface{}, names ...string) ([]Row, error) {
+	if tabular, ok := result.(Tabular); ok {
+		result = tabular.Rows()
+	}
+	return flatten(reflect.ValueOf(result), names, 0, Row{})
+}
+
+func flatten(value reflect.Value, names []string, depth int, prefix Row) ([]Row, error) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return []Row{}, nil
+		}
+		value = value.Elem()
+	}
+
+	rows := []Row{}
+	switch value.Kind() {
+	case reflect.Invalid:
+		return rows, nil
+
+	case reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		name := getName(names, depth, "key")
+		for _, key := range keys {
+			keyValue, err := json.Marshal(fmt.Sprint(key.Interface()))
+			if err != nil {
+				return nil, err
+			}
+			row := append(append(Row{}, prefix...), Field{Name: name, Value: keyValue})
+			children, err := flatten(value.MapIndex(key), names, depth+1, row)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, children...)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+			return plainRow(value, names, depth, prefix)
+		}
+		for i := 0; i < value.Len(); i++ {
+			children, err := flatten(value.Index(i), names, depth, prefix)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, children...)
+		}
+
+	case reflect.Struct:
+		fields, err := objectFields(value.Interface())
+		if err != nil {
+			return nil, err
+		}
+		if fields == nil {
+			return plainRow(value, names, depth, prefix)
+		}
+		rows = append(rows, append(append(Row{}, prefix...), fields...))
+
+	default:
+		return plainRow(value, names, depth, prefix)
+	}
+
+	return rows, nil
+}
+
+// plainRow will return a row for a value that is not a list, map or object
+func plainRow(value reflect.Value, names []string, depth int, prefix Row) ([]Row, error) {
+	data, err := json.Marshal(value.Interface())
+	if err != nil {
+		return nil, err
+	}
+	field := Field{Name: getName(names, depth, "value"), Value: data}
+	return []Row{append(append(Row{}, prefix...), field)}, nil
+}
+
+// objectFields will return the fields of the JSON object that the given value
+// encodes to, in order. Returns nil if the value does not encode to an object.
+func objectFields(value interface{}) (Row, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token != json.Delim('{') {
+		return nil, nil
+	}
+
+	fields := Row{}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var raw json.RawMessage
+		err = decoder.Decode(&raw)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, Field{Name: fmt.Sprint(key), Value: raw})
+	}
+	return fields, nil
+}
+
+// getName will return the name at the given depth, or the fallback
+func getName(names []string, depth int, fallback string) string {
+	if depth < len(names) {
+		return names[depth]
+	}
+	return fallback
+}
+
+// printTable will print the given rows as aligned columns. The columns are
+// the fields of all rows in the order they first appear.
+func printTable(out io.Writer, rows []Row) error {
+	columns := []string{}
+	seen := map[string]bool{}
+	for _, row := range rows {
+		for _, field := range row {
+			if !seen[field.Name] {
+				seen[field.Name] = true
+				columns = append(columns, field.Name)
+			}
+		}
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	header := []string{}
+	for _, column := range columns {
+		header = append(header, strings.ToUpper(column))
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		values := map[string]string{}
+		for _, field := range row {
+			values[field.Name] = cellValue(field.Value)
+		}
+		cells := []string{}
+		for _, column := range columns {
+			cells = append(cells, values[column])
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	return w.Flush()
+}
+
+// cellValue will return the text of a table cell for the given JSON value.
+// Strings are unquoted, lists of plain values are joined with commas and
+// other values are printed as compact JSON.
+func cellValue(value json.RawMessage) string {
+	var decoded interface{}
+	err := json.Unmarshal(value, &decoded)
+	if err != nil {
+		return string(value)
+	}
+	switch v := decoded.(type) {
+	case nil:
+		return ""
+	case string:
+		return sanitize(v)
+	case []interface{}:
+		parts := []string{}
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				return compact(value)
+			}
+			parts = append(parts, fmt.Sprint(item))
+		}
+		return sanitize(strings.Join(parts, ","))
+	case map[string]interface{}:
+		return compact(value)
+	}
+	return string(value)
+}
+
+// compact will return the given JSON value without whitespace
+func compact(value json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, value); err != nil {
+		return string(value)
+	}
+	return buf.String()
+}
+
+// sanitize will replace characters that would break the table layout
+func sanitize(s string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(s)
+}
@@ -0,0 +1,188 @@
+package roms
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Platform defines how ROMs for a single system are found and launched
+type Platform struct {
+	// Name is the short name of the platform (e.g. "snes")
+	Name string `yaml:"name" json:"name"`
+	// Title is the human readable name of the platform, used as a shortcut tag
+	Title string `yaml:"title" json:"title"`
+	// Aliases are other directory names that contain ROMs for the platform
+	Aliases []string `yaml:"aliases,omitempty" json:"aliases,omitempty"`
+	// Extensions are the ROM file extensions without a leading dot
+	Extensions []string `yaml:"extensions" json:"extensions"`
+	// Emulator is the program used to run the ROMs
+	Emulator Emulator `yaml:"emulator" json:"emulator"`
+	// Args is a text/template for the emulator arguments. The template is
+	// given the ROM path as .ROM, the cleaned title as .Title and the platform
+	// as .Platform. Use {{quote .ROM}} to quote paths.
+	Args string `yaml:"args" json:"args"`
+}
+
+// Emulator defines how to find an emulator executable
+type Emulator struct {
+	Name string `yaml:"name" json:"name"`
+	// Binaries are executable names or paths to look for, in order
+	Binaries []string `yaml:"binaries" json:"binaries"`
+	// Flatpak is the Flatpak app ID of the emulator, if available
+	Flatpak string `yaml:"flatpak,omitempty" json:"flatpak,omitempty"`
+}
+
+// HasExtension will return whether or not the platform uses the given file
+// extension (without a leading dot).
+func (p *Platform) HasExtension(ext string) bool {
+	for _, e := range p.Extensions {
+		if strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesDir will return whether or not the given directory name is the
+// platform's name or one of its aliases.
+func (p *Platform) MatchesDir(dir string) bool {
+	if strings.EqualFold(dir, p.Name) {
+		return true
+	}
+	for _, alias := range p.Aliases {
+		if strings.EqualFold(dir, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// Platforms is a table of platforms by name
+type Platforms map[string]*Platform
+
+// Get will return the platform with the given name or alias
+func (p Platforms) Get(name string) (*Platform, error) {
+	for _, platform := range p {
+		if platform.MatchesDir(name) {
+			return platform, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown platform: %v", name)
+}
+
+// Sorted will return the platforms sorted by name
+func (p Platforms) Sorted() []*Platform {
+	list := make([]*Platform, 0, len(p))
+	for _, platform := range p {
+		list = append(list, platform)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// LoadPlatforms will load a list of platforms from the given YAML file and
+// merge them with the built-in platforms. Platforms in the file replace
+// built-in platforms with the same name.
+func LoadPlatforms(file string) (Platforms, error) {
+	platforms := DefaultPlatforms()
+	if file == "" {
+		return platforms, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var custom []*Platform
+	err = yaml.Unmarshal(data, &custom)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %v: %v", file, err)
+	}
+	for _, platform := range custom {
+		if platform.Name == "" {
+			return nil, fmt.Errorf("platform in %v is missing a name", file)
+		}
+		if platform.Title == "" {
+			platform.Title = platform.Name
+		}
+		platforms[platform.Name] = platform
+	}
+
+	return platforms, nil
+}
+
+// retroarch returns a platform that runs the given RetroArch core
+func retroarch(name, title, core string, aliases []string, extensions ...string) *Platform {
+	return &Platform{
+		Name:       name,
+		Title:      title,
+		Aliases:    aliases,
+		Extensions: extensions,
+		Emulator: Emulator{
+			Name:     "RetroArch",
+			Binaries: []string{"retroarch"},
+			Flatpak:  "org.libretro.RetroArch",
+		},
+		Args: "-L " + core + " {{quote .ROM}}",
+	}
+}
+
+// DefaultPlatforms will return the built-in platform table
+func DefaultPlatforms() Platforms {
+	dolphin := Emulator{Name: "Dolphin", Binaries: []string{"dolphin-emu"}, Flatpak: "org.DolphinEmu.dolphin-emu"}
+	list := []*Platform{
+		retroarch("nes", "Nintendo Entertainment System", "fceumm_libretro", []string{"famicom"}, "nes", "unf", "fds"),
+		retroarch("snes", "Super Nintendo", "snes9x_libretro", []string{"sfc", "supernintendo"}, "sfc", "smc"),
+		retroarch("n64", "Nintendo 64", "mupen64plus_next_libretro", []string{"nintendo64"}, "n64", "z64", "v64"),
+		retroarch("gb", "Game Boy", "gambatte_libretro", []string{"gameboy"}, "gb"),
+		retroarch("gbc", "Game Boy Color", "gambatte_libretro", []string{"gameboycolor"}, "gbc"),
+		retroarch("gba", "Game Boy Advance", "mgba_libretro", []string{"gameboyadvance"}, "gba"),
+		retroarch("nds", "Nintendo DS", "melonds_libretro", []string{"ds"}, "nds"),
+		retroarch("genesis", "Sega Genesis", "genesis_plus_gx_libretro", []string{"megadrive", "md"}, "md", "gen", "smd"),
+		retroarch("mastersystem", "Sega Master System", "genesis_plus_gx_libretro", []string{"sms"}, "sms"),
+		retroarch("gamegear", "Sega Game Gear", "genesis_plus_gx_libretro", []string{"gg"}, "gg"),
+		retroarch("pcengine", "PC Engine", "mednafen_pce_fast_libretro", []string{"tg16", "turbografx16"}, "pce"),
+		retroarch("atari2600", "Atari 2600", "stella_libretro", []string{"a2600"}, "a26"),
+		retroarch("psx", "PlayStation", "pcsx_rearmed_libretro", []string{"ps1", "playstation"}, "cue", "chd", "pbp", "m3u"),
+		{
+			Name:       "ps2",
+			Title:      "PlayStation 2",
+			Aliases:    []string{"playstation2"},
+			Extensions: []string{"iso", "chd", "cso"},
+			Emulator:   Emulator{Name: "PCSX2", Binaries: []string{"pcsx2-qt", "pcsx2"}, Flatpak: "net.pcsx2.PCSX2"},
+			Args:       "-batch -fullscreen {{quote .ROM}}",
+		},
+		{
+			Name:       "psp",
+			Title:      "PlayStation Portable",
+			Extensions: []string{"iso", "cso"},
+			Emulator:   Emulator{Name: "PPSSPP", Binaries: []string{"PPSSPPSDL", "ppsspp"}, Flatpak: "org.ppsspp.PPSSPP"},
+			Args:       "{{quote .ROM}}",
+		},
+		{
+			Name:       "gamecube",
+			Title:      "Nintendo GameCube",
+			Aliases:    []string{"gc", "ngc"},
+			Extensions: []string{"iso", "gcm", "rvz", "ciso"},
+			Emulator:   dolphin,
+			Args:       "-b -e {{quote .ROM}}",
+		},
+		{
+			Name:       "wii",
+			Title:      "Nintendo Wii",
+			Extensions: []string{"wbfs", "rvz", "iso"},
+			Emulator:   dolphin,
+			Args:       "-b -e {{quote .ROM}}",
+		},
+	}
+
+	platforms := Platforms{}
+	for _, platform := range list {
+		platforms[platform.Name] = platform
+	}
+	return platforms
+}
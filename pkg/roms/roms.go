@@ -0,0 +1,159 @@
+package roms
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/flatpak"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/shortcut"
+)
+
+var (
+	// bracketedTags matches region and dump tags such as "(USA)" or "[!]"
+	bracketedTags = regexp.MustCompile(`\s*(\([^)]*\)|\[[^\]]*\])`)
+	// repeatedSpaces matches runs of whitespace
+	repeatedSpaces = regexp.MustCompile(`\s+`)
+	// trailingArticle matches titles such as "Legend of Zelda, The" and
+	// "Legend of Zelda, The - A Link to the Past"
+	trailingArticle = regexp.MustCompile(`^(.*?), (The|A|An)(\s*[-:].*)?$`)
+)
+
+// ROM is a single ROM file found while scanning
+type ROM struct {
+	Path     string    `json:"path"`
+	Title    string    `json:"title"`
+	Platform *Platform `json:"-"`
+}
+
+// CleanTitle will convert a ROM file name into a title suitable for a
+// shortcut name and for searching SteamGridDB.
+func CleanTitle(file string) string {
+	title := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	title = bracketedTags.ReplaceAllString(title, "")
+	title = strings.NewReplacer("_", " ", ".", " ").Replace(title)
+	title = strings.TrimSpace(repeatedSpaces.ReplaceAllString(title, " "))
+	if match := trailingArticle.FindStringSubmatch(title); match != nil {
+		title = match[2] + " " + match[1] + match[3]
+	}
+	return title
+}
+
+// Scan will recursively find ROMs in the given directory. If platform is not
+// nil, every file with one of its extensions is used. Otherwise the platform
+// is detected from the name of a parent directory (e.g. "roms/snes/"),
+// falling back to the file extension if only one platform uses it.
+func Scan(dir string, platforms Platforms, platform *Platform) ([]*ROM, error) {
+	roms := []*ROM{}
+	err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		ext := strings.TrimPrefix(filepath.Ext(file), ".")
+		if ext == "" {
+			return nil
+		}
+
+		romPlatform := platform
+		if romPlatform == nil {
+			romPlatform = detectPlatform(dir, file, platforms)
+		}
+		if romPlatform == nil || !romPlatform.HasExtension(ext) {
+			return nil
+		}
+		roms = append(roms, &ROM{Path: file, Title: CleanTitle(file), Platform: romPlatform})
+		return nil
+	})
+
+	return roms, err
+}
+
+// detectPlatform will return the platform of the given ROM file, or nil if
+// it cannot be detected.
+func detectPlatform(root, file string, platforms Platforms) *Platform {
+	// Check each parent directory up to the scan root
+	rel, err := filepath.Rel(root, filepath.Dir(file))
+	if err == nil {
+		dirs := append([]string{filepath.Base(root)}, strings.Split(rel, string(filepath.Separator))...)
+		for i := len(dirs) - 1; i >= 0; i-- {
+			if platform, err := platforms.Get(dirs[i]); err == nil {
+				return platform
+			}
+		}
+	}
+
+	// Fall back to a unique file extension
+	ext := strings.TrimPrefix(filepath.Ext(file), ".")
+	var found *Platform
+	for _, platform := range platforms {
+		if !platform.HasExtension(ext) {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = platform
+	}
+	return found
+}
+
+// Shortcut will return a new Steam shortcut that launches the ROM with the
+// platform's emulator.
+func (r *ROM) Shortcut() (*shortcut.Shortcut, error) {
+	emulator := r.Platform.Emulator
+	exe, prefix := flatpak.FindCommand(emulator.Flatpak, "", emulator.Binaries...)
+	if exe == "" {
+		return nil, fmt.Errorf("no emulator defined for platform: %v", r.Platform.Name)
+	}
+
+	args, err := r.renderArgs()
+	if err != nil {
+		return nil, err
+	}
+	if prefix != "" {
+		args = prefix + " " + args
+	}
+
+	shortcutConfiger := func(s *shortcut.Shortcut) {
+		s.LaunchOptions = args
+		s.StartDir = shortcut.Quote(path.Dir(exe) + "/")
+		s.SetTags([]string{r.Platform.Title, "ROMs"})
+		s.Appid = int64(shortcut.CalculateAppID(s.Exe, s.AppName))
+	}
+	sc := shortcut.NewShortcut(r.Title, shortcut.Quote(exe), shortcut.DefaultShortcut, shortcutConfiger)
+
+	return sc, nil
+}
+
+// renderArgs will render the platform's argument template for the ROM
+func (r *ROM) renderArgs() (string, error) {
+	funcs := template.FuncMap{
+		"quote": func(s string) string {
+			return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+		},
+	}
+	tmpl, err := template.New(r.Platform.Name).Funcs(funcs).Parse(r.Platform.Args)
+	if err != nil {
+		return "", fmt.Errorf("invalid args template for platform %v: %v", r.Platform.Name, err)
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, map[string]interface{}{
+		"ROM":      r.Path,
+		"Title":    r.Title,
+		"Platform": r.Platform,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
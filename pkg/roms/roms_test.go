@@ -0,0 +1,142 @@
+package roms
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newTestROMs will create the given empty files in a temporary directory and
+// return the directory.
+func newTestROMs(t *testing.T, files ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, nil, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// summarize will return the ROM paths relative to dir and their platforms
+func summarize(t *testing.T, dir string, roms []*ROM) [][2]string {
+	t.Helper()
+	result := [][2]string{}
+	for _, rom := range roms {
+		rel, err := filepath.Rel(dir, rom.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, [2]string{rel, rom.Platform.Name})
+	}
+	return result
+}
+
+func TestCleanTitle(t *testing.T) {
+	tests := map[string]string{
+		"Super Mario World (USA).sfc":                                 "Super Mario World",
+		"/roms/snes/Super Metroid (Japan, USA) (Rev 1) [!].sfc":       "Super Metroid",
+		"Legend of Zelda, The - A Link to the Past (USA).sfc":         "The Legend of Zelda - A Link to the Past",
+		"Legend of Zelda, The (Europe) (Rev A).nes":                   "The Legend of Zelda",
+		"Adventure, An.a26":                                           "An Adventure",
+		"Sonic_The_Hedgehog_(World).md":                               "Sonic The Hedgehog",
+		"Pokemon - Red Version (USA, Europe) (SGB Enhanced).gb":       "Pokemon - Red Version",
+		"Final Fantasy VII (USA) (Disc 1) [b].cue":                    "Final Fantasy VII",
+		"Metroid.Prime.(v1.02).iso":                                   "Metroid Prime",
+		"Tetris   [T+Fre].gb":                                         "Tetris",
+		"Castlevania - Aria of Sorrow (Europe) (En,Fr,De) (Beta).gba": "Castlevania - Aria of Sorrow",
+	}
+	for file, want := range tests {
+		if got := CleanTitle(file); got != want {
+			t.Errorf("CleanTitle(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := newTestROMs(t,
+		"snes/Super Metroid (USA).sfc",
+		"snes/readme.txt",
+		"SNES/Chrono Trigger.smc",
+		"megadrive/Sonic.bin",
+		"megadrive/Streets of Rage.md",
+		"nintendo/gba/Metroid Fusion.gba",
+		"nintendo/gba/saves/Metroid Fusion.gba",
+		"psx/Spyro.iso",
+		"unsorted/Tetris.gb",
+		"unsorted/Ico.iso",
+		"unsorted/noextension",
+	)
+	roms, err := Scan(dir, DefaultPlatforms(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Platforms are detected from the nearest platform directory or a unique
+	// extension, and files with another platform's extension are skipped
+	want := [][2]string{
+		{"SNES/Chrono Trigger.smc", "snes"},
+		{"megadrive/Streets of Rage.md", "genesis"},
+		{"nintendo/gba/Metroid Fusion.gba", "gba"},
+		{"nintendo/gba/saves/Metroid Fusion.gba", "gba"},
+		{"snes/Super Metroid (USA).sfc", "snes"},
+		{"unsorted/Tetris.gb", "gb"},
+	}
+	if got := summarize(t, dir, roms); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected ROMs:\n%v\nwant:\n%v", got, want)
+	}
+	for _, rom := range roms {
+		if rom.Title != CleanTitle(rom.Path) {
+			t.Errorf("unexpected title: %v", rom.Title)
+		}
+	}
+}
+
+func TestScanPlatformDir(t *testing.T) {
+	// The scanned directory itself can be the platform directory
+	dir := newTestROMs(t, "gba/Metroid Fusion.gba", "gba/Golden Sun.iso")
+	roms, err := Scan(filepath.Join(dir, "gba"), DefaultPlatforms(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{{"gba/Metroid Fusion.gba", "gba"}}
+	if got := summarize(t, dir, roms); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected ROMs: %v", got)
+	}
+}
+
+func TestScanWithPlatform(t *testing.T) {
+	dir := newTestROMs(t, "psx/Okami.iso", "unsorted/Shadow of the Colossus.ISO", "unsorted/Tetris.gb")
+	platforms := DefaultPlatforms()
+	ps2, err := platforms.Get("playstation2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every file with one of the platform's extensions is used, regardless
+	// of the directory it is in
+	roms, err := Scan(dir, platforms, ps2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{
+		{"psx/Okami.iso", "ps2"},
+		{"unsorted/Shadow of the Colossus.ISO", "ps2"},
+	}
+	if got := summarize(t, dir, roms); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected ROMs: %v", got)
+	}
+
+	_, err = Scan(filepath.Join(dir, "missing"), platforms, nil)
+	if err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
@@ -20,5 +20,14 @@ func CalculateBPMID(exe, name string) uint64 {
 
 func CalculateAppID(exe, name string) uint64 {
 	combined := exe + name
-	return uint64(crc32.ChecksumIEEE([]byte(combined))) | 0x80000000
+	return uint64(crc32.ChecksumIEEE([]byte(combined))) | shortcutAppIDBit
+}
+
+// shortcutAppIDBit is set in the app ids of all non-Steam shortcuts
+const shortcutAppIDBit = 0x80000000
+
+// IsShortcutAppID will return whether or not the given app id is in the range
+// used by non-Steam shortcuts instead of Steam games.
+func IsShortcutAppID(appId uint64) bool {
+	return appId >= shortcutAppIDBit
 }
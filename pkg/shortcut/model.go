@@ -1,8 +1,11 @@
 package shortcut
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam"
 )
 
 /*
@@ -30,6 +33,9 @@ import (
 }
 */
 
+// ErrNotFound indicates that no shortcut matches the given name or app id.
+var ErrNotFound = errors.New("no shortcut found")
+
 func NewShortcuts() *Shortcuts {
 	return &Shortcuts{Shortcuts: map[string]Shortcut{}}
 }
@@ -56,7 +62,7 @@ func (s *Shortcuts) LookupByName(name string) (*Shortcut, error) {
 			return &sc, nil
 		}
 	}
-	return nil, fmt.Errorf("no shortcut found with name: %v", name)
+	return nil, fmt.Errorf("%w with name: %v", ErrNotFound, name)
 }
 
 // LookupByID will return a shortcut by name
@@ -66,7 +72,18 @@ func (s *Shortcuts) LookupByID(appId int64) (*Shortcut, error) {
 			return &sc, nil
 		}
 	}
-	return nil, fmt.Errorf("no shortcut found with id: %v", appId)
+	return nil, fmt.Errorf("%w with id: %v", ErrNotFound, appId)
+}
+
+// Lookup will return the key and shortcut that matches the given name or app
+// id.
+func (s *Shortcuts) Lookup(nameOrID string) (string, *Shortcut, error) {
+	for key, sc := range s.Shortcuts {
+		if sc.AppName == nameOrID || fmt.Sprintf("%v", sc.Appid) == nameOrID {
+			return key, &sc, nil
+		}
+	}
+	return "", nil, fmt.Errorf("%w with name or id: %v", ErrNotFound, nameOrID)
 }
 
 // Get the next shortcut id
@@ -124,8 +141,13 @@ type Shortcut struct {
 	Icon                string                 `json:"icon"`
 	Tags                map[string]interface{} `json:"tags"`
 	Images              *Images                `json:"images,omitempty"`
+	CompatTool          string                 `json:"compatTool,omitempty"`
 }
 
+// nonVDFFields are fields of a Shortcut that are discovered from other Steam
+// files and are not stored in shortcuts.vdf.
+var nonVDFFields = []string{"images", "compatTool"}
+
 // Images is a structure that holds the paths to grid images for a shortcut.
 type Images struct {
 	Portrait  string `json:"portrait"`
@@ -133,4 +155,6 @@ type Images struct {
 	Hero      string `json:"hero"`
 	Logo      string `json:"logo"`
 	Icon      string `json:"icon"`
+
+	LogoPosition *steam.LogoPosition `json:"logoPosition,omitempty"`
 }
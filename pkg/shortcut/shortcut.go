@@ -53,6 +53,17 @@ func Save(shortcuts *Shortcuts, file string) error {
 		return fmt.Errorf("Unable to unmarshal to VDF Map: %v", err)
 	}
 
+	// Remove fields that do not belong in shortcuts.vdf
+	if entries, ok := vdfMap["shortcuts"].(map[string]interface{}); ok {
+		for _, entry := range entries {
+			if fields, ok := entry.(map[string]interface{}); ok {
+				for _, field := range nonVDFFields {
+					delete(fields, field)
+				}
+			}
+		}
+	}
+
 	// Save the shortcuts
 	rawVdf, err := vdf.WriteVdf(ensureVDFMap(vdfMap))
 	if err != nil {
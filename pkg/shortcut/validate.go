@@ -0,0 +1,287 @@
+package shortcut
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Problem describes a single way in which a shortcut does not follow Steam's
+// conventions.
+type Problem struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	// Fixable is whether or not the problem can be fixed automatically
+	Fixable bool `json:"fixable"`
+	// Info is whether or not the problem is only informational. Steam
+	// accepts the shortcut as it is, so it does not need to be fixed.
+	Info bool `json:"info,omitempty"`
+
+	fix func(s *Shortcut)
+}
+
+func (p *Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Field, p.Message)
+}
+
+// Validator is a function that checks a shortcut for a single kind of problem
+type Validator func(s *Shortcut) []*Problem
+
+// Validators is the list of checks that are run by Validate, in order.
+var Validators = []Validator{
+	ValidateExe,
+	ValidateStartDir,
+	ValidateIcon,
+	ValidateTags,
+	ValidateAppID,
+}
+
+// Validate will check the given shortcut against Steam's conventions and
+// return any problems found.
+func Validate(s *Shortcut) []*Problem {
+	problems := []*Problem{}
+	for _, validator := range Validators {
+		problems = append(problems, validator(s)...)
+	}
+	return problems
+}
+
+// Fix will apply automatic fixes to the given shortcut until no fixable
+// problems remain. Returns the problems that were fixed and the problems that
+// still need to be fixed manually. Informational problems are not returned.
+func Fix(s *Shortcut) (fixed []*Problem, remaining []*Problem) {
+	return FixWith(s, Validators...)
+}
+
+// FixWith will apply automatic fixes like Fix, but only for the problems found
+// by the given validators. This is used to check only the fields of a shortcut
+// that were changed.
+func FixWith(s *Shortcut, validators ...Validator) (fixed []*Problem, remaining []*Problem) {
+	fixed = []*Problem{}
+	// Fixes can affect later checks (e.g. resolving the exe makes the start
+	// directory fixable), so validate again after each fix.
+	for i := 0; i < len(validators)*2; i++ {
+		applied := false
+		remaining = []*Problem{}
+		problems := []*Problem{}
+		for _, validator := range validators {
+			problems = append(problems, validator(s)...)
+		}
+		for _, problem := range problems {
+			if problem.Info {
+				continue
+			}
+			if !problem.Fixable || applied {
+				remaining = append(remaining, problem)
+				continue
+			}
+			problem.fix(s)
+			fixed = append(fixed, problem)
+			applied = true
+		}
+		if !applied {
+			break
+		}
+	}
+	return fixed, remaining
+}
+
+// ValidateExe will check that the executable is quoted and exists
+func ValidateExe(s *Shortcut) []*Problem {
+	problems := []*Problem{}
+	if strings.TrimSpace(s.Exe) == "" {
+		return append(problems, &Problem{Field: "Exe", Message: "executable is empty"})
+	}
+	exe := Unquote(s.Exe)
+
+	// Steam requires absolute paths to executables
+	if !filepath.IsAbs(exe) {
+		found, err := exec.LookPath(exe)
+		if err != nil {
+			return append(problems, &Problem{
+				Field:   "Exe",
+				Message: fmt.Sprintf("executable is not an absolute path and was not found in PATH: %v", exe),
+			})
+		}
+		return append(problems, &Problem{
+			Field:   "Exe",
+			Message: fmt.Sprintf("executable is not an absolute path: %v", exe),
+			Fixable: true,
+			fix:     func(s *Shortcut) { s.Exe = Quote(found) },
+		})
+	}
+	if !IsQuoted(s.Exe) {
+		problems = append(problems, &Problem{
+			Field:   "Exe",
+			Message: "executable path is not quoted",
+			Fixable: true,
+			fix:     func(s *Shortcut) { s.Exe = Quote(exe) },
+		})
+	}
+	if _, err := os.Stat(exe); err != nil {
+		problems = append(problems, &Problem{
+			Field:   "Exe",
+			Message: fmt.Sprintf("executable does not exist: %v", exe),
+		})
+	}
+
+	return problems
+}
+
+// ValidateStartDir will check that the start directory is set, quoted and
+// exists.
+func ValidateStartDir(s *Shortcut) []*Problem {
+	problems := []*Problem{}
+	if strings.TrimSpace(s.StartDir) == "" {
+		exe := Unquote(s.Exe)
+		return append(problems, &Problem{
+			Field:   "StartDir",
+			Message: "start directory is empty",
+			Fixable: filepath.IsAbs(exe),
+			fix:     func(s *Shortcut) { s.StartDir = Quote(filepath.Dir(exe) + "/") },
+		})
+	}
+	dir := Unquote(s.StartDir)
+	if !IsQuoted(s.StartDir) {
+		problems = append(problems, &Problem{
+			Field:   "StartDir",
+			Message: "start directory is not quoted",
+			Fixable: true,
+			fix:     func(s *Shortcut) { s.StartDir = Quote(dir) },
+		})
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		problems = append(problems, &Problem{
+			Field:   "StartDir",
+			Message: fmt.Sprintf("start directory does not exist: %v", dir),
+		})
+	}
+
+	return problems
+}
+
+// ValidateIcon will check that the icon, if set, is readable
+func ValidateIcon(s *Shortcut) []*Problem {
+	problems := []*Problem{}
+	if s.Icon == "" {
+		return problems
+	}
+	f, err := os.Open(Unquote(s.Icon))
+	if err != nil {
+		return append(problems, &Problem{
+			Field:   "icon",
+			Message: fmt.Sprintf("icon is not readable: %v", err),
+			Fixable: true,
+			fix:     func(s *Shortcut) { s.Icon = "" },
+		})
+	}
+	f.Close()
+
+	return problems
+}
+
+// ValidateTags will check that no tags are duplicated
+func ValidateTags(s *Shortcut) []*Problem {
+	problems := []*Problem{}
+	seen := map[string]bool{}
+	for _, tag := range s.TagList() {
+		if seen[tag] {
+			problems = append(problems, &Problem{
+				Field:   "tags",
+				Message: fmt.Sprintf("duplicate tag: %v", tag),
+				Fixable: true,
+				fix:     func(s *Shortcut) { s.SetTags(dedupe(s.TagList())) },
+			})
+		}
+		seen[tag] = true
+	}
+
+	return problems
+}
+
+// ValidateAppID will check that the app id matches the one Steam calculates
+// from the executable and name. Steam keeps the original app id when a
+// shortcut is renamed in Steam, and play time, controller layouts and
+// screenshots belong to that app id, so a mismatch is only informational.
+func ValidateAppID(s *Shortcut) []*Problem {
+	problems := []*Problem{}
+	expected := int64(CalculateAppID(s.Exe, s.AppName))
+	if s.Appid == expected {
+		return problems
+	}
+	return append(problems, &Problem{
+		Field:   "appid",
+		Message: fmt.Sprintf("app id %v does not match calculated app id %v (expected if the shortcut was renamed in Steam)", s.Appid, expected),
+		Info:    true,
+	})
+}
+
+// TagList will return the shortcut's tags in order
+func (s *Shortcut) TagList() []string {
+	keys := make([]string, 0, len(s.Tags))
+	for key := range s.Tags {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, errA := strconv.Atoi(keys[i])
+		b, errB := strconv.Atoi(keys[j])
+		if errA != nil || errB != nil {
+			return keys[i] < keys[j]
+		}
+		return a < b
+	})
+
+	tags := []string{}
+	for _, key := range keys {
+		tags = append(tags, fmt.Sprintf("%v", s.Tags[key]))
+	}
+	return tags
+}
+
+// SetTags will replace the shortcut's tags with the given tags
+func (s *Shortcut) SetTags(tags []string) {
+	s.Tags = map[string]interface{}{}
+	for key, tag := range tags {
+		s.Tags[fmt.Sprintf("%v", key)] = tag
+	}
+}
+
+// IsQuoted will return whether or not the given path is wrapped in double
+// quotes, as Steam expects for Exe and StartDir.
+func IsQuoted(s string) bool {
+	return len(s) >= 2 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`)
+}
+
+// Quote will wrap the given path in double quotes if it is not already quoted
+func Quote(s string) string {
+	if IsQuoted(s) {
+		return s
+	}
+	return `"` + s + `"`
+}
+
+// Unquote will remove surrounding double quotes from the given path
+func Unquote(s string) string {
+	if IsQuoted(s) {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// dedupe will remove duplicate strings while preserving order
+func dedupe(items []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, item := range items {
+		if seen[item] {
+			continue
+		}
+		seen[item] = true
+		result = append(result, item)
+	}
+	return result
+}
@@ -0,0 +1,226 @@
+package shortcut
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestGame will create an executable named "game" in a temporary
+// directory, add the directory to PATH and return the executable's path.
+func newTestGame(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "game")
+	err := os.WriteFile(exe, []byte("#!/bin/sh\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	return exe
+}
+
+// fields will return the fields of the given problems
+func fields(problems []*Problem) []string {
+	result := []string{}
+	for _, problem := range problems {
+		result = append(result, problem.Field)
+	}
+	return result
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		value    string
+		quoted   bool
+		quote    string
+		unquoted string
+	}{
+		{"/usr/bin/game", false, `"/usr/bin/game"`, "/usr/bin/game"},
+		{`"/usr/bin/game"`, true, `"/usr/bin/game"`, "/usr/bin/game"},
+		{`"/path with spaces/game"`, true, `"/path with spaces/game"`, "/path with spaces/game"},
+		{`"`, false, `"""`, `"`},
+		{`""`, true, `""`, ""},
+		{"", false, `""`, ""},
+	}
+	for _, tt := range tests {
+		if got := IsQuoted(tt.value); got != tt.quoted {
+			t.Errorf("IsQuoted(%q) = %v, want %v", tt.value, got, tt.quoted)
+		}
+		if got := Quote(tt.value); got != tt.quote {
+			t.Errorf("Quote(%q) = %q, want %q", tt.value, got, tt.quote)
+		}
+		if got := Unquote(tt.value); got != tt.unquoted {
+			t.Errorf("Unquote(%q) = %q, want %q", tt.value, got, tt.unquoted)
+		}
+	}
+}
+
+func TestValidateExe(t *testing.T) {
+	game := newTestGame(t)
+	tests := []struct {
+		name    string
+		exe     string
+		want    []string
+		fixable bool
+		fixed   string
+	}{
+		{"valid", Quote(game), nil, false, ""},
+		{"empty", " ", []string{"executable is empty"}, false, ""},
+		{"not quoted", game, []string{"executable path is not quoted"}, true, Quote(game)},
+		{"found in PATH", "game", []string{"executable is not an absolute path: game"}, true, Quote(game)},
+		{"quoted and found in PATH", `"game"`, []string{"executable is not an absolute path: game"}, true, Quote(game)},
+		{"not found in PATH", "missing", []string{"executable is not an absolute path and was not found in PATH: missing"}, false, ""},
+		{"does not exist", `"/missing/game"`, []string{"executable does not exist: /missing/game"}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := ValidateExe(&Shortcut{Exe: tt.exe})
+			messages := []string{}
+			for _, problem := range problems {
+				messages = append(messages, problem.Message)
+			}
+			if strings.Join(messages, "\n") != strings.Join(tt.want, "\n") {
+				t.Fatalf("unexpected problems: %v", messages)
+			}
+			if len(problems) == 0 {
+				return
+			}
+			if problems[0].Fixable != tt.fixable {
+				t.Fatalf("expected fixable to be %v", tt.fixable)
+			}
+			if tt.fixable {
+				sc := &Shortcut{Exe: tt.exe}
+				problems[0].fix(sc)
+				if sc.Exe != tt.fixed {
+					t.Errorf("unexpected fixed exe: %v", sc.Exe)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateStartDir(t *testing.T) {
+	game := newTestGame(t)
+	dir := filepath.Dir(game)
+	tests := []struct {
+		name     string
+		exe      string
+		startDir string
+		want     []string
+		fixed    string
+	}{
+		{"valid", Quote(game), Quote(dir), nil, ""},
+		{"empty", Quote(game), "", []string{"start directory is empty"}, Quote(dir + "/")},
+		{"empty with relative exe", "game", "", []string{"start directory is empty"}, ""},
+		{"not quoted", Quote(game), dir, []string{"start directory is not quoted"}, Quote(dir)},
+		{"does not exist", Quote(game), `"/missing"`, []string{"start directory does not exist: /missing"}, ""},
+		{"is a file", Quote(game), Quote(game), []string{"start directory does not exist: " + game}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := &Shortcut{Exe: tt.exe, StartDir: tt.startDir}
+			problems := ValidateStartDir(sc)
+			messages := []string{}
+			for _, problem := range problems {
+				messages = append(messages, problem.Message)
+			}
+			if strings.Join(messages, "\n") != strings.Join(tt.want, "\n") {
+				t.Fatalf("unexpected problems: %v", messages)
+			}
+			if len(problems) > 0 && problems[0].Fixable != (tt.fixed != "") {
+				t.Fatalf("expected fixable to be %v", tt.fixed != "")
+			}
+			if tt.fixed != "" {
+				problems[0].fix(sc)
+				if sc.StartDir != tt.fixed {
+					t.Errorf("unexpected fixed start dir: %v", sc.StartDir)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAppID(t *testing.T) {
+	game := newTestGame(t)
+	sc := &Shortcut{Exe: Quote(game), AppName: "Game"}
+	sc.Appid = int64(CalculateAppID(sc.Exe, sc.AppName))
+	if problems := ValidateAppID(sc); len(problems) != 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+
+	// Steam keeps the app id of a shortcut that was renamed in Steam
+	sc.AppName = "Renamed"
+	problems := ValidateAppID(sc)
+	if len(problems) != 1 || !problems[0].Info || problems[0].Fixable {
+		t.Fatalf("expected an informational problem, got %+v", problems)
+	}
+}
+
+func TestFix(t *testing.T) {
+	game := newTestGame(t)
+	icon := filepath.Join(t.TempDir(), "missing.png")
+	sc := &Shortcut{AppName: "Game", Exe: "game", Icon: icon, Appid: 1}
+	sc.SetTags([]string{"RPG", "Indie", "RPG"})
+
+	// Resolving the exe makes the start directory fixable, so each fix is
+	// followed by another round of validation
+	fixed, remaining := Fix(sc)
+	if len(remaining) != 0 {
+		t.Errorf("unexpected remaining problems: %v", remaining)
+	}
+	want := []string{"Exe", "StartDir", "icon", "tags"}
+	if !reflect.DeepEqual(fields(fixed), want) {
+		t.Errorf("unexpected fixed problems: %v", fixed)
+	}
+	if sc.Exe != Quote(game) || sc.StartDir != Quote(filepath.Dir(game)+"/") || sc.Icon != "" {
+		t.Errorf("unexpected shortcut: %+v", sc)
+	}
+	if !reflect.DeepEqual(sc.TagList(), []string{"RPG", "Indie"}) {
+		t.Errorf("unexpected tags: %v", sc.TagList())
+	}
+
+	// The app id is never changed, but the mismatch is still reported
+	if sc.Appid != 1 {
+		t.Errorf("app id was changed to %v", sc.Appid)
+	}
+	if problems := Validate(sc); !reflect.DeepEqual(fields(problems), []string{"appid"}) {
+		t.Errorf("unexpected problems: %v", problems)
+	}
+
+	// Problems that cannot be fixed are returned
+	sc = &Shortcut{AppName: "Game", Exe: `"/missing/game"`, StartDir: `"/missing"`}
+	fixed, remaining = Fix(sc)
+	if len(fixed) != 0 || !reflect.DeepEqual(fields(remaining), []string{"Exe", "StartDir"}) {
+		t.Errorf("unexpected result: %v, %v", fixed, remaining)
+	}
+}
+
+func TestFixWith(t *testing.T) {
+	newTestGame(t)
+	sc := &Shortcut{AppName: "Game", Exe: "game", StartDir: `"/missing"`}
+	sc.SetTags([]string{"RPG", "RPG"})
+
+	// Only the problems found by the given validators are fixed or returned
+	fixed, remaining := FixWith(sc, ValidateTags)
+	if !reflect.DeepEqual(fields(fixed), []string{"tags"}) || len(remaining) != 0 {
+		t.Errorf("unexpected result: %v, %v", fixed, remaining)
+	}
+	if sc.Exe != "game" || !reflect.DeepEqual(sc.TagList(), []string{"RPG"}) {
+		t.Errorf("unexpected shortcut: %+v", sc)
+	}
+
+	fixed, remaining = FixWith(sc, ValidateExe, ValidateStartDir)
+	if !reflect.DeepEqual(fields(fixed), []string{"Exe"}) || !reflect.DeepEqual(fields(remaining), []string{"StartDir"}) {
+		t.Errorf("unexpected result: %v, %v", fixed, remaining)
+	}
+}
+
+func TestTagList(t *testing.T) {
+	sc := &Shortcut{Tags: map[string]interface{}{"10": "k", "2": "c", "0": "a", "1": "b"}}
+	if got := sc.TagList(); !reflect.DeepEqual(got, []string{"a", "b", "c", "k"}) {
+		t.Errorf("tags are not in numeric order: %v", got)
+	}
+}
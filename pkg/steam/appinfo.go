@@ -0,0 +1,285 @@
+package steam
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	vdfbinary "github.com/shadowblip/steam-shortcut-manager/pkg/vdf/binary"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/vdf/text"
+)
+
+// Versions of the appinfo.vdf format, identified by the magic number at the
+// start of the file.
+const (
+	AppInfoVersion27 uint32 = 0x07564427
+	// AppInfoVersion28 adds a hash of the binary app data to each entry
+	AppInfoVersion28 uint32 = 0x07564428
+	// AppInfoVersion29 stores keys in a string table at the end of the file
+	AppInfoVersion29 uint32 = 0x07564429
+)
+
+// AppInfo is the metadata of a Steam app from Steam's appinfo.vdf cache
+type AppInfo struct {
+	AppID        uint32    `json:"appid"`
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`
+	LastUpdated  time.Time `json:"lastUpdated"`
+	ChangeNumber uint32    `json:"changeNumber"`
+	// Assets are the hashes or file names of the app's artwork, e.g. "icon"
+	// or "library_hero"
+	Assets map[string]string `json:"assets"`
+	// StoreTags are the ids of the app's user-defined tags in the Steam store
+	StoreTags     []int           `json:"storeTags"`
+	LaunchConfigs []*LaunchConfig `json:"launchConfigs"`
+
+	// Data is all of the app's metadata
+	Data *text.Node `json:"-"`
+}
+
+// LaunchConfig is a way to launch a Steam app
+type LaunchConfig struct {
+	ID          string `json:"id"`
+	Executable  string `json:"executable"`
+	Arguments   string `json:"arguments,omitempty"`
+	WorkingDir  string `json:"workingdir,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	OSList      string `json:"oslist,omitempty"`
+	OSArch      string `json:"osarch,omitempty"`
+	BetaKey     string `json:"betakey,omitempty"`
+}
+
+// commonAssets are the artwork hashes stored directly in the "common" section
+var commonAssets = []string{"icon", "clienticon", "clienttga", "logo", "logo_small"}
+
+// GetAppInfoPath will return the path to Steam's appinfo.vdf cache
+func GetAppInfoPath() (string, error) {
+	steamDir, err := GetBaseDir()
+	if err != nil {
+		return "", err
+	}
+	return path.Join(steamDir, "appcache", "appinfo.vdf"), nil
+}
+
+// GetAppInfo will return the metadata of the given app from Steam's
+// appinfo.vdf cache.
+func GetAppInfo(appId uint32) (*AppInfo, error) {
+	appInfoPath, err := GetAppInfoPath()
+	if err != nil {
+		return nil, err
+	}
+	apps, err := ReadAppInfo(appInfoPath, appId)
+	if err != nil {
+		return nil, err
+	}
+	if len(apps) == 0 {
+		return nil, fmt.Errorf("no app info found for app id: %v", appId)
+	}
+	return apps[0], nil
+}
+
+// ReadAppInfo will read the given appinfo.vdf file. If app ids are given, only
+// those apps are decoded and returned.
+func ReadAppInfo(file string, appIds ...uint32) ([]*AppInfo, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	apps, err := readAppInfo(f, appIds)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %v: %w", file, err)
+	}
+	return apps, nil
+}
+
+// readAppInfo will read the apps from the given appinfo.vdf data
+func readAppInfo(r io.ReadSeeker, appIds []uint32) ([]*AppInfo, error) {
+	var header struct {
+		Magic    uint32
+		Universe uint32
+	}
+	err := binary.Read(r, binary.LittleEndian, &header)
+	if err != nil {
+		return nil, err
+	}
+	if header.Magic < AppInfoVersion27 || header.Magic > AppInfoVersion29 {
+		return nil, fmt.Errorf("unsupported appinfo version: 0x%08x", header.Magic)
+	}
+
+	// Newer versions store keys in a string table at the end of the file
+	var stringTable []string
+	if header.Magic >= AppInfoVersion29 {
+		var offset int64
+		err := binary.Read(r, binary.LittleEndian, &offset)
+		if err != nil {
+			return nil, err
+		}
+		start, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		_, err = r.Seek(offset, io.SeekStart)
+		if err != nil {
+			return nil, err
+		}
+		stringTable, err = vdfbinary.ReadStringTable(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string table: %w", err)
+		}
+		_, err = r.Seek(start, io.SeekStart)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	wanted := map[uint32]bool{}
+	for _, appId := range appIds {
+		wanted[appId] = true
+	}
+
+	apps := []*AppInfo{}
+	for {
+		var entry struct {
+			AppID uint32
+			Size  uint32
+		}
+		err := binary.Read(r, binary.LittleEndian, &entry.AppID)
+		if err != nil {
+			return nil, err
+		}
+		if entry.AppID == 0 {
+			break
+		}
+		err = binary.Read(r, binary.LittleEndian, &entry.Size)
+		if err != nil {
+			return nil, err
+		}
+
+		// Skip apps we were not asked for without decoding them
+		if len(wanted) > 0 && !wanted[entry.AppID] {
+			_, err := r.Seek(int64(entry.Size), io.SeekCurrent)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		data := make([]byte, entry.Size)
+		_, err = io.ReadFull(r, data)
+		if err != nil {
+			return nil, err
+		}
+		app, err := decodeAppInfo(entry.AppID, data, header.Magic, stringTable)
+		if err != nil {
+			return nil, fmt.Errorf("app %v: %w", entry.AppID, err)
+		}
+		apps = append(apps, app)
+		if len(wanted) > 0 && len(apps) == len(wanted) {
+			break
+		}
+	}
+
+	return apps, nil
+}
+
+// decodeAppInfo will decode a single appinfo.vdf entry after its size
+func decodeAppInfo(appId uint32, data []byte, version uint32, stringTable []string) (*AppInfo, error) {
+	r := bytes.NewReader(data)
+	var header struct {
+		InfoState    uint32
+		LastUpdated  uint32
+		PICSToken    uint64
+		TextSHA1     [sha1.Size]byte
+		ChangeNumber uint32
+	}
+	err := binary.Read(r, binary.LittleEndian, &header)
+	if err != nil {
+		return nil, err
+	}
+	if version >= AppInfoVersion28 {
+		var binarySHA1 [sha1.Size]byte
+		err := binary.Read(r, binary.LittleEndian, &binarySHA1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	decoder := vdfbinary.NewDecoder(r)
+	decoder.StringTable = stringTable
+	root, err := decoder.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	app := &AppInfo{
+		AppID:         appId,
+		LastUpdated:   time.Unix(int64(header.LastUpdated), 0),
+		ChangeNumber:  header.ChangeNumber,
+		Assets:        map[string]string{},
+		StoreTags:     []int{},
+		LaunchConfigs: []*LaunchConfig{},
+		Data:          root.Child("appinfo"),
+	}
+	if app.Data == nil {
+		app.Data = root
+	}
+	app.Name, _ = app.Data.GetString("common", "name")
+	app.Type, _ = app.Data.GetString("common", "type")
+
+	// Artwork
+	for _, key := range commonAssets {
+		if value, ok := app.Data.GetString("common", key); ok && value != "" {
+			app.Assets[key] = value
+		}
+	}
+	if header, ok := app.Data.GetString("common", "header_image", "english"); ok {
+		app.Assets["header_image"] = header
+	}
+	if assets := app.Data.Lookup("common", "library_assets_full"); assets != nil {
+		for _, asset := range assets.Children {
+			if value, ok := asset.GetString("image", "english"); ok {
+				app.Assets[asset.Key] = value
+			}
+			if value, ok := asset.GetString("image2x", "english"); ok {
+				app.Assets[asset.Key+"_2x"] = value
+			}
+		}
+	}
+
+	// Store tags
+	if tags := app.Data.Lookup("common", "store_tags"); tags != nil {
+		for _, tag := range tags.Children {
+			id, err := strconv.Atoi(tag.Value)
+			if err == nil {
+				app.StoreTags = append(app.StoreTags, id)
+			}
+		}
+	}
+
+	// Launch configs
+	if launch := app.Data.Lookup("config", "launch"); launch != nil {
+		for _, entry := range launch.Children {
+			config := &LaunchConfig{ID: entry.Key}
+			config.Executable, _ = entry.GetString("executable")
+			config.Arguments, _ = entry.GetString("arguments")
+			config.WorkingDir, _ = entry.GetString("workingdir")
+			config.Type, _ = entry.GetString("type")
+			config.Description, _ = entry.GetString("description")
+			config.OSList, _ = entry.GetString("config", "oslist")
+			config.OSArch, _ = entry.GetString("config", "osarch")
+			config.BetaKey, _ = entry.GetString("config", "betakey")
+			app.LaunchConfigs = append(app.LaunchConfigs, config)
+		}
+	}
+
+	return app, nil
+}
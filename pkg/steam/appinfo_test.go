@@ -0,0 +1,179 @@
+package steam
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	vdfbinary "github.com/shadowblip/steam-shortcut-manager/pkg/vdf/binary"
+)
+
+// testApp is an app to write into an appinfo.vdf fixture
+type testApp struct {
+	id   uint32
+	name string
+}
+
+// appInfoFixture will build an appinfo.vdf file of the given version with
+// the given apps. Keys are written into a string table for version 29.
+func appInfoFixture(version uint32, apps ...testApp) []byte {
+	table := []string{}
+	key := func(buf *bytes.Buffer, kind byte, name string) {
+		buf.WriteByte(kind)
+		if version < AppInfoVersion29 {
+			buf.WriteString(name + "\x00")
+			return
+		}
+		for i, s := range table {
+			if s == name {
+				binary.Write(buf, binary.LittleEndian, uint32(i))
+				return
+			}
+		}
+		binary.Write(buf, binary.LittleEndian, uint32(len(table)))
+		table = append(table, name)
+	}
+	str := func(buf *bytes.Buffer, name, value string) {
+		key(buf, vdfbinary.TypeString, name)
+		buf.WriteString(value + "\x00")
+	}
+
+	var entries bytes.Buffer
+	for _, app := range apps {
+		var data bytes.Buffer
+		binary.Write(&data, binary.LittleEndian, uint32(2))          // info state
+		binary.Write(&data, binary.LittleEndian, uint32(1700000000)) // last updated
+		binary.Write(&data, binary.LittleEndian, uint64(0))          // PICS token
+		data.Write(make([]byte, sha1.Size))                          // text SHA1
+		binary.Write(&data, binary.LittleEndian, app.id*10)          // change number
+		if version >= AppInfoVersion28 {
+			data.Write(make([]byte, sha1.Size)) // binary SHA1
+		}
+		key(&data, vdfbinary.TypeObject, "appinfo")
+		key(&data, vdfbinary.TypeObject, "common")
+		str(&data, "name", app.name)
+		str(&data, "type", "Game")
+		str(&data, "icon", "abc123")
+		key(&data, vdfbinary.TypeObject, "store_tags")
+		str(&data, "0", "492")
+		str(&data, "1", "19")
+		data.WriteByte(vdfbinary.TypeEnd)
+		data.WriteByte(vdfbinary.TypeEnd)
+		key(&data, vdfbinary.TypeObject, "config")
+		key(&data, vdfbinary.TypeObject, "launch")
+		key(&data, vdfbinary.TypeObject, "0")
+		str(&data, "executable", "game.sh")
+		key(&data, vdfbinary.TypeObject, "config")
+		str(&data, "oslist", "linux")
+		data.WriteByte(vdfbinary.TypeEnd)
+		data.WriteByte(vdfbinary.TypeEnd)
+		data.WriteByte(vdfbinary.TypeEnd)
+		data.WriteByte(vdfbinary.TypeEnd)
+		data.WriteByte(vdfbinary.TypeEnd)
+		data.WriteByte(vdfbinary.TypeEnd)
+
+		binary.Write(&entries, binary.LittleEndian, app.id)
+		binary.Write(&entries, binary.LittleEndian, uint32(data.Len()))
+		entries.Write(data.Bytes())
+	}
+	binary.Write(&entries, binary.LittleEndian, uint32(0))
+
+	var file bytes.Buffer
+	binary.Write(&file, binary.LittleEndian, version)
+	binary.Write(&file, binary.LittleEndian, uint32(1)) // universe
+	if version >= AppInfoVersion29 {
+		offset := int64(file.Len() + 8 + entries.Len())
+		binary.Write(&file, binary.LittleEndian, offset)
+	}
+	file.Write(entries.Bytes())
+	if version >= AppInfoVersion29 {
+		binary.Write(&file, binary.LittleEndian, uint32(len(table)))
+		for _, s := range table {
+			file.WriteString(s + "\x00")
+		}
+	}
+	return file.Bytes()
+}
+
+func TestReadAppInfo(t *testing.T) {
+	apps := []testApp{{id: 620, name: "Portal 2"}, {id: 440, name: "Team Fortress 2"}}
+	for _, version := range []uint32{AppInfoVersion27, AppInfoVersion28, AppInfoVersion29} {
+		t.Run(fmt.Sprintf("v%x", version&0xff), func(t *testing.T) {
+			data := appInfoFixture(version, apps...)
+			result, err := readAppInfo(bytes.NewReader(data), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result) != 2 {
+				t.Fatalf("expected 2 apps, got %v", len(result))
+			}
+			app := result[0]
+			if app.AppID != 620 || app.Name != "Portal 2" || app.Type != "Game" || app.ChangeNumber != 6200 {
+				t.Errorf("unexpected app: %+v", app)
+			}
+			if !app.LastUpdated.Equal(time.Unix(1700000000, 0)) {
+				t.Errorf("unexpected last updated time: %v", app.LastUpdated)
+			}
+			if app.Assets["icon"] != "abc123" {
+				t.Errorf("unexpected assets: %v", app.Assets)
+			}
+			if !reflect.DeepEqual(app.StoreTags, []int{492, 19}) {
+				t.Errorf("unexpected store tags: %v", app.StoreTags)
+			}
+			if len(app.LaunchConfigs) != 1 || app.LaunchConfigs[0].Executable != "game.sh" || app.LaunchConfigs[0].OSList != "linux" {
+				t.Errorf("unexpected launch configs: %+v", app.LaunchConfigs)
+			}
+			if result[1].Name != "Team Fortress 2" {
+				t.Errorf("unexpected second app: %+v", result[1])
+			}
+
+			// Only the requested apps are returned
+			result, err = readAppInfo(bytes.NewReader(data), []uint32{440})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result) != 1 || result[0].AppID != 440 || result[0].Name != "Team Fortress 2" {
+				t.Errorf("unexpected apps: %+v", result)
+			}
+		})
+	}
+}
+
+func TestReadAppInfoErrors(t *testing.T) {
+	valid := appInfoFixture(AppInfoVersion29, testApp{id: 620, name: "Portal 2"})
+
+	// A string table with fewer keys than the apps use
+	var shortTable bytes.Buffer
+	offset := int64(binary.LittleEndian.Uint64(valid[8:16]))
+	shortTable.Write(valid[:offset])
+	binary.Write(&shortTable, binary.LittleEndian, uint32(1))
+	shortTable.WriteString("appinfo\x00")
+
+	// A string table offset past the end of the file
+	badOffset := append([]byte{}, valid...)
+	binary.LittleEndian.PutUint64(badOffset[8:16], uint64(len(valid)+100))
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"unsupported version", appInfoFixture(0x07564426), "unsupported appinfo version"},
+		{"string index out of range", shortTable.Bytes(), "out of range"},
+		{"string table offset out of range", badOffset, "invalid string table"},
+		{"truncated entry", valid[:40], "EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readAppInfo(bytes.NewReader(tt.data), nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
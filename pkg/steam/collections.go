@@ -0,0 +1,464 @@
+package steam
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/fileutil"
+)
+
+const (
+	// collectionKeyPrefix is the prefix of cloud storage keys that hold
+	// collections
+	collectionKeyPrefix = "user-collections."
+	// userCollectionIDPrefix is the prefix of collections created by the user
+	userCollectionIDPrefix = "uc-"
+	// collectionIDChars are the characters Steam uses for collection ids
+	collectionIDChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// ErrCollectionNotFound is returned when a collection does not exist
+var ErrCollectionNotFound = errors.New("collection not found")
+
+// Collection is a Steam library collection. Steam stores collections in the
+// user's cloud storage namespace file, e.g.:
+//
+//	[["user-collections.uc-Qo8HMuDD3nYx", {
+//	  "key": "user-collections.uc-Qo8HMuDD3nYx",
+//	  "timestamp": 1675000000,
+//	  "value": "{\"id\":\"uc-Qo8HMuDD3nYx\",\"name\":\"Emulators\",\"added\":[2393915710],\"removed\":[]}",
+//	  "version": "12",
+//	  "conflictResolutionMethod": "custom",
+//	  "strMethodId": "union-collections"
+//	}]]
+type Collection struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Added   []int64 `json:"added"`
+	Removed []int64 `json:"removed"`
+
+	// Dynamic collections have a filter spec instead of a list of apps
+	Dynamic bool `json:"dynamic"`
+
+	// value holds every field of the collection so fields we do not know
+	// about are written back unchanged.
+	value map[string]interface{}
+}
+
+// IsUserCollection will return whether or not the collection was created by
+// the user, rather than a built-in collection such as favorites.
+func (c *Collection) IsUserCollection() bool {
+	return strings.HasPrefix(c.ID, userCollectionIDPrefix)
+}
+
+// HasApp will return whether or not the given app id is in the collection
+func (c *Collection) HasApp(appId int64) bool {
+	return containsAppID(c.Added, appId)
+}
+
+// Collections is a user's cloud storage namespace file that holds their
+// Steam library collections. Steam overwrites this file from the cloud while
+// it is running, so changes should be made while Steam is closed.
+type Collections struct {
+	path    string
+	entries []*cloudStorageEntry
+}
+
+// cloudStorageEntry is a single key/value pair in the cloud storage file
+type cloudStorageEntry struct {
+	key    string
+	fields map[string]interface{}
+}
+
+// GetCollectionsPath will return the path to the cloud storage file that
+// holds the given user's collections.
+func GetCollectionsPath(user string) (string, error) {
+	userDir, err := GetUserDir()
+	if err != nil {
+		return "", err
+	}
+	return path.Join(userDir, user, "config", "cloudstorage", "cloud-storage-namespace-1.json"), nil
+}
+
+// LoadCollections will load the collections for the given user. If Steam has
+// not created the file yet, an empty set of collections is returned.
+func LoadCollections(user string) (*Collections, error) {
+	collectionsPath, err := GetCollectionsPath(user)
+	if err != nil {
+		return nil, err
+	}
+	return loadCollectionsFile(collectionsPath)
+}
+
+// loadCollectionsFile will load the collections from the given cloud storage
+// file.
+func loadCollectionsFile(collectionsPath string) (*Collections, error) {
+	collections := &Collections{path: collectionsPath, entries: []*cloudStorageEntry{}}
+	data, err := os.ReadFile(collectionsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return collections, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs [][]json.RawMessage
+	err = json.Unmarshal(data, &pairs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %v: %v", collectionsPath, err)
+	}
+	for _, pair := range pairs {
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("unable to parse %v: invalid entry", collectionsPath)
+		}
+		entry := &cloudStorageEntry{}
+		err := json.Unmarshal(pair[0], &entry.key)
+		if err == nil {
+			err = json.Unmarshal(pair[1], &entry.fields)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %v: %v", collectionsPath, err)
+		}
+		collections.entries = append(collections.entries, entry)
+	}
+
+	return collections, nil
+}
+
+// List will return all collections that have not been deleted, sorted by
+// name.
+func (c *Collections) List() []*Collection {
+	collections := []*Collection{}
+	for _, entry := range c.entries {
+		collection, err := entry.collection()
+		if err != nil || collection == nil {
+			continue
+		}
+		collections = append(collections, collection)
+	}
+	sort.Slice(collections, func(i, j int) bool {
+		return strings.ToLower(collections[i].Name) < strings.ToLower(collections[j].Name)
+	})
+	return collections
+}
+
+// Get will return the collection with the given id or name. Returns
+// ErrCollectionNotFound if it does not exist.
+func (c *Collections) Get(nameOrID string) (*Collection, error) {
+	for _, collection := range c.List() {
+		if collection.ID == nameOrID || collection.Name == nameOrID {
+			return collection, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %v", ErrCollectionNotFound, nameOrID)
+}
+
+// Create will create a new, empty collection with the given name
+func (c *Collections) Create(name string) (*Collection, error) {
+	if name == "" {
+		return nil, fmt.Errorf("collection name cannot be empty")
+	}
+	if _, err := c.Get(name); err == nil {
+		return nil, fmt.Errorf("collection already exists: %v", name)
+	}
+	id, err := newCollectionID()
+	if err != nil {
+		return nil, err
+	}
+	collection := &Collection{
+		ID:      id,
+		Name:    name,
+		Added:   []int64{},
+		Removed: []int64{},
+		value:   map[string]interface{}{},
+	}
+	err = c.put(collection)
+	if err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// GetOrCreate will return the collection with the given name, creating it if
+// it does not exist.
+func (c *Collections) GetOrCreate(name string) (*Collection, error) {
+	collection, err := c.Get(name)
+	if errors.Is(err, ErrCollectionNotFound) {
+		return c.Create(name)
+	}
+	return collection, err
+}
+
+// Rename will rename the given collection
+func (c *Collections) Rename(nameOrID, newName string) error {
+	if newName == "" {
+		return fmt.Errorf("collection name cannot be empty")
+	}
+	collection, err := c.Get(nameOrID)
+	if err != nil {
+		return err
+	}
+	if !collection.IsUserCollection() {
+		return fmt.Errorf("built-in collection cannot be renamed: %v", collection.ID)
+	}
+	if existing, err := c.Get(newName); err == nil && existing.ID != collection.ID {
+		return fmt.Errorf("collection already exists: %v", newName)
+	}
+	collection.Name = newName
+	return c.put(collection)
+}
+
+// Delete will mark the given collection as deleted. Steam keeps deleted
+// collections as tombstones so the deletion is synced to other devices.
+func (c *Collections) Delete(nameOrID string) error {
+	collection, err := c.Get(nameOrID)
+	if err != nil {
+		return err
+	}
+	if !collection.IsUserCollection() {
+		return fmt.Errorf("built-in collection cannot be deleted: %v", collection.ID)
+	}
+	entry := c.entry(collectionKeyPrefix + collection.ID)
+	entry.fields = map[string]interface{}{
+		"key":        entry.key,
+		"timestamp":  time.Now().Unix(),
+		"is_deleted": true,
+		"version":    c.nextVersion(),
+	}
+	return nil
+}
+
+// AddApps will add the given app ids to the given collection. Returns the
+// number of apps that were not already in the collection.
+func (c *Collections) AddApps(nameOrID string, appIds ...int64) (int, error) {
+	collection, err := c.Get(nameOrID)
+	if err != nil {
+		return 0, err
+	}
+	if collection.Dynamic {
+		return 0, fmt.Errorf("apps cannot be added to dynamic collection: %v", collection.Name)
+	}
+	added := 0
+	for _, appId := range appIds {
+		collection.Removed = removeAppID(collection.Removed, appId)
+		if collection.HasApp(appId) {
+			continue
+		}
+		collection.Added = append(collection.Added, appId)
+		added++
+	}
+	return added, c.put(collection)
+}
+
+// RemoveApps will remove the given app ids from the given collection.
+// Returns the number of apps that were removed.
+func (c *Collections) RemoveApps(nameOrID string, appIds ...int64) (int, error) {
+	collection, err := c.Get(nameOrID)
+	if err != nil {
+		return 0, err
+	}
+	if collection.Dynamic {
+		return 0, fmt.Errorf("apps cannot be removed from dynamic collection: %v", collection.Name)
+	}
+	removed := 0
+	for _, appId := range appIds {
+		if !collection.HasApp(appId) {
+			continue
+		}
+		collection.Added = removeAppID(collection.Added, appId)
+		if !containsAppID(collection.Removed, appId) {
+			collection.Removed = append(collection.Removed, appId)
+		}
+		removed++
+	}
+	return removed, c.put(collection)
+}
+
+// ReplaceApp will replace the given old app id with the new app id in every
+// collection that contains it, e.g. when a shortcut gets a new app id.
+// Returns the number of collections that were changed.
+func (c *Collections) ReplaceApp(oldAppId, newAppId int64) (int, error) {
+	changed := 0
+	for _, collection := range c.List() {
+		if collection.Dynamic || !collection.HasApp(oldAppId) {
+			continue
+		}
+		collection.Added = removeAppID(collection.Added, oldAppId)
+		if !containsAppID(collection.Removed, oldAppId) {
+			collection.Removed = append(collection.Removed, oldAppId)
+		}
+		collection.Removed = removeAppID(collection.Removed, newAppId)
+		if !collection.HasApp(newAppId) {
+			collection.Added = append(collection.Added, newAppId)
+		}
+		err := c.put(collection)
+		if err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// Save will write the collections back to the cloud storage file
+func (c *Collections) Save() error {
+	pairs := make([][]interface{}, 0, len(c.entries))
+	for _, entry := range c.entries {
+		pairs = append(pairs, []interface{}{entry.key, entry.fields})
+	}
+	data, err := json.Marshal(pairs)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(path.Dir(c.path), 0755)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so Steam never reads a partial file
+	return fileutil.WriteFile(c.path, data, 0644)
+}
+
+// put will write the given collection into its cloud storage entry, creating
+// the entry if needed.
+func (c *Collections) put(collection *Collection) error {
+	if collection.value == nil {
+		collection.value = map[string]interface{}{}
+	}
+	collection.value["id"] = collection.ID
+	// Built-in collections such as favorites are stored without a name
+	if _, hasName := collection.value["name"]; hasName || collection.IsUserCollection() {
+		collection.value["name"] = collection.Name
+	}
+	if !collection.Dynamic {
+		collection.value["added"] = collection.Added
+		collection.value["removed"] = collection.Removed
+	}
+	value, err := json.Marshal(collection.value)
+	if err != nil {
+		return err
+	}
+
+	key := collectionKeyPrefix + collection.ID
+	entry := c.entry(key)
+	if entry == nil {
+		entry = &cloudStorageEntry{key: key, fields: map[string]interface{}{}}
+		c.entries = append(c.entries, entry)
+	}
+	delete(entry.fields, "is_deleted")
+	entry.fields["key"] = key
+	entry.fields["timestamp"] = time.Now().Unix()
+	entry.fields["value"] = string(value)
+	entry.fields["version"] = c.nextVersion()
+	entry.fields["conflictResolutionMethod"] = "custom"
+	entry.fields["strMethodId"] = "union-collections"
+
+	return nil
+}
+
+// entry will return the cloud storage entry with the given key or nil
+func (c *Collections) entry(key string) *cloudStorageEntry {
+	for _, entry := range c.entries {
+		if entry.key == key {
+			return entry
+		}
+	}
+	return nil
+}
+
+// nextVersion will return a version number higher than any in the file so
+// Steam treats our changes as the newest.
+func (c *Collections) nextVersion() string {
+	var highest int64
+	for _, entry := range c.entries {
+		version, _ := entry.fields["version"].(string)
+		num, err := strconv.ParseInt(version, 10, 64)
+		if err == nil && num > highest {
+			highest = num
+		}
+	}
+	return strconv.FormatInt(highest+1, 10)
+}
+
+// collection will decode the entry as a collection. Returns nil if the entry
+// is not a collection or has been deleted.
+func (e *cloudStorageEntry) collection() (*Collection, error) {
+	if !strings.HasPrefix(e.key, collectionKeyPrefix) {
+		return nil, nil
+	}
+	if deleted, _ := e.fields["is_deleted"].(bool); deleted {
+		return nil, nil
+	}
+	raw, _ := e.fields["value"].(string)
+	if raw == "" {
+		return nil, nil
+	}
+
+	collection := &Collection{}
+	err := json.Unmarshal([]byte(raw), &collection.value)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(raw), collection)
+	if err != nil {
+		return nil, err
+	}
+	if collection.ID == "" {
+		collection.ID = strings.TrimPrefix(e.key, collectionKeyPrefix)
+	}
+	if collection.Name == "" {
+		collection.Name = collection.ID
+	}
+	_, collection.Dynamic = collection.value["filterSpec"]
+	if collection.Added == nil {
+		collection.Added = []int64{}
+	}
+	if collection.Removed == nil {
+		collection.Removed = []int64{}
+	}
+
+	return collection, nil
+}
+
+// newCollectionID will generate a random id for a user collection
+func newCollectionID() (string, error) {
+	var sb strings.Builder
+	sb.WriteString(userCollectionIDPrefix)
+	max := big.NewInt(int64(len(collectionIDChars)))
+	for i := 0; i < 12; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(collectionIDChars[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+func containsAppID(appIds []int64, appId int64) bool {
+	for _, id := range appIds {
+		if id == appId {
+			return true
+		}
+	}
+	return false
+}
+
+func removeAppID(appIds []int64, appId int64) []int64 {
+	result := []int64{}
+	for _, id := range appIds {
+		if id != appId {
+			result = append(result, id)
+		}
+	}
+	return result
+}
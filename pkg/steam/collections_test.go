@@ -0,0 +1,241 @@
+package steam
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// loadTestCollections will copy the collections fixture into a temporary
+// directory and load it.
+func loadTestCollections(t *testing.T) *Collections {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "cloud-storage-namespace-1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "cloud-storage-namespace-1.json")
+	err = os.WriteFile(file, data, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	collections, err := loadCollectionsFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return collections
+}
+
+// reloadCollections will save the given collections and load them again
+func reloadCollections(t *testing.T, collections *Collections) *Collections {
+	t.Helper()
+	err := collections.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := loadCollectionsFile(collections.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reloaded
+}
+
+func TestLoadCollections(t *testing.T) {
+	collections := loadTestCollections(t)
+
+	// Deleted collections and other cloud storage keys are not listed, and
+	// built-in collections are named after their id
+	names := []string{}
+	for _, collection := range collections.List() {
+		names = append(names, collection.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"Emulators", "favorite", "Unplayed"}) {
+		t.Fatalf("unexpected collections: %v", names)
+	}
+
+	emulators, err := collections.Get("uc-Qo8HMuDD3nYx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !emulators.IsUserCollection() || emulators.Dynamic || !emulators.HasApp(3810698013) {
+		t.Errorf("unexpected collection: %+v", emulators)
+	}
+	unplayed, _ := collections.Get("Unplayed")
+	if !unplayed.Dynamic {
+		t.Error("expected a collection with a filter spec to be dynamic")
+	}
+	_, err = collections.Get("uc-D3l3t3dC0llX")
+	if !errors.Is(err, ErrCollectionNotFound) {
+		t.Errorf("expected deleted collection not to be found, got %v", err)
+	}
+}
+
+func TestCollectionsCreate(t *testing.T) {
+	collections := loadTestCollections(t)
+	created, err := collections.Create("RPG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created.IsUserCollection() || len(created.ID) != len(userCollectionIDPrefix)+12 {
+		t.Errorf("unexpected collection id: %v", created.ID)
+	}
+	if version := collections.entry(collectionKeyPrefix + created.ID).fields["version"]; version != "31" {
+		t.Errorf("unexpected version: %v", version)
+	}
+
+	for _, name := range []string{"", "RPG", "Emulators"} {
+		if _, err := collections.Create(name); err == nil {
+			t.Errorf("expected an error creating %q", name)
+		}
+	}
+
+	// The new collection is written along with every existing entry
+	reloaded := reloadCollections(t, collections)
+	collection, err := reloaded.Get("RPG")
+	if err != nil || collection.ID != created.ID || len(collection.Added) != 0 {
+		t.Errorf("unexpected collection: %+v, %v", collection, err)
+	}
+	if len(reloaded.entries) != len(collections.entries) || reloaded.entry("showcases.recently_played") == nil {
+		t.Errorf("expected all entries to be kept, got %v", len(reloaded.entries))
+	}
+}
+
+func TestCollectionsRename(t *testing.T) {
+	collections := loadTestCollections(t)
+	err := collections.Rename("Emulators", "Retro")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unknown fields of the collection are kept
+	reloaded := reloadCollections(t, collections)
+	retro, err := reloaded.Get("Retro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retro.ID != "uc-Qo8HMuDD3nYx" || retro.value["sortOrder"] != float64(2) {
+		t.Errorf("unexpected collection: %+v", retro)
+	}
+	if _, err := reloaded.Get("Emulators"); err == nil {
+		t.Error("expected the old name to be gone")
+	}
+
+	err = reloaded.Rename("Retro", "Retro")
+	if err != nil {
+		t.Errorf("expected renaming to the same name to work: %v", err)
+	}
+	tests := map[string][2]string{
+		"empty name":         {"Retro", ""},
+		"existing name":      {"Retro", "Unplayed"},
+		"built-in":           {"favorite", "Favorites"},
+		"missing collection": {"Missing", "Other"},
+	}
+	for name, tt := range tests {
+		if err := reloaded.Rename(tt[0], tt[1]); err == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+}
+
+func TestCollectionsDelete(t *testing.T) {
+	collections := loadTestCollections(t)
+	err := collections.Delete("Emulators")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Deleted collections are kept as tombstones with a newer version
+	reloaded := reloadCollections(t, collections)
+	if _, err := reloaded.Get("Emulators"); !errors.Is(err, ErrCollectionNotFound) {
+		t.Errorf("expected the collection to be deleted, got %v", err)
+	}
+	entry := reloaded.entry(collectionKeyPrefix + "uc-Qo8HMuDD3nYx")
+	if entry == nil || entry.fields["is_deleted"] != true || entry.fields["version"] != "31" {
+		t.Errorf("unexpected tombstone: %+v", entry)
+	}
+	if _, ok := entry.fields["value"]; ok {
+		t.Error("expected the tombstone to have no value")
+	}
+
+	for _, name := range []string{"favorite", "Emulators", "Missing"} {
+		if err := reloaded.Delete(name); err == nil {
+			t.Errorf("expected an error deleting %v", name)
+		}
+	}
+
+	// Creating a collection with the same name again works
+	if _, err := reloaded.Create("Emulators"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCollectionsReplaceApp(t *testing.T) {
+	collections := loadTestCollections(t)
+	changed, err := collections.ReplaceApp(3810698013, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed != 1 {
+		t.Fatalf("expected 1 changed collection, got %v", changed)
+	}
+
+	// The old app id is removed and the new one is no longer marked removed
+	emulators, _ := reloadCollections(t, collections).Get("Emulators")
+	if !reflect.DeepEqual(emulators.Added, []int64{2393915710, 42}) {
+		t.Errorf("unexpected added apps: %v", emulators.Added)
+	}
+	if !reflect.DeepEqual(emulators.Removed, []int64{3810698013}) {
+		t.Errorf("unexpected removed apps: %v", emulators.Removed)
+	}
+
+	changed, err = collections.ReplaceApp(1, 2)
+	if err != nil || changed != 0 {
+		t.Errorf("expected nothing to change, got %v, %v", changed, err)
+	}
+}
+
+func TestCollectionsNextVersion(t *testing.T) {
+	collections := &Collections{entries: []*cloudStorageEntry{}}
+	if version := collections.nextVersion(); version != "1" {
+		t.Errorf("unexpected version for empty collections: %v", version)
+	}
+
+	// Versions that are not numbers are ignored
+	collections.entries = []*cloudStorageEntry{
+		{key: "a", fields: map[string]interface{}{"version": "9"}},
+		{key: "b", fields: map[string]interface{}{"version": "abc"}},
+		{key: "c", fields: map[string]interface{}{"version": float64(100)}},
+		{key: "d", fields: map[string]interface{}{}},
+	}
+	if version := collections.nextVersion(); version != "10" {
+		t.Errorf("unexpected version: %v", version)
+	}
+	if version := loadTestCollections(t).nextVersion(); version != "31" {
+		t.Errorf("unexpected version: %v", version)
+	}
+}
+
+func TestCollectionsSave(t *testing.T) {
+	collections := loadTestCollections(t)
+	collections.path = filepath.Join(t.TempDir(), "cloudstorage", "cloud-storage-namespace-1.json")
+	err := collections.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// No temporary files are left behind
+	files, err := os.ReadDir(filepath.Dir(collections.path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || strings.HasSuffix(files[0].Name(), ".tmp") {
+		t.Errorf("unexpected files: %v", files)
+	}
+	info, err := os.Stat(collections.path)
+	if err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("unexpected file: %v, %v", info, err)
+	}
+}
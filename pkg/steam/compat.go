@@ -0,0 +1,252 @@
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/logger"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/vdf/text"
+)
+
+// compatToolPriority is the priority Steam uses for per-app compat tool
+// mappings set by the user.
+const compatToolPriority = "250"
+
+// compatToolMappingPath is the path of keys to the CompatToolMapping section
+// in config.vdf
+var compatToolMappingPath = []string{"InstallConfigStore", "Software", "Valve", "Steam", "CompatToolMapping"}
+
+// protonVersion matches versioned Proton app names such as "Proton 8.0"
+var protonVersion = regexp.MustCompile(`^Proton (\d+)\.(\d+)$`)
+
+// CompatTool is a compatibility tool (e.g. Proton) that Steam can use to run
+// a shortcut.
+type CompatTool struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Path        string `json:"path"`
+	Source      string `json:"source"`
+}
+
+// GetConfigPath will return the path to Steam's global config.vdf file
+func GetConfigPath() (string, error) {
+	steamDir, err := GetBaseDir()
+	if err != nil {
+		return "", err
+	}
+	return path.Join(steamDir, "config", "config.vdf"), nil
+}
+
+// GetCompatToolDirs will return the directories that custom compatibility
+// tools are installed in.
+func GetCompatToolDirs() ([]string, error) {
+	steamDir, err := GetBaseDir()
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		path.Join(steamDir, "compatibilitytools.d"),
+		"/usr/share/steam/compatibilitytools.d",
+		"/usr/local/share/steam/compatibilitytools.d",
+	}, nil
+}
+
+// GetCompatTools will return all compatibility tools found in the
+// compatibilitytools.d directories and the Steam library, sorted by name.
+func GetCompatTools() ([]*CompatTool, error) {
+	dirs, err := GetCompatToolDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	tools := []*CompatTool{}
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		files, err := filepath.Glob(path.Join(dir, "*", "compatibilitytool.vdf"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			found, err := readCompatToolManifest(file)
+			if err != nil {
+				logger.DebugPrintln("Skipping compat tool manifest:", err)
+				continue
+			}
+			for _, tool := range found {
+				if seen[tool.Name] {
+					continue
+				}
+				seen[tool.Name] = true
+				tools = append(tools, tool)
+			}
+		}
+	}
+
+	// Proton versions installed through Steam are regular apps. The library
+	// is only an extra source of tools, so it is skipped if it can't be read.
+	apps, err := GetInstalledGames()
+	if err != nil {
+		logger.DebugPrintln("Unable to read Steam library for compat tools:", err)
+	}
+	for _, app := range apps {
+		name := protonToolName(app.Name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		tools = append(tools, &CompatTool{
+			Name:        name,
+			DisplayName: app.Name,
+			Path:        app.Path,
+			Source:      "steam",
+		})
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+
+	return tools, nil
+}
+
+// LookupCompatTool will return the compatibility tool with the given internal
+// or display name.
+func LookupCompatTool(name string) (*CompatTool, error) {
+	tools, err := GetCompatTools()
+	if err != nil {
+		return nil, err
+	}
+	for _, tool := range tools {
+		if tool.Name == name || tool.DisplayName == name {
+			return tool, nil
+		}
+	}
+	return nil, fmt.Errorf("no compatibility tool found with name: %v", name)
+}
+
+// GetCompatToolMapping will return the compatibility tool name mapped to each
+// app id in config.vdf.
+func GetCompatToolMapping() (map[string]string, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	root, err := text.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := map[string]string{}
+	section := root.Lookup(compatToolMappingPath...)
+	if section == nil {
+		return mapping, nil
+	}
+	for _, entry := range section.Children {
+		if name := entry.Child("name"); name != nil && name.Value != "" {
+			mapping[entry.Key] = name.Value
+		}
+	}
+	return mapping, nil
+}
+
+// GetCompatTool will return the compatibility tool mapped to the given app
+// id, or an empty string if there is none.
+func GetCompatTool(appId string) (string, error) {
+	mapping, err := GetCompatToolMapping()
+	if err != nil {
+		return "", err
+	}
+	return mapping[appId], nil
+}
+
+// SetCompatTool will map the given app id to the given compatibility tool in
+// config.vdf. An empty tool name will remove the mapping. Steam overwrites
+// this file when it exits, so it should not be running.
+func SetCompatTool(appId, name string) error {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return err
+	}
+	root, err := text.ReadFile(configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		root = text.NewDocument()
+		err = os.MkdirAll(path.Dir(configPath), 0755)
+	}
+	if err != nil {
+		return err
+	}
+
+	section := root.EnsureObject(compatToolMappingPath...)
+	if name == "" {
+		if !section.Remove(appId) {
+			return nil
+		}
+		return text.WriteFile(configPath, root)
+	}
+	entry := section.EnsureObject(appId)
+	entry.Set("name", name)
+	if entry.Child("config") == nil {
+		entry.Set("config", "")
+	}
+	entry.Set("priority", compatToolPriority)
+
+	return text.WriteFile(configPath, root)
+}
+
+// readCompatToolManifest will read the tools defined in the given
+// compatibilitytool.vdf file.
+func readCompatToolManifest(file string) ([]*CompatTool, error) {
+	root, err := text.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	section := root.Lookup("compatibilitytools", "compat_tools")
+	if section == nil {
+		return nil, fmt.Errorf("no compat_tools found in %v", file)
+	}
+
+	tools := []*CompatTool{}
+	for _, entry := range section.Children {
+		tool := &CompatTool{
+			Name:        entry.Key,
+			DisplayName: entry.Key,
+			Path:        path.Dir(file),
+			Source:      "custom",
+		}
+		if displayName := entry.Child("display_name"); displayName != nil && displayName.Value != "" {
+			tool.DisplayName = displayName.Value
+		}
+		if installPath := entry.Child("install_path"); installPath != nil && installPath.Value != "" {
+			if filepath.IsAbs(installPath.Value) {
+				tool.Path = installPath.Value
+			} else {
+				tool.Path = path.Join(path.Dir(file), installPath.Value)
+			}
+		}
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// protonToolName will return the internal compat tool name Steam uses for the
+// given Proton app name (e.g. "Proton 8.0" is "proton_8"), or an empty string
+// if the app is not Proton.
+func protonToolName(appName string) string {
+	if m := protonVersion.FindStringSubmatch(appName); m != nil {
+		if m[2] == "0" {
+			return "proton_" + m[1]
+		}
+		return "proton_" + m[1] + m[2]
+	}
+	if !strings.HasPrefix(appName, "Proton ") {
+		return ""
+	}
+	suffix := strings.ToLower(strings.TrimPrefix(appName, "Proton "))
+	if strings.ContainsAny(suffix, " .") {
+		return ""
+	}
+	return "proton_" + suffix
+}
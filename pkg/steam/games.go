@@ -0,0 +1,24 @@
+package steam
+
+import (
+	"github.com/shadowblip/steam-shortcut-manager/pkg/steam/library"
+)
+
+// GetLibraries will return all Steam library folders
+func GetLibraries() ([]*library.Library, error) {
+	steamDir, err := GetBaseDir()
+	if err != nil {
+		return nil, err
+	}
+	return library.GetLibraries(steamDir)
+}
+
+// GetInstalledGames will return the apps installed in every Steam library
+// folder, sorted by name.
+func GetInstalledGames() ([]*library.Game, error) {
+	steamDir, err := GetBaseDir()
+	if err != nil {
+		return nil, err
+	}
+	return library.GetGames(steamDir)
+}
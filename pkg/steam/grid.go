@@ -3,13 +3,40 @@ package steam
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
 )
 
 // ErrImageNotFound indicates that a grid images does not exist.
 var ErrImageNotFound = errors.New("image not found")
 
+// ImageType is the kind of grid image that Steam displays for an app.
+type ImageType string
+
+const (
+	ImagePortrait  ImageType = "portrait"
+	ImageLandscape ImageType = "landscape"
+	ImageHero      ImageType = "hero"
+	ImageLogo      ImageType = "logo"
+	ImageIcon      ImageType = "icon"
+)
+
+// ImageTypes is a list of all grid image types
+var ImageTypes = []ImageType{
+	ImagePortrait,
+	ImageLandscape,
+	ImageHero,
+	ImageLogo,
+	ImageIcon,
+}
+
+// KnownImageExtensions is a list of image file extensions that Steam will
+// look for in the grid directory.
+var KnownImageExtensions = []string{"png", "jpg", "jpeg", "ico"}
+
 // GetImagesDir will return the steam images directory
 func GetImagesDir(user string) (string, error) {
 	userDir, err := GetUserDir()
@@ -19,55 +46,315 @@ func GetImagesDir(user string) (string, error) {
 	return path.Join(userDir, user, "config", "grid"), nil
 }
 
-// GetImageLandscape will return the landscape grid image
-func GetImageLandscape(user, appId string) (string, error) {
+// GetImageBaseName will return the file name without an extension that Steam
+// uses for the given image type and app id.
+func GetImageBaseName(appId string, kind ImageType) (string, error) {
+	switch kind {
+	case ImagePortrait:
+		return fmt.Sprintf("%sp", appId), nil
+	case ImageLandscape:
+		return appId, nil
+	case ImageHero:
+		return fmt.Sprintf("%s_hero", appId), nil
+	case ImageLogo:
+		return fmt.Sprintf("%s_logo", appId), nil
+	case ImageIcon:
+		return fmt.Sprintf("%s-icon", appId), nil
+	}
+	return "", fmt.Errorf("unknown image type: %v", kind)
+}
+
+// GetImage will return the path to the grid image of the given type
+func GetImage(user, appId string, kind ImageType) (string, error) {
 	imagesDir, err := GetImagesDir(user)
 	if err != nil {
 		return "", err
 	}
+	baseName, err := GetImageBaseName(appId, kind)
+	if err != nil {
+		return "", err
+	}
 
 	// Check to see if the file exists with different extensions
-	return checkForImage(path.Join(imagesDir, appId))
+	return checkForImage(path.Join(imagesDir, baseName))
+}
+
+// GetImageLandscape will return the landscape grid image
+func GetImageLandscape(user, appId string) (string, error) {
+	return GetImage(user, appId, ImageLandscape)
 }
 
 // GetImagePortrait will return the portrait grid image
 func GetImagePortrait(user, appId string) (string, error) {
+	return GetImage(user, appId, ImagePortrait)
+}
+
+// GetImageHero will return the hero grid image
+func GetImageHero(user, appId string) (string, error) {
+	return GetImage(user, appId, ImageHero)
+}
+
+// GetImageLogo will return the logo grid image
+func GetImageLogo(user, appId string) (string, error) {
+	return GetImage(user, appId, ImageLogo)
+}
+
+// GetImageIcon will return the icon grid image
+func GetImageIcon(user, appId string) (string, error) {
+	return GetImage(user, appId, ImageIcon)
+}
+
+// GridImage is an image file found in the grid directory
+type GridImage struct {
+	Path  string    `json:"path"`
+	AppID string    `json:"appid"`
+	Type  ImageType `json:"type"`
+}
+
+// ParseImageFileName will parse the given grid image file name (e.g.
+// "1234p.png") and return the app id and image type it is used for.
+func ParseImageFileName(fileName string) (string, ImageType, error) {
+	ext := filepath.Ext(fileName)
+	if !isKnownExtension(strings.ToLower(strings.TrimPrefix(ext, "."))) {
+		return "", "", fmt.Errorf("not a grid image: %v", fileName)
+	}
+	baseName := strings.TrimSuffix(fileName, ext)
+
+	// Check the suffixes used for each image type. Landscape images have no
+	// suffix.
+	suffixes := []struct {
+		suffix string
+		kind   ImageType
+	}{
+		{"p", ImagePortrait},
+		{"_hero", ImageHero},
+		{"_logo", ImageLogo},
+		{"-icon", ImageIcon},
+		{"", ImageLandscape},
+	}
+	for _, s := range suffixes {
+		if !strings.HasSuffix(baseName, s.suffix) {
+			continue
+		}
+		appId := strings.TrimSuffix(baseName, s.suffix)
+		if !isNumber(appId) {
+			continue
+		}
+		return appId, s.kind, nil
+	}
+
+	return "", "", fmt.Errorf("not a grid image: %v", fileName)
+}
+
+// GetImages will return all grid images found in the user's grid directory
+func GetImages(user string) ([]*GridImage, error) {
 	imagesDir, err := GetImagesDir(user)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	files, err := os.ReadDir(imagesDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []*GridImage{}, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// Check to see if the file exists with different extensions
-	return checkForImage(path.Join(imagesDir, fmt.Sprintf("%sp", appId)))
+	images := []*GridImage{}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		appId, kind, err := ParseImageFileName(f.Name())
+		if err != nil {
+			continue
+		}
+		images = append(images, &GridImage{
+			Path:  path.Join(imagesDir, f.Name()),
+			AppID: appId,
+			Type:  kind,
+		})
+	}
+
+	return images, nil
 }
 
-// GetImageHero will return the hero grid image
-func GetImageHero(user, appId string) (string, error) {
+// SetImage will copy the given image file into the grid directory using the
+// file name that Steam looks for. Any existing image for the same type with a
+// different extension will be removed. Returns the path to the new image.
+func SetImage(user, appId string, kind ImageType, src string) (string, error) {
+	return setImage(user, appId, kind, src, copyFile)
+}
+
+// LinkImage is like SetImage, but hard links the given image file into the
+// grid directory so the same download can be shared. The file is copied if
+// it cannot be linked, e.g. when it is on another filesystem.
+func LinkImage(user, appId string, kind ImageType, src string) (string, error) {
+	return setImage(user, appId, kind, src, LinkFile)
+}
+
+// setImage will put the given image file into the grid directory using the
+// given function to copy or link it.
+func setImage(user, appId string, kind ImageType, src string, put func(src, dst string) error) (string, error) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(src), "."))
+	if !isKnownExtension(ext) {
+		return "", fmt.Errorf("unsupported image extension for %v: %v", src, ext)
+	}
+
 	imagesDir, err := GetImagesDir(user)
 	if err != nil {
 		return "", err
 	}
+	baseName, err := GetImageBaseName(appId, kind)
+	if err != nil {
+		return "", err
+	}
 
-	// Check to see if the file exists with different extensions
-	return checkForImage(path.Join(imagesDir, fmt.Sprintf("%s_hero", appId)))
+	// Check the source before touching the current image, so a bad source
+	// never leaves the shortcut without artwork.
+	info, err := os.Stat(src)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("image is not a regular file: %v", src)
+	}
+	err = os.MkdirAll(imagesDir, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	// The source may already be the current image
+	dst := path.Join(imagesDir, fmt.Sprintf("%s.%s", baseName, ext))
+	if same, err := sameFile(src, dst); err == nil && same {
+		return dst, removeOtherImages(imagesDir, baseName, ext)
+	}
+
+	// Write the image to a temporary file in the grid directory first, so
+	// it can be renamed into place once it is complete.
+	tmp, err := os.CreateTemp(imagesDir, "."+baseName+"-*."+ext)
+	if err != nil {
+		return "", err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+	err = tmp.Chmod(0644)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	err = put(src, tmpPath)
+	if err != nil {
+		return "", err
+	}
+	err = os.Rename(tmpPath, dst)
+	if err != nil {
+		return "", err
+	}
+
+	// Remove any conflicting images so Steam picks up the new one
+	err = removeOtherImages(imagesDir, baseName, ext)
+	if err != nil {
+		return "", err
+	}
+
+	return dst, nil
 }
 
-// GetImageLogo will return the logo grid image
-func GetImageLogo(user, appId string) (string, error) {
+// removeOtherImages will remove the images with the given base name in the
+// given directory for all known extensions except the given one.
+func removeOtherImages(imagesDir, baseName, keep string) error {
+	for _, ext := range KnownImageExtensions {
+		if ext == keep {
+			continue
+		}
+		fileName := path.Join(imagesDir, fmt.Sprintf("%s.%s", baseName, ext))
+		err := os.Remove(fileName)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveImage will remove the grid image of the given type for all known
+// extensions. Returns the paths of the removed files.
+func RemoveImage(user, appId string, kind ImageType) ([]string, error) {
 	imagesDir, err := GetImagesDir(user)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	baseName, err := GetImageBaseName(appId, kind)
+	if err != nil {
+		return nil, err
 	}
 
-	// Check to see if the file exists with different extensions
-	return checkForImage(path.Join(imagesDir, fmt.Sprintf("%s_logo", appId)))
+	removed := []string{}
+	for _, ext := range KnownImageExtensions {
+		fileName := path.Join(imagesDir, fmt.Sprintf("%s.%s", baseName, ext))
+		err := os.Remove(fileName)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return removed, err
+		}
+		removed = append(removed, fileName)
+	}
+
+	return removed, nil
+}
+
+// MoveImages will rename all grid images and the logo position config of
+// the given app id to use the new app id. This is needed when a shortcut's
+// name or executable changes. Returns the paths of the moved files.
+func MoveImages(user, oldAppId, newAppId string) ([]string, error) {
+	images, err := GetImages(user)
+	if err != nil {
+		return nil, err
+	}
+
+	moved := []string{}
+	for _, img := range images {
+		if img.AppID != oldAppId {
+			continue
+		}
+		baseName, err := GetImageBaseName(newAppId, img.Type)
+		if err != nil {
+			return moved, err
+		}
+		newPath := path.Join(path.Dir(img.Path), baseName+path.Ext(img.Path))
+		err = os.Rename(img.Path, newPath)
+		if err != nil {
+			return moved, err
+		}
+		moved = append(moved, newPath)
+	}
+
+	oldConfig, err := GetLogoConfigPath(user, oldAppId)
+	if err != nil {
+		return moved, err
+	}
+	newConfig, err := GetLogoConfigPath(user, newAppId)
+	if err != nil {
+		return moved, err
+	}
+	err = os.Rename(oldConfig, newConfig)
+	if err == nil {
+		moved = append(moved, newConfig)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return moved, err
+	}
+
+	return moved, nil
 }
 
 // checkForImage will check various image extensions for the given file path
 // without an extension. Returns a ErrImageNotFound error if it does not exist.
 func checkForImage(basePath string) (string, error) {
-	knownExtensions := []string{"png", "jpg", "jpeg", "ico"}
-	for _, ext := range knownExtensions {
+	for _, ext := range KnownImageExtensions {
 		fileName := fmt.Sprintf("%s.%s", basePath, ext)
 		if _, err := os.Stat(fileName); errors.Is(err, os.ErrNotExist) {
 			continue
@@ -76,3 +363,78 @@ func checkForImage(basePath string) (string, error) {
 	}
 	return "", ErrImageNotFound
 }
+
+// isKnownExtension will return whether or not the given extension (without a
+// leading dot) is one that Steam will use.
+func isKnownExtension(ext string) bool {
+	for _, known := range KnownImageExtensions {
+		if known == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// isNumber will return whether or not the given string only contains digits
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// LinkFile will hard link the given source file to the given destination,
+// replacing it if it exists. The file is copied if it cannot be linked.
+func LinkFile(src, dst string) error {
+	if same, err := sameFile(src, dst); err == nil && same {
+		return nil
+	}
+	err := os.Remove(dst)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	return copyFile(src, dst)
+}
+
+// sameFile will return whether or not the given paths are the same file
+func sameFile(a, b string) (bool, error) {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(infoA, infoB), nil
+}
+
+// copyFile will copy the given source file to the given destination
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,167 @@
+package library
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/logger"
+	"github.com/shadowblip/steam-shortcut-manager/pkg/vdf/text"
+)
+
+// Library is a Steam library folder that games are installed in
+type Library struct {
+	Path      string `json:"path"`
+	Label     string `json:"label,omitempty"`
+	ContentID string `json:"contentid,omitempty"`
+	TotalSize int64  `json:"totalsize,omitempty"`
+	// AppSizes are the sizes of the apps in the library as recorded in
+	// libraryfolders.vdf, by app id
+	AppSizes map[string]int64 `json:"apps,omitempty"`
+}
+
+// GetLibraryFoldersPath will return the path to the libraryfolders.vdf file in
+// the given Steam directory.
+func GetLibraryFoldersPath(steamDir string) string {
+	return path.Join(steamDir, "steamapps", "libraryfolders.vdf")
+}
+
+// GetLibraries will return all Steam library folders of the given Steam
+// directory. The Steam directory itself is always the first library.
+func GetLibraries(steamDir string) ([]*Library, error) {
+	libraries := []*Library{{Path: steamDir, AppSizes: map[string]int64{}}}
+	root, err := text.ReadFile(GetLibraryFoldersPath(steamDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return libraries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The root key is "libraryfolders" on newer clients and "LibraryFolders"
+	// on older ones, which only list the paths.
+	folders := root.Child("libraryfolders")
+	if folders == nil {
+		return libraries, nil
+	}
+	for _, entry := range folders.Children {
+		if _, err := strconv.Atoi(entry.Key); err != nil {
+			continue
+		}
+		library := &Library{AppSizes: map[string]int64{}}
+		if entry.IsObject() {
+			library.Path, _ = entry.GetString("path")
+			library.Label, _ = entry.GetString("label")
+			library.ContentID, _ = entry.GetString("contentid")
+			library.TotalSize = parseInt(entry.GetString("totalsize"))
+			if apps := entry.Child("apps"); apps != nil {
+				for _, app := range apps.Children {
+					library.AppSizes[app.Key] = parseInt(app.Value, true)
+				}
+			}
+		} else {
+			library.Path = entry.Value
+		}
+		if library.Path == "" {
+			continue
+		}
+
+		// The Steam directory is usually listed as well, often through a
+		// symlink such as ~/.steam/steam
+		if existing := findLibrary(libraries, library.Path); existing != nil {
+			if existing.Path == steamDir {
+				library.Path = existing.Path
+				*existing = *library
+			}
+			continue
+		}
+		libraries = append(libraries, library)
+	}
+
+	return libraries, nil
+}
+
+// GetGames will return the games installed in every library of the given Steam
+// directory, sorted by name.
+func GetGames(steamDir string) ([]*Game, error) {
+	libraries, err := GetLibraries(steamDir)
+	if err != nil {
+		return nil, err
+	}
+
+	games := []*Game{}
+	seen := map[string]bool{}
+	for _, library := range libraries {
+		found, err := library.Games()
+		if err != nil {
+			return nil, err
+		}
+		for _, game := range found {
+			if seen[game.AppID] {
+				continue
+			}
+			seen[game.AppID] = true
+			games = append(games, game)
+		}
+	}
+	sort.Slice(games, func(i, j int) bool { return games[i].Name < games[j].Name })
+
+	return games, nil
+}
+
+// SteamAppsDir will return the steamapps directory of the library
+func (l *Library) SteamAppsDir() string {
+	return path.Join(l.Path, "steamapps")
+}
+
+// Games will return the games installed in the library. Manifests that cannot
+// be read are skipped and logged when debugging is enabled.
+func (l *Library) Games() ([]*Game, error) {
+	manifests, err := filepath.Glob(path.Join(l.SteamAppsDir(), "appmanifest_*.acf"))
+	if err != nil {
+		return nil, err
+	}
+	games := []*Game{}
+	for _, manifest := range manifests {
+		game, err := ReadManifest(manifest)
+		if err != nil {
+			logger.DebugPrintln("Skipping app manifest:", err)
+			continue
+		}
+		game.Library = l.Path
+		game.Path = path.Join(l.SteamAppsDir(), "common", game.InstallDir)
+		games = append(games, game)
+	}
+	return games, nil
+}
+
+// findLibrary will return the library with the given path, ignoring symlinks
+func findLibrary(libraries []*Library, p string) *Library {
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		resolved = filepath.Clean(p)
+	}
+	for _, library := range libraries {
+		other, err := filepath.EvalSymlinks(library.Path)
+		if err != nil {
+			other = filepath.Clean(library.Path)
+		}
+		if other == resolved {
+			return library
+		}
+	}
+	return nil
+}
+
+// parseInt will parse the given KeyValues integer, returning 0 if it is
+// missing or invalid.
+func parseInt(value string, ok bool) int64 {
+	if !ok {
+		return 0
+	}
+	num, _ := strconv.ParseInt(value, 10, 64)
+	return num
+}
@@ -0,0 +1,225 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSteam is a Steam directory with a second library for tests
+type testSteam struct {
+	// dir is the real Steam directory
+	dir string
+	// link is a symlink to the Steam directory, like ~/.steam/steam
+	link string
+	// library is the path of the second library
+	library string
+}
+
+// newTestSteam will create a Steam directory with the given libraryfolders.vdf
+// fixture and copy the given app manifests into the Steam directory and the
+// second library.
+func newTestSteam(t *testing.T, libraryFolders string, steamApps, libraryApps []string) *testSteam {
+	t.Helper()
+	tmp := t.TempDir()
+	s := &testSteam{
+		dir:     filepath.Join(tmp, "steam"),
+		link:    filepath.Join(tmp, "steam-link"),
+		library: filepath.Join(tmp, "library"),
+	}
+	for _, dir := range []string{s.dir, s.library} {
+		err := os.MkdirAll(filepath.Join(dir, "steamapps"), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := os.Symlink(s.dir, s.link)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := readFixture(t, libraryFolders)
+	data = strings.NewReplacer("@STEAM_LINK@", s.link, "@LIBRARY@", s.library).Replace(data)
+	writeFile(t, GetLibraryFoldersPath(s.dir), data)
+	for dir, apps := range map[string][]string{s.dir: steamApps, s.library: libraryApps} {
+		for _, app := range apps {
+			name := "appmanifest_" + app + ".acf"
+			writeFile(t, filepath.Join(dir, "steamapps", name), readFixture(t, name))
+		}
+	}
+
+	return s
+}
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func writeFile(t *testing.T, file, data string) {
+	t.Helper()
+	err := os.WriteFile(file, []byte(data), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetLibraries(t *testing.T) {
+	s := newTestSteam(t, "libraryfolders.vdf", nil, nil)
+	libraries, err := GetLibraries(s.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(libraries) != 2 {
+		t.Fatalf("expected 2 libraries, got %v", len(libraries))
+	}
+
+	// The Steam directory is listed through a symlink, so its entry is
+	// merged into the first library
+	steam := libraries[0]
+	if steam.Path != s.dir || steam.ContentID != "4410264453414085402" {
+		t.Errorf("unexpected Steam library: %+v", steam)
+	}
+	wantSizes := map[string]int64{"620": 12884901888, "228980": 412654890}
+	if !reflect.DeepEqual(steam.AppSizes, wantSizes) {
+		t.Errorf("unexpected app sizes: %v", steam.AppSizes)
+	}
+
+	library := libraries[1]
+	if library.Path != s.library || library.Label != "SD Card" || library.TotalSize != 511859089408 {
+		t.Errorf("unexpected library: %+v", library)
+	}
+	if library.AppSizes["440"] != 26843545600 {
+		t.Errorf("unexpected app sizes: %v", library.AppSizes)
+	}
+}
+
+func TestGetLibrariesOldFormat(t *testing.T) {
+	s := newTestSteam(t, "libraryfolders.old.vdf", nil, nil)
+	libraries, err := GetLibraries(s.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := []string{}
+	for _, library := range libraries {
+		paths = append(paths, library.Path)
+	}
+	if !reflect.DeepEqual(paths, []string{s.dir, s.library}) {
+		t.Errorf("unexpected libraries: %v", paths)
+	}
+}
+
+func TestGetLibrariesMissing(t *testing.T) {
+	dir := t.TempDir()
+	libraries, err := GetLibraries(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(libraries) != 1 || libraries[0].Path != dir {
+		t.Errorf("expected only the Steam directory, got %+v", libraries)
+	}
+}
+
+func TestGetGames(t *testing.T) {
+	s := newTestSteam(t, "libraryfolders.vdf", []string{"620", "228980", "999"}, []string{"440", "620"})
+	games, err := GetGames(s.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Games are sorted by name, installed twice only once and unreadable
+	// manifests are skipped
+	names := []string{}
+	for _, game := range games {
+		names = append(names, game.Name)
+	}
+	want := []string{"Portal 2", "Steamworks Common Redistributables", "Team Fortress 2"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("unexpected games: %v", names)
+	}
+
+	portal := games[0]
+	if portal.Library != s.dir || portal.Path != filepath.Join(s.dir, "steamapps", "common", "Portal 2") {
+		t.Errorf("unexpected paths: %v, %v", portal.Library, portal.Path)
+	}
+	if games[2].Library != s.library {
+		t.Errorf("unexpected library: %v", games[2].Library)
+	}
+	if portal.IsTool() || !games[1].IsTool() {
+		t.Error("unexpected tool detection")
+	}
+}
+
+func TestReadManifest(t *testing.T) {
+	game, err := ReadManifest(filepath.Join("testdata", "appmanifest_620.acf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.AppID != "620" || game.Name != "Portal 2" || game.InstallDir != "Portal 2" || game.BuildID != "12345" {
+		t.Errorf("unexpected game: %+v", game)
+	}
+	if game.SizeOnDisk != 12884901888 || !game.LastUpdated.Equal(time.Unix(1700000000, 0)) || !game.LastPlayed.IsZero() {
+		t.Errorf("unexpected game: %+v", game)
+	}
+	if !game.IsInstalled() || !reflect.DeepEqual(game.State, []string{"FullyInstalled"}) {
+		t.Errorf("unexpected state: %v", game.State)
+	}
+
+	// Truncated manifests are an error
+	_, err = ReadManifest(filepath.Join("testdata", "appmanifest_999.acf"))
+	if err == nil {
+		t.Error("expected an error for a truncated manifest")
+	}
+
+	// So are manifests without a valid app id
+	for name, data := range map[string]string{
+		"no state":  "\"Other\"\n{\n}\n",
+		"no appid":  "\"AppState\"\n{\n\t\"name\"\t\"Game\"\n}\n",
+		"bad appid": "\"AppState\"\n{\n\t\"appid\"\t\"abc\"\n}\n",
+	} {
+		file := filepath.Join(t.TempDir(), "appmanifest.acf")
+		writeFile(t, file, data)
+		if _, err := ReadManifest(file); err == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+}
+
+func TestStateFlags(t *testing.T) {
+	tests := []struct {
+		flags StateFlags
+		want  []string
+	}{
+		{0, []string{"Invalid"}},
+		{4, []string{"FullyInstalled"}},
+		{6, []string{"UpdateRequired", "FullyInstalled"}},
+		{1026, []string{"UpdateRequired", "UpdateStarted"}},
+		{StateDownloading | StateStaging, []string{"Downloading", "Staging"}},
+	}
+	for _, tt := range tests {
+		if got := tt.flags.Strings(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%d: got %v, want %v", tt.flags, got, tt.want)
+		}
+	}
+	if got := StateFlags(6).String(); got != "UpdateRequired,FullyInstalled" {
+		t.Errorf("unexpected string: %v", got)
+	}
+	if StateFlags(6).Has(StateUninstalled) || !StateFlags(6).Has(StateUpdateRequired|StateFullyInstalled) {
+		t.Error("unexpected result for Has")
+	}
+
+	flag, ok := ParseState("fullyinstalled")
+	if !ok || flag != StateFullyInstalled {
+		t.Errorf("unexpected flag: %v", flag)
+	}
+	if _, ok := ParseState("Unknown"); ok {
+		t.Error("expected unknown state not to be parsed")
+	}
+}
@@ -0,0 +1,93 @@
+package library
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/vdf/text"
+)
+
+// toolPrefixes are name prefixes of installed apps that are not games
+var toolPrefixes = []string{
+	"Proton",
+	"Steam Linux Runtime",
+	"Steamworks Common Redistributables",
+}
+
+// Game is an app installed in a Steam library, as described by its
+// appmanifest_<appid>.acf file.
+type Game struct {
+	AppID       string     `json:"appid"`
+	Name        string     `json:"name"`
+	InstallDir  string     `json:"installdir"`
+	Path        string     `json:"path"`
+	Library     string     `json:"library"`
+	SizeOnDisk  int64      `json:"sizeOnDisk"`
+	BuildID     string     `json:"buildid"`
+	StateFlags  StateFlags `json:"stateFlags"`
+	State       []string   `json:"state"`
+	LastUpdated time.Time  `json:"lastUpdated"`
+	LastPlayed  time.Time  `json:"lastPlayed"`
+	// Manifest holds all of the values in the manifest
+	Manifest *text.Node `json:"-"`
+}
+
+// IsTool will return whether or not the app is a Steam tool (e.g. Proton)
+// rather than a game.
+func (g *Game) IsTool() bool {
+	for _, prefix := range toolPrefixes {
+		if strings.HasPrefix(g.Name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInstalled will return whether or not the game is fully installed
+func (g *Game) IsInstalled() bool {
+	return g.StateFlags.Has(StateFullyInstalled)
+}
+
+// ReadManifest will read the given appmanifest_*.acf file. The Path and
+// Library of the game are not set.
+func ReadManifest(file string) (*Game, error) {
+	root, err := text.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	state := root.Child("AppState")
+	if state == nil {
+		return nil, fmt.Errorf("no AppState found in %v", file)
+	}
+
+	game := &Game{Manifest: state}
+	game.AppID, _ = state.GetString("appid")
+	game.Name, _ = state.GetString("name")
+	game.InstallDir, _ = state.GetString("installdir")
+	game.BuildID, _ = state.GetString("buildid")
+	game.SizeOnDisk = parseInt(state.GetString("SizeOnDisk"))
+	game.StateFlags = StateFlags(parseInt(state.GetString("StateFlags")))
+	game.State = game.StateFlags.Strings()
+	game.LastUpdated = parseTime(state.GetString("LastUpdated"))
+	game.LastPlayed = parseTime(state.GetString("LastPlayed"))
+	if game.AppID == "" {
+		return nil, fmt.Errorf("no appid found in %v", file)
+	}
+	if _, err := strconv.ParseUint(game.AppID, 10, 32); err != nil {
+		return nil, fmt.Errorf("invalid appid in %v: %v", file, game.AppID)
+	}
+
+	return game, nil
+}
+
+// parseTime will parse the given unix timestamp, returning the zero time if
+// it is missing or zero.
+func parseTime(value string, ok bool) time.Time {
+	seconds := parseInt(value, ok)
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
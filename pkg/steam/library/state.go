@@ -0,0 +1,94 @@
+package library
+
+import "strings"
+
+// StateFlags is the install state of a game in its app manifest
+type StateFlags uint32
+
+// Install states of a game
+const (
+	StateInvalid        StateFlags = 0
+	StateUninstalled    StateFlags = 1 << 0
+	StateUpdateRequired StateFlags = 1 << 1
+	StateFullyInstalled StateFlags = 1 << 2
+	StateEncrypted      StateFlags = 1 << 3
+	StateLocked         StateFlags = 1 << 4
+	StateFilesMissing   StateFlags = 1 << 5
+	StateAppRunning     StateFlags = 1 << 6
+	StateFilesCorrupt   StateFlags = 1 << 7
+	StateUpdateRunning  StateFlags = 1 << 8
+	StateUpdatePaused   StateFlags = 1 << 9
+	StateUpdateStarted  StateFlags = 1 << 10
+	StateUninstalling   StateFlags = 1 << 11
+	StateBackupRunning  StateFlags = 1 << 12
+	StateReconfiguring  StateFlags = 1 << 16
+	StateValidating     StateFlags = 1 << 17
+	StateAddingFiles    StateFlags = 1 << 18
+	StatePreallocating  StateFlags = 1 << 19
+	StateDownloading    StateFlags = 1 << 20
+	StateStaging        StateFlags = 1 << 21
+	StateCommitting     StateFlags = 1 << 22
+	StateUpdateStopping StateFlags = 1 << 23
+)
+
+// stateNames are the names of each state flag in bit order
+var stateNames = []struct {
+	flag StateFlags
+	name string
+}{
+	{StateUninstalled, "Uninstalled"},
+	{StateUpdateRequired, "UpdateRequired"},
+	{StateFullyInstalled, "FullyInstalled"},
+	{StateEncrypted, "Encrypted"},
+	{StateLocked, "Locked"},
+	{StateFilesMissing, "FilesMissing"},
+	{StateAppRunning, "AppRunning"},
+	{StateFilesCorrupt, "FilesCorrupt"},
+	{StateUpdateRunning, "UpdateRunning"},
+	{StateUpdatePaused, "UpdatePaused"},
+	{StateUpdateStarted, "UpdateStarted"},
+	{StateUninstalling, "Uninstalling"},
+	{StateBackupRunning, "BackupRunning"},
+	{StateReconfiguring, "Reconfiguring"},
+	{StateValidating, "Validating"},
+	{StateAddingFiles, "AddingFiles"},
+	{StatePreallocating, "Preallocating"},
+	{StateDownloading, "Downloading"},
+	{StateStaging, "Staging"},
+	{StateCommitting, "Committing"},
+	{StateUpdateStopping, "UpdateStopping"},
+}
+
+// Has will return whether or not the given flag is set
+func (s StateFlags) Has(flag StateFlags) bool {
+	return s&flag == flag
+}
+
+// Strings will return the names of the flags that are set
+func (s StateFlags) Strings() []string {
+	if s == StateInvalid {
+		return []string{"Invalid"}
+	}
+	names := []string{}
+	for _, state := range stateNames {
+		if s.Has(state.flag) {
+			names = append(names, state.name)
+		}
+	}
+	return names
+}
+
+// String will return the names of the flags that are set, separated by commas
+func (s StateFlags) String() string {
+	return strings.Join(s.Strings(), ",")
+}
+
+// ParseState will return the flag with the given name (case-insensitive)
+func ParseState(name string) (StateFlags, bool) {
+	for _, state := range stateNames {
+		if strings.EqualFold(state.name, name) {
+			return state.flag, true
+		}
+	}
+	return StateInvalid, false
+}
@@ -0,0 +1,153 @@
+package steam
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/vdf/text"
+)
+
+// launchCommand is the placeholder Steam replaces with the game's command
+const launchCommand = "%command%"
+
+// localConfigAppsPath is the path of keys to the per-app settings section in
+// localconfig.vdf
+var localConfigAppsPath = []string{"UserLocalConfigStore", "Software", "Valve", "Steam", "apps"}
+
+// LocalConfig is a user's localconfig.vdf file, which holds per-user
+// settings for Steam games such as their launch options. Steam rewrites
+// this file when it exits, so changes should be made while Steam is closed.
+type LocalConfig struct {
+	path string
+	root *text.Node
+}
+
+// GetLocalConfigPath will return the path to the localconfig.vdf file for the
+// given user.
+func GetLocalConfigPath(user string) (string, error) {
+	userDir, err := GetUserDir()
+	if err != nil {
+		return "", err
+	}
+	return path.Join(userDir, user, "config", "localconfig.vdf"), nil
+}
+
+// LoadLocalConfig will load the localconfig.vdf file for the given user
+func LoadLocalConfig(user string) (*LocalConfig, error) {
+	configPath, err := GetLocalConfigPath(user)
+	if err != nil {
+		return nil, err
+	}
+	root, err := text.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalConfig{path: configPath, root: root}, nil
+}
+
+// AppIDs will return the ids of all apps that have launch options set
+func (c *LocalConfig) AppIDs() []string {
+	appIds := []string{}
+	apps := c.root.Lookup(localConfigAppsPath...)
+	if apps == nil {
+		return appIds
+	}
+	for _, app := range apps.Children {
+		if options := app.Child("LaunchOptions"); options != nil && options.Value != "" {
+			appIds = append(appIds, app.Key)
+		}
+	}
+	sort.Strings(appIds)
+	return appIds
+}
+
+// GetLaunchOptions will return the launch options of the given app id
+func (c *LocalConfig) GetLaunchOptions(appId string) string {
+	options := c.root.Lookup(append(localConfigAppsPath, appId, "LaunchOptions")...)
+	if options == nil {
+		return ""
+	}
+	return options.Value
+}
+
+// SetLaunchOptions will set the launch options of the given app id. Empty
+// options will remove them.
+func (c *LocalConfig) SetLaunchOptions(appId, options string) {
+	if options == "" {
+		if app := c.root.Lookup(append(localConfigAppsPath, appId)...); app != nil {
+			app.Remove("LaunchOptions")
+		}
+		return
+	}
+	c.root.EnsureObject(append(localConfigAppsPath, appId)...).Set("LaunchOptions", options)
+}
+
+// Save will write the changes back to localconfig.vdf
+func (c *LocalConfig) Save() error {
+	return text.WriteFile(c.path, c.root)
+}
+
+// MergeLaunchOptions will add the given options to the existing launch
+// options. Anything before %command% (e.g. "gamemoderun %command%") is added
+// before the game's command, and anything after it, or options without
+// %command%, are added as game arguments. Options that are already present
+// are not added again.
+func MergeLaunchOptions(existing, options string) string {
+	prefix, suffix := splitLaunchOptions(existing)
+	addPrefix, addSuffix := splitLaunchOptions(options)
+	if addPrefix != "" && !containsOption(prefix, addPrefix) {
+		prefix = strings.TrimSpace(prefix + " " + addPrefix)
+	}
+	if addSuffix != "" && !containsOption(suffix, addSuffix) {
+		suffix = strings.TrimSpace(suffix + " " + addSuffix)
+	}
+	return joinLaunchOptions(prefix, suffix)
+}
+
+// StripLaunchOptions will remove the given options from the existing launch
+// options. It is the inverse of MergeLaunchOptions.
+func StripLaunchOptions(existing, options string) string {
+	prefix, suffix := splitLaunchOptions(existing)
+	removePrefix, removeSuffix := splitLaunchOptions(options)
+	prefix = removeOption(prefix, removePrefix)
+	suffix = removeOption(suffix, removeSuffix)
+	return joinLaunchOptions(prefix, suffix)
+}
+
+// splitLaunchOptions will split the given launch options into the part that
+// wraps the game's command and the game's arguments.
+func splitLaunchOptions(options string) (prefix, suffix string) {
+	options = strings.TrimSpace(options)
+	i := strings.Index(options, launchCommand)
+	if i < 0 {
+		return "", options
+	}
+	prefix = strings.TrimSpace(options[:i])
+	suffix = strings.TrimSpace(options[i+len(launchCommand):])
+	return prefix, suffix
+}
+
+// joinLaunchOptions will combine the wrapping part and the game's arguments
+// into launch options.
+func joinLaunchOptions(prefix, suffix string) string {
+	if prefix == "" {
+		return suffix
+	}
+	return strings.TrimSpace(strings.Join([]string{prefix, launchCommand, suffix}, " "))
+}
+
+// containsOption will return whether or not the given options contain the
+// given option as whole words.
+func containsOption(options, option string) bool {
+	return strings.Contains(" "+options+" ", " "+option+" ")
+}
+
+// removeOption will remove the given option from the options as whole words
+func removeOption(options, option string) string {
+	if option == "" {
+		return options
+	}
+	result := strings.Replace(" "+options+" ", " "+option+" ", " ", 1)
+	return strings.Join(strings.Fields(result), " ")
+}
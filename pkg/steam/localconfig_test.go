@@ -0,0 +1,83 @@
+package steam
+
+import "testing"
+
+func TestMergeLaunchOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		options  string
+		want     string
+	}{
+		{"empty with command", "", "gamemoderun %command%", "gamemoderun %command%"},
+		{"empty without command", "", "-novid", "-novid"},
+		{"empty options", "gamemoderun %command% -novid", "", "gamemoderun %command% -novid"},
+		{"arguments to wrapper", "-novid", "gamemoderun %command%", "gamemoderun %command% -novid"},
+		{"wrapper to arguments", "gamemoderun %command%", "-novid", "gamemoderun %command% -novid"},
+		{"both parts", "gamemoderun %command% -novid", "mangohud %command% -fullscreen", "gamemoderun mangohud %command% -novid -fullscreen"},
+		{"empty prefix", "DXVK_HUD=1 %command%", "%command% -fullscreen", "DXVK_HUD=1 %command% -fullscreen"},
+		{"empty suffix", "%command% -novid", "gamemoderun %command%", "gamemoderun %command% -novid"},
+		{"already present", "gamemoderun %command% -novid", "gamemoderun %command% -novid", "gamemoderun %command% -novid"},
+		{"whole words only", "-novideo", "-novid", "-novideo -novid"},
+		{"extra whitespace", "  gamemoderun   %command%  ", "", "gamemoderun %command%"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MergeLaunchOptions(tt.existing, tt.options); got != tt.want {
+				t.Errorf("MergeLaunchOptions(%q, %q) = %q, want %q", tt.existing, tt.options, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripLaunchOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		options  string
+		want     string
+	}{
+		{"wrapper", "gamemoderun %command% -novid", "gamemoderun %command%", "-novid"},
+		{"one of two wrappers", "gamemoderun mangohud %command%", "mangohud %command%", "gamemoderun %command%"},
+		{"arguments", "-novid -fullscreen", "-novid", "-fullscreen"},
+		{"arguments after command", "gamemoderun %command% -novid -fullscreen", "%command% -fullscreen", "gamemoderun %command% -novid"},
+		{"everything", "gamemoderun %command% -novid", "gamemoderun %command% -novid", ""},
+		{"not present", "-novid", "gamemoderun %command%", "-novid"},
+		{"empty options", "gamemoderun %command%", "", "gamemoderun %command%"},
+		{"empty existing", "", "gamemoderun %command%", ""},
+		{"whole words only", "-novideo", "-novid", "-novideo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripLaunchOptions(tt.existing, tt.options); got != tt.want {
+				t.Errorf("StripLaunchOptions(%q, %q) = %q, want %q", tt.existing, tt.options, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLaunchOptionsRepeated(t *testing.T) {
+	tests := []struct {
+		existing string
+		options  string
+	}{
+		{"", "gamemoderun %command%"},
+		{"-novid", "gamemoderun %command% -fullscreen"},
+		{"DXVK_HUD=1 %command% -novid", "mangohud %command%"},
+		{"gamemoderun %command%", "-fullscreen"},
+	}
+	for _, tt := range tests {
+		// Appending again does nothing, and removing restores the original
+		merged := MergeLaunchOptions(tt.existing, tt.options)
+		if again := MergeLaunchOptions(merged, tt.options); again != merged {
+			t.Errorf("%q: merging twice gave %q, want %q", tt.existing, again, merged)
+		}
+		stripped := StripLaunchOptions(merged, tt.options)
+		if stripped != tt.existing {
+			t.Errorf("%q: stripping %q gave %q", tt.existing, merged, stripped)
+		}
+		if again := StripLaunchOptions(stripped, tt.options); again != stripped {
+			t.Errorf("%q: stripping twice gave %q, want %q", tt.existing, again, stripped)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package steam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+)
+
+// ErrLogoPositionNotFound indicates that no logo position config exists.
+var ErrLogoPositionNotFound = errors.New("logo position not found")
+
+// Pinned positions that Steam supports for logos placed over the hero image.
+const (
+	LogoPinBottomLeft   = "BottomLeft"
+	LogoPinUpperLeft    = "UpperLeft"
+	LogoPinCenterCenter = "CenterCenter"
+	LogoPinUpperCenter  = "UpperCenter"
+	LogoPinBottomCenter = "BottomCenter"
+)
+
+// LogoPositionPresets is a map of preset names to logo positions
+var LogoPositionPresets = map[string]LogoPosition{
+	LogoPinBottomLeft:   {PinnedPosition: LogoPinBottomLeft, WidthPct: 50, HeightPct: 50},
+	LogoPinUpperLeft:    {PinnedPosition: LogoPinUpperLeft, WidthPct: 50, HeightPct: 50},
+	LogoPinCenterCenter: {PinnedPosition: LogoPinCenterCenter, WidthPct: 60, HeightPct: 60},
+	LogoPinUpperCenter:  {PinnedPosition: LogoPinUpperCenter, WidthPct: 60, HeightPct: 50},
+	LogoPinBottomCenter: {PinnedPosition: LogoPinBottomCenter, WidthPct: 60, HeightPct: 50},
+}
+
+// LogoConfig is the structure of the "<appid>.json" file that Steam stores in
+// the grid directory. E.g.
+//
+//	{"nVersion":1,"logoPosition":{"pinnedPosition":"BottomLeft","nWidthPct":50,"nHeightPct":50}}
+type LogoConfig struct {
+	Version      int           `json:"nVersion"`
+	LogoPosition *LogoPosition `json:"logoPosition"`
+}
+
+// LogoPosition defines where the logo is placed over the hero image
+type LogoPosition struct {
+	PinnedPosition string  `json:"pinnedPosition"`
+	WidthPct       float64 `json:"nWidthPct"`
+	HeightPct      float64 `json:"nHeightPct"`
+}
+
+// IsValidLogoPin will return whether or not the given pinned position is one
+// that Steam supports.
+func IsValidLogoPin(pin string) bool {
+	_, ok := LogoPositionPresets[pin]
+	return ok
+}
+
+// GetLogoConfigPath will return the path to the logo position config for the
+// given app id.
+func GetLogoConfigPath(user, appId string) (string, error) {
+	imagesDir, err := GetImagesDir(user)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(imagesDir, fmt.Sprintf("%s.json", appId)), nil
+}
+
+// GetLogoPosition will return the logo position for the given app id. Returns
+// a ErrLogoPositionNotFound error if it does not exist.
+func GetLogoPosition(user, appId string) (*LogoPosition, error) {
+	configPath, err := GetLogoConfigPath(user, appId)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrLogoPositionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var config LogoConfig
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %v: %v", configPath, err)
+	}
+	if config.LogoPosition == nil {
+		return nil, ErrLogoPositionNotFound
+	}
+
+	return config.LogoPosition, nil
+}
+
+// SetLogoPosition will write the given logo position for the given app id.
+func SetLogoPosition(user, appId string, position *LogoPosition) error {
+	if !IsValidLogoPin(position.PinnedPosition) {
+		return fmt.Errorf("invalid logo pinned position: %v", position.PinnedPosition)
+	}
+	configPath, err := GetLogoConfigPath(user, appId)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(path.Dir(configPath), 0755)
+	if err != nil {
+		return err
+	}
+
+	config := LogoConfig{Version: 1, LogoPosition: position}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(configPath, data, 0644)
+}
+
+// RemoveLogoPosition will remove the logo position config for the given app
+// id so Steam falls back to its default placement.
+func RemoveLogoPosition(user, appId string) error {
+	configPath, err := GetLogoConfigPath(user, appId)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(configPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -5,10 +5,24 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 )
 
+// baseDir overrides the default Steam directory if set
+var baseDir string
+
+// SetBaseDir will override the Steam directory, e.g. for a Flatpak install of
+// Steam in ~/.var/app/com.valvesoftware.Steam/.steam/steam. An empty value
+// will use the default directory.
+func SetBaseDir(dir string) {
+	baseDir = ExpandHome(dir)
+}
+
 // GetSteamDir will return the base steam config directory
 func GetBaseDir() (string, error) {
+	if baseDir != "" {
+		return baseDir, nil
+	}
 	dirname, err := os.UserHomeDir()
 	if err != nil {
 		return dirname, err
@@ -72,3 +86,16 @@ func HasShortcuts(user string) bool {
 	}
 	return true
 }
+
+// ExpandHome will replace a leading "~" in the given path with the user's
+// home directory.
+func ExpandHome(dir string) string {
+	if dir != "~" && !strings.HasPrefix(dir, "~/") {
+		return dir
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return dir
+	}
+	return path.Join(homeDir, strings.TrimPrefix(dir, "~"))
+}
@@ -0,0 +1,66 @@
+package steamgriddb
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ContentPolicy controls whether images tagged as NSFW or humor are returned
+// by the SteamGridDB API. The values match the "nsfw" and "humor" query
+// parameters of the API.
+type ContentPolicy string
+
+const (
+	// ContentDefault uses the SteamGridDB default, which excludes NSFW images
+	// and includes humor images.
+	ContentDefault ContentPolicy = ""
+	// ContentAny includes tagged and untagged images
+	ContentAny ContentPolicy = "any"
+	// ContentExclude excludes tagged images
+	ContentExclude ContentPolicy = "false"
+	// ContentOnly only includes tagged images
+	ContentOnly ContentPolicy = "true"
+)
+
+// ContentPolicies are all supported content policies
+var ContentPolicies = []ContentPolicy{ContentAny, ContentExclude, ContentOnly}
+
+// ParseContentPolicy will return the content policy with the given name. An
+// empty name will return the default policy.
+func ParseContentPolicy(name string) (ContentPolicy, error) {
+	if name == "" {
+		return ContentDefault, nil
+	}
+	names := []string{}
+	for _, policy := range ContentPolicies {
+		if string(policy) == name {
+			return policy, nil
+		}
+		names = append(names, string(policy))
+	}
+	return "", fmt.Errorf("unknown content policy: %v (supported: %v)", name, strings.Join(names, ", "))
+}
+
+// SetContentPolicy will set whether images tagged as NSFW or humor are
+// included in the image results of the client.
+func (c *Client) SetContentPolicy(nsfw, humor ContentPolicy) {
+	c.nsfw = nsfw
+	c.humor = humor
+}
+
+// withContentPolicy will add the content policy query parameters of the
+// client to the given image endpoint.
+func (c *Client) withContentPolicy(path string) string {
+	query := url.Values{}
+	if c.nsfw != ContentDefault {
+		query.Set("nsfw", string(c.nsfw))
+	}
+	if c.humor != ContentDefault {
+		query.Set("humor", string(c.humor))
+	}
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
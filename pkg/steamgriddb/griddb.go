@@ -18,6 +18,9 @@ const BASE_URL = "https://www.steamgriddb.com/api/v2"
 
 var isDebug = os.Getenv("DEBUG") == "1"
 
+// ErrUnauthorized indicates that the API key was rejected by SteamGridDB.
+var ErrUnauthorized = errors.New("invalid SteamGridDB API key")
+
 // NewClient will return a new SteamGridDB Client
 func NewClient(apiKey string) *Client {
 	return &Client{
@@ -29,6 +32,8 @@ func NewClient(apiKey string) *Client {
 type Client struct {
 	apiKey string
 	client http.Client
+	nsfw   ContentPolicy
+	humor  ContentPolicy
 }
 
 func (c *Client) debug(str string) {
@@ -58,13 +63,28 @@ func (c *Client) get(url string, authenticated bool) (*http.Response, error) {
 	}
 	if res.StatusCode != 200 {
 		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
 		logger.DebugPrintln(res.StatusCode)
 		logger.DebugPrintln(string(body))
+		if res.StatusCode == http.StatusUnauthorized {
+			return nil, ErrUnauthorized
+		}
 		return nil, fmt.Errorf("Received non 200 response code")
 	}
 	return res, nil
 }
 
+// Validate will check that the API key of the client is accepted by
+// SteamGridDB. Returns ErrUnauthorized if it is not.
+func (c *Client) Validate() error {
+	res, err := c.Get("/search/autocomplete/steam")
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
 // Download will download the given file to the provided path
 func (c *Client) Download(url, path string) error {
 	// Fetch the file
@@ -78,11 +98,13 @@ func (c *Client) Download(url, path string) error {
 	dir := filepath.Dir(path)
 	os.MkdirAll(dir, os.ModePerm)
 
-	// Create a empty file
-	file, err := os.Create(path)
+	// Write to a temporary file first so an existing file is only replaced
+	// once the download is complete.
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*")
 	if err != nil {
 		return err
 	}
+	defer os.Remove(file.Name())
 	defer file.Close()
 
 	// Write the bytes to the file
@@ -90,8 +112,16 @@ func (c *Client) Download(url, path string) error {
 	if err != nil {
 		return err
 	}
+	err = file.Chmod(0644)
+	if err != nil {
+		return err
+	}
+	err = file.Close()
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return os.Rename(file.Name(), path)
 }
 
 // CachedDownload will download only if the file does not already exist.
@@ -127,7 +157,17 @@ func (c *Client) Search(term string) (*SearchResponse, error) {
 
 // GetGrids will return the results of the grids for a given game ID
 func (c *Client) GetGrids(gameID string, filters ...FilterGrid) (*GridResponse, error) {
-	res, err := c.Get("/grids/game/" + gameID)
+	return c.getGrids("/grids/game/"+gameID, filters...)
+}
+
+// GetGridsByPlatform will return the results of the grids for a given platform
+// (e.g. "steam", "egs", "gog") and the app ID on that platform.
+func (c *Client) GetGridsByPlatform(platform, platformID string, filters ...FilterGrid) (*GridResponse, error) {
+	return c.getGrids("/grids/"+platform+"/"+platformID, filters...)
+}
+
+func (c *Client) getGrids(path string, filters ...FilterGrid) (*GridResponse, error) {
+	res, err := c.Get(c.withContentPolicy(path))
 	if err != nil {
 		return nil, err
 	}
@@ -156,7 +196,17 @@ func (c *Client) GetGrids(gameID string, filters ...FilterGrid) (*GridResponse,
 
 // GetHeroes will return the results of heroes for a given game ID
 func (c *Client) GetHeroes(gameID string, filters ...FilterHeroes) (*HeroesResponse, error) {
-	res, err := c.Get("/heroes/game/" + gameID)
+	return c.getHeroes("/heroes/game/"+gameID, filters...)
+}
+
+// GetHeroesByPlatform will return the results of heroes for a given platform
+// (e.g. "steam", "egs", "gog") and the app ID on that platform.
+func (c *Client) GetHeroesByPlatform(platform, platformID string, filters ...FilterHeroes) (*HeroesResponse, error) {
+	return c.getHeroes("/heroes/"+platform+"/"+platformID, filters...)
+}
+
+func (c *Client) getHeroes(path string, filters ...FilterHeroes) (*HeroesResponse, error) {
+	res, err := c.Get(c.withContentPolicy(path))
 	if err != nil {
 		return nil, err
 	}
@@ -185,7 +235,17 @@ func (c *Client) GetHeroes(gameID string, filters ...FilterHeroes) (*HeroesRespo
 
 // GetLogos will return the results of logos for a given game ID
 func (c *Client) GetLogos(gameID string, filters ...FilterLogos) (*LogosResponse, error) {
-	res, err := c.Get("/logos/game/" + gameID)
+	return c.getLogos("/logos/game/"+gameID, filters...)
+}
+
+// GetLogosByPlatform will return the results of logos for a given platform
+// (e.g. "steam", "egs", "gog") and the app ID on that platform.
+func (c *Client) GetLogosByPlatform(platform, platformID string, filters ...FilterLogos) (*LogosResponse, error) {
+	return c.getLogos("/logos/"+platform+"/"+platformID, filters...)
+}
+
+func (c *Client) getLogos(path string, filters ...FilterLogos) (*LogosResponse, error) {
+	res, err := c.Get(c.withContentPolicy(path))
 	if err != nil {
 		return nil, err
 	}
@@ -215,7 +275,17 @@ func (c *Client) GetLogos(gameID string, filters ...FilterLogos) (*LogosResponse
 
 // GetIcons will return the results of icons for a given game ID
 func (c *Client) GetIcons(gameID string, filters ...FilterIcons) (*IconsResponse, error) {
-	res, err := c.Get("/icons/game/" + gameID)
+	return c.getIcons("/icons/game/"+gameID, filters...)
+}
+
+// GetIconsByPlatform will return the results of icons for a given platform
+// (e.g. "steam", "egs", "gog") and the app ID on that platform.
+func (c *Client) GetIconsByPlatform(platform, platformID string, filters ...FilterIcons) (*IconsResponse, error) {
+	return c.getIcons("/icons/"+platform+"/"+platformID, filters...)
+}
+
+func (c *Client) getIcons(path string, filters ...FilterIcons) (*IconsResponse, error) {
+	res, err := c.Get(c.withContentPolicy(path))
 	if err != nil {
 		return nil, err
 	}
@@ -59,6 +59,8 @@ type ImageResponseData struct {
 	ID     int      `json:"id"`
 	Score  int      `json:"score"`
 	Style  string   `json:"style"`
+	Nsfw   bool     `json:"nsfw"`
+	Humor  bool     `json:"humor"`
 	URL    string   `json:"url"`
 	Thumb  string   `json:"thumb"`
 	Tags   []string `json:"tags"`
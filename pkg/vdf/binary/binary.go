@@ -0,0 +1,180 @@
+package binary
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/vdf/text"
+)
+
+// Value types of binary KeyValues
+const (
+	TypeObject  byte = 0x00
+	TypeString  byte = 0x01
+	TypeInt32   byte = 0x02
+	TypeFloat32 byte = 0x03
+	TypePointer byte = 0x04
+	TypeWString byte = 0x05
+	TypeColor   byte = 0x06
+	TypeUint64  byte = 0x07
+	TypeEnd     byte = 0x08
+	TypeInt64   byte = 0x0A
+	TypeEndAlt  byte = 0x0B
+)
+
+// Decoder reads binary KeyValues, as used in appinfo.vdf and packageinfo.vdf.
+// Numbers are converted to their decimal string form so the result can be
+// handled like a text KeyValues document.
+type Decoder struct {
+	r *bufio.Reader
+	// StringTable holds the keys of newer formats, where each key is stored
+	// as an index into a table at the end of the file instead of inline.
+	StringTable []string
+}
+
+// NewDecoder will return a new decoder that reads from the given reader
+func NewDecoder(r io.Reader) *Decoder {
+	if br, ok := r.(*bufio.Reader); ok {
+		return &Decoder{r: br}
+	}
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// Decode will read a binary KeyValues object up to and including its end
+// marker and return it as a document root.
+func (d *Decoder) Decode() (*text.Node, error) {
+	root := text.NewDocument()
+	err := d.decodeChildren(root)
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// decodeChildren will read values into the given parent until an end marker
+func (d *Decoder) decodeChildren(parent *text.Node) error {
+	for {
+		kind, err := d.r.ReadByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		if kind == TypeEnd || kind == TypeEndAlt {
+			return nil
+		}
+		key, err := d.readKey()
+		if err != nil {
+			return err
+		}
+
+		var value string
+		switch kind {
+		case TypeObject:
+			child := text.NewObject(key)
+			err := d.decodeChildren(child)
+			if err != nil {
+				return err
+			}
+			parent.Append(child)
+			continue
+		case TypeString:
+			value, err = d.readString()
+		case TypeWString:
+			value, err = d.readWideString()
+		case TypeInt32, TypePointer, TypeColor:
+			var v int32
+			err = binary.Read(d.r, binary.LittleEndian, &v)
+			value = strconv.FormatInt(int64(v), 10)
+		case TypeFloat32:
+			var v uint32
+			err = binary.Read(d.r, binary.LittleEndian, &v)
+			value = strconv.FormatFloat(float64(math.Float32frombits(v)), 'f', -1, 32)
+		case TypeUint64:
+			var v uint64
+			err = binary.Read(d.r, binary.LittleEndian, &v)
+			value = strconv.FormatUint(v, 10)
+		case TypeInt64:
+			var v int64
+			err = binary.Read(d.r, binary.LittleEndian, &v)
+			value = strconv.FormatInt(v, 10)
+		default:
+			return fmt.Errorf("unknown value type 0x%02x for key %q", kind, key)
+		}
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		parent.Append(text.NewString(key, value))
+	}
+}
+
+// readKey will read a key, either inline or from the string table
+func (d *Decoder) readKey() (string, error) {
+	if d.StringTable == nil {
+		return d.readString()
+	}
+	var index uint32
+	err := binary.Read(d.r, binary.LittleEndian, &index)
+	if err != nil {
+		return "", unexpectedEOF(err)
+	}
+	if int(index) >= len(d.StringTable) {
+		return "", fmt.Errorf("string table index %v out of range", index)
+	}
+	return d.StringTable[index], nil
+}
+
+// readString will read a null-terminated UTF-8 string
+func (d *Decoder) readString() (string, error) {
+	data, err := d.r.ReadBytes(0)
+	if err != nil {
+		return "", unexpectedEOF(err)
+	}
+	return string(data[:len(data)-1]), nil
+}
+
+// readWideString will read a null-terminated UTF-16 string
+func (d *Decoder) readWideString() (string, error) {
+	chars := []uint16{}
+	for {
+		var c uint16
+		err := binary.Read(d.r, binary.LittleEndian, &c)
+		if err != nil {
+			return "", unexpectedEOF(err)
+		}
+		if c == 0 {
+			return string(utf16.Decode(chars)), nil
+		}
+		chars = append(chars, c)
+	}
+}
+
+// ReadStringTable will read a string table: a count followed by that many
+// null-terminated strings.
+func ReadStringTable(r io.Reader) ([]string, error) {
+	br := bufio.NewReader(r)
+	var count uint32
+	err := binary.Read(br, binary.LittleEndian, &count)
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	table := make([]string, 0, count)
+	for i := uint32(0); i < count; i++ {
+		data, err := br.ReadBytes(0)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		table = append(table, string(data[:len(data)-1]))
+	}
+	return table, nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
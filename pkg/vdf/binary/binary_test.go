@@ -0,0 +1,171 @@
+package binary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/vdf/text"
+)
+
+// builder writes binary KeyValues for tests, with keys either inline or as
+// indexes into a string table.
+type builder struct {
+	buf     bytes.Buffer
+	indexed bool
+	table   []string
+}
+
+func (b *builder) key(kind byte, key string) *builder {
+	b.buf.WriteByte(kind)
+	if !b.indexed {
+		b.buf.WriteString(key + "\x00")
+		return b
+	}
+	for i, s := range b.table {
+		if s == key {
+			binary.Write(&b.buf, binary.LittleEndian, uint32(i))
+			return b
+		}
+	}
+	binary.Write(&b.buf, binary.LittleEndian, uint32(len(b.table)))
+	b.table = append(b.table, key)
+	return b
+}
+
+func (b *builder) object(key string) *builder {
+	return b.key(TypeObject, key)
+}
+
+func (b *builder) str(key, value string) *builder {
+	b.key(TypeString, key)
+	b.buf.WriteString(value + "\x00")
+	return b
+}
+
+func (b *builder) number(kind byte, key string, value interface{}) *builder {
+	b.key(kind, key)
+	binary.Write(&b.buf, binary.LittleEndian, value)
+	return b
+}
+
+func (b *builder) end() *builder {
+	b.buf.WriteByte(TypeEnd)
+	return b
+}
+
+// sample will write an object with every value type
+func sample(b *builder) *builder {
+	b.object("appinfo").
+		str("name", "Portal 2").
+		number(TypeInt32, "int", int32(-5)).
+		number(TypeFloat32, "float", math.Float32bits(1.5)).
+		number(TypePointer, "pointer", int32(7)).
+		number(TypeColor, "color", int32(255)).
+		number(TypeUint64, "uint64", uint64(math.MaxUint64)).
+		number(TypeInt64, "int64", int64(math.MinInt64))
+	b.key(TypeWString, "wide")
+	for _, c := range []uint16{'h', 0xe9, 0} {
+		binary.Write(&b.buf, binary.LittleEndian, c)
+	}
+	b.object("common").str("type", "Game").end()
+	b.end()
+	return b.end()
+}
+
+func checkSample(t *testing.T, root *text.Node) {
+	t.Helper()
+	tests := []struct {
+		path []string
+		want string
+	}{
+		{[]string{"appinfo", "name"}, "Portal 2"},
+		{[]string{"appinfo", "int"}, "-5"},
+		{[]string{"appinfo", "float"}, "1.5"},
+		{[]string{"appinfo", "pointer"}, "7"},
+		{[]string{"appinfo", "color"}, "255"},
+		{[]string{"appinfo", "uint64"}, "18446744073709551615"},
+		{[]string{"appinfo", "int64"}, "-9223372036854775808"},
+		{[]string{"appinfo", "wide"}, "hé"},
+		{[]string{"appinfo", "common", "type"}, "Game"},
+	}
+	for _, tt := range tests {
+		got, ok := root.GetString(tt.path...)
+		if !ok || got != tt.want {
+			t.Errorf("%v: got %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	b := sample(&builder{})
+	root, err := NewDecoder(&b.buf).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSample(t, root)
+}
+
+func TestDecodeStringTable(t *testing.T) {
+	b := sample(&builder{indexed: true})
+	decoder := NewDecoder(&b.buf)
+	decoder.StringTable = b.table
+	root, err := decoder.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSample(t, root)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	complete := sample(&builder{}).buf.Bytes()
+	indexed := sample(&builder{indexed: true})
+
+	tests := []struct {
+		name  string
+		data  []byte
+		table []string
+		want  string
+	}{
+		{"truncated", complete[:len(complete)-3], nil, io.ErrUnexpectedEOF.Error()},
+		{"truncated key", []byte{TypeString, 'a'}, nil, io.ErrUnexpectedEOF.Error()},
+		{"truncated number", []byte{TypeInt32, 'a', 0, 1}, nil, io.ErrUnexpectedEOF.Error()},
+		{"unknown type", []byte{0x09, 'a', 0}, nil, "unknown value type 0x09"},
+		{"string index out of range", indexed.buf.Bytes(), indexed.table[:2], "out of range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := NewDecoder(bytes.NewReader(tt.data))
+			decoder.StringTable = tt.table
+			_, err := decoder.Decode()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestReadStringTable(t *testing.T) {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(2))
+	buf.WriteString("appinfo\x00name\x00")
+	table, err := ReadStringTable(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table) != 2 || table[0] != "appinfo" || table[1] != "name" {
+		t.Errorf("unexpected string table: %v", table)
+	}
+
+	buf.Reset()
+	binary.Write(&buf, binary.LittleEndian, uint32(2))
+	buf.WriteString("appinfo\x00")
+	_, err = ReadStringTable(&buf)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected unexpected EOF, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+// EvalCondition will evaluate a KeyValues conditional such as "$WIN32",
+// "!$X360" or "$WIN32 || $OSX" with the given conditions defined. Conditions
+// are case-insensitive and may be given with or without the leading '$'.
+// Malformed conditionals evaluate to false.
+func EvalCondition(condition string, defines ...string) bool {
+	defined := map[string]bool{}
+	for _, define := range defines {
+		defined[strings.ToUpper(strings.TrimPrefix(define, "$"))] = true
+	}
+	e := &conditionEval{input: condition, defined: defined}
+	result := e.or()
+	e.skipSpace()
+	if e.failed || e.pos < len(e.input) {
+		return false
+	}
+	return result
+}
+
+// conditionEval is a recursive descent evaluator for conditionals
+type conditionEval struct {
+	input   string
+	pos     int
+	defined map[string]bool
+	failed  bool
+}
+
+func (e *conditionEval) or() bool {
+	result := e.and()
+	for e.consume("||") {
+		// Evaluate both sides so the whole input is consumed
+		right := e.and()
+		result = result || right
+	}
+	return result
+}
+
+func (e *conditionEval) and() bool {
+	result := e.unary()
+	for e.consume("&&") {
+		right := e.unary()
+		result = result && right
+	}
+	return result
+}
+
+func (e *conditionEval) unary() bool {
+	if e.consume("!") {
+		return !e.unary()
+	}
+	if e.consume("(") {
+		result := e.or()
+		if !e.consume(")") {
+			e.failed = true
+		}
+		return result
+	}
+	if !e.consume("$") {
+		e.failed = true
+		return false
+	}
+	start := e.pos
+	for e.pos < len(e.input) {
+		c := rune(e.input[e.pos])
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' {
+			break
+		}
+		e.pos++
+	}
+	if start == e.pos {
+		e.failed = true
+		return false
+	}
+	return e.defined[strings.ToUpper(e.input[start:e.pos])]
+}
+
+// consume will skip whitespace and the given operator if it is next
+func (e *conditionEval) consume(op string) bool {
+	e.skipSpace()
+	if strings.HasPrefix(e.input[e.pos:], op) {
+		e.pos += len(op)
+		return true
+	}
+	return false
+}
+
+func (e *conditionEval) skipSpace() {
+	for e.pos < len(e.input) && (e.input[e.pos] == ' ' || e.input[e.pos] == '\t') {
+		e.pos++
+	}
+}
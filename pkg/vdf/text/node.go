@@ -0,0 +1,167 @@
+package text
+
+import "strings"
+
+// Node is a single key in a KeyValues document. Objects have a non-nil list of
+// children, while strings have a value. The root node of a document is an
+// object without a key whose children are the top-level keys.
+type Node struct {
+	Key      string
+	Value    string
+	Children []*Node
+	// Condition is the platform conditional of the node without brackets,
+	// e.g. "$WIN32" or "!$X360"
+	Condition string
+	// Comments are the comments on the lines before the node's key
+	Comments []string
+
+	// format holds the tokens the node was parsed from so unchanged parts
+	// are written back exactly as they were read.
+	format *nodeFormat
+}
+
+// nodeFormat is the original formatting of a parsed node
+type nodeFormat struct {
+	key       *Token
+	value     *Token
+	open      *Token
+	close     *Token
+	condition *Token
+	comments  []string
+	object    bool
+	// conditionBeforeOpen is whether or not the conditional of an object
+	// came before its '{' rather than after its '}'
+	conditionBeforeOpen bool
+}
+
+// NewDocument will return a new, empty document root
+func NewDocument() *Node {
+	return &Node{Children: []*Node{}}
+}
+
+// NewObject will return a new object node with the given key
+func NewObject(key string) *Node {
+	return &Node{Key: key, Children: []*Node{}}
+}
+
+// NewString will return a new string node with the given key and value
+func NewString(key, value string) *Node {
+	return &Node{Key: key, Value: value}
+}
+
+// IsObject will return whether or not the node contains other nodes
+func (n *Node) IsObject() bool {
+	return n.Children != nil
+}
+
+// Child will return the first child with the given key or nil if it does not
+// exist. Keys are case-insensitive, as they are in Steam.
+func (n *Node) Child(key string) *Node {
+	for _, child := range n.Children {
+		if strings.EqualFold(child.Key, key) {
+			return child
+		}
+	}
+	return nil
+}
+
+// Lookup will return the node at the given path of keys or nil if it does not
+// exist.
+func (n *Node) Lookup(keys ...string) *Node {
+	node := n
+	for _, key := range keys {
+		node = node.Child(key)
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// GetString will return the value of the string node at the given path of
+// keys. Returns false if it does not exist or is an object.
+func (n *Node) GetString(keys ...string) (string, bool) {
+	node := n.Lookup(keys...)
+	if node == nil || node.IsObject() {
+		return "", false
+	}
+	return node.Value, true
+}
+
+// EnsureObject will return the object node at the given path of keys,
+// creating any missing nodes. String nodes along the path are replaced with
+// objects.
+func (n *Node) EnsureObject(keys ...string) *Node {
+	node := n
+	for _, key := range keys {
+		next := node.Child(key)
+		if next == nil {
+			next = NewObject(key)
+			node.Append(next)
+		}
+		if !next.IsObject() {
+			next.Value = ""
+			next.Children = []*Node{}
+		}
+		node = next
+	}
+	return node
+}
+
+// Set will set the value of the child with the given key, keeping its
+// position if it already exists. Returns the child.
+func (n *Node) Set(key, value string) *Node {
+	child := n.Child(key)
+	if child == nil {
+		child = NewString(key, value)
+		n.Append(child)
+		return child
+	}
+	child.Value = value
+	child.Children = nil
+	return child
+}
+
+// Append will add the given child to the end of the node
+func (n *Node) Append(child *Node) {
+	if n.Children == nil {
+		n.Children = []*Node{}
+	}
+	n.Children = append(n.Children, child)
+}
+
+// Remove will remove the first child with the given key. Returns whether or
+// not the child existed.
+func (n *Node) Remove(key string) bool {
+	for i, child := range n.Children {
+		if strings.EqualFold(child.Key, key) {
+			n.Children = append(n.Children[:i], n.Children[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Matches will return whether or not the node's conditional is true when the
+// given conditions (e.g. "$LINUX") are defined. Nodes without a conditional
+// always match.
+func (n *Node) Matches(defines ...string) bool {
+	if n.Condition == "" {
+		return true
+	}
+	return EvalCondition(n.Condition, defines...)
+}
+
+// ToMap will convert the children of the node into nested maps of strings.
+// Duplicate keys will use the last value found.
+func (n *Node) ToMap() map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, child := range n.Children {
+		if child.IsObject() {
+			result[child.Key] = child.ToMap()
+			continue
+		}
+		result[child.Key] = child.Value
+	}
+	return result
+}
@@ -0,0 +1,161 @@
+package text
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Parse will parse a KeyValues document from the given reader and return its
+// root node.
+func Parse(r io.Reader) (*Node, error) {
+	p := &parser{tokenizer: NewTokenizer(r)}
+	root := NewDocument()
+	root.format = &nodeFormat{object: true}
+	err := p.parseChildren(root, false)
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// ParseBytes will parse the given KeyValues document and return its root node
+func ParseBytes(data []byte) (*Node, error) {
+	return Parse(bytes.NewReader(data))
+}
+
+// ReadFile will read and parse the given KeyValues file
+func ReadFile(file string) (*Node, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	root, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %v: %w", file, err)
+	}
+	return root, nil
+}
+
+// parser builds nodes from the tokens of a document
+type parser struct {
+	tokenizer *Tokenizer
+	peeked    *Token
+}
+
+func (p *parser) next() (*Token, error) {
+	if p.peeked != nil {
+		token := p.peeked
+		p.peeked = nil
+		return token, nil
+	}
+	return p.tokenizer.Next()
+}
+
+func (p *parser) peek() (*Token, error) {
+	if p.peeked == nil {
+		token, err := p.tokenizer.Next()
+		if err != nil {
+			return nil, err
+		}
+		p.peeked = token
+	}
+	return p.peeked, nil
+}
+
+// parseChildren will parse nodes into the given parent until its closing
+// brace, or the end of the document for the root.
+func (p *parser) parseChildren(parent *Node, nested bool) error {
+	for {
+		token, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch token.Type {
+		case TokenEOF:
+			if nested {
+				return fmt.Errorf("unexpected end of document, expected '}'")
+			}
+			parent.format.close = token
+			return nil
+		case TokenClose:
+			if !nested {
+				return unexpected(token)
+			}
+			parent.format.close = token
+			return nil
+		case TokenString:
+			node, err := p.parseNode(token)
+			if err != nil {
+				return err
+			}
+			parent.Append(node)
+		default:
+			return unexpected(token)
+		}
+	}
+}
+
+// parseNode will parse the value of the node with the given key
+func (p *parser) parseNode(key *Token) (*Node, error) {
+	comments := key.Comments()
+	node := &Node{Key: key.Value, Comments: comments}
+	node.format = &nodeFormat{key: key, comments: comments}
+
+	token, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	// Objects can have their conditional before the '{'
+	if token.Type == TokenConditional {
+		node.Condition = token.Value
+		node.format.condition = token
+		node.format.conditionBeforeOpen = true
+		token, err = p.next()
+		if err != nil {
+			return nil, err
+		}
+		if token.Type != TokenOpen {
+			return nil, unexpected(token)
+		}
+	}
+
+	switch token.Type {
+	case TokenString:
+		node.Value = token.Value
+		node.format.value = token
+	case TokenOpen:
+		node.Children = []*Node{}
+		node.format.open = token
+		node.format.object = true
+		err := p.parseChildren(node, true)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("line %v, column %v: missing value for key %q", token.Line, token.Column, key.Value)
+	}
+
+	// Conditionals usually follow the value
+	if node.format.condition == nil {
+		next, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if next.Type == TokenConditional {
+			p.peeked = nil
+			node.Condition = next.Value
+			node.format.condition = next
+		}
+	}
+
+	return node, nil
+}
+
+// unexpected will return an error for an unexpected token
+func unexpected(token *Token) error {
+	return fmt.Errorf("line %v, column %v: unexpected %v", token.Line, token.Column, token.Type)
+}
@@ -0,0 +1,287 @@
+package text
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// document is a KeyValues document with comments, escapes, conditionals and
+// inconsistent formatting that must be written back unchanged.
+const document = `// Steam config
+"InstallConfigStore"
+{
+	"Software"
+	{
+		// Compat tools
+		"CompatToolMapping"
+		{
+			"3810698013"
+			{
+				"name"		"proton_experimental"
+				"config"		""
+				"priority"		"250"
+			}
+		}
+		"Path"	"C:\\Games\\\"Steam\""
+		"Multi"		"line\none\ttab"
+	}
+	unquoted value
+	"platform"	"windows"	[$WIN32]
+	"platform"	"linux"	[$LINUX]
+	"console" [!$X360 && !$PS3]
+	{
+		"enabled"	"1"
+	}
+}
+`
+
+func parse(t *testing.T, doc string) *Node {
+	t.Helper()
+	root, err := ParseBytes([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestRoundTrip(t *testing.T) {
+	root := parse(t, document)
+	if got := string(Marshal(root)); got != document {
+		t.Errorf("document changed after round trip:\n%v", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	root := parse(t, document)
+	store := root.Child("installconfigstore")
+	if store == nil || !store.IsObject() {
+		t.Fatal("expected InstallConfigStore object")
+	}
+	if !reflect.DeepEqual(store.Comments, []string{"Steam config"}) {
+		t.Errorf("unexpected comments: %v", store.Comments)
+	}
+
+	tests := []struct {
+		path []string
+		want string
+	}{
+		{[]string{"Software", "CompatToolMapping", "3810698013", "name"}, "proton_experimental"},
+		{[]string{"Software", "CompatToolMapping", "3810698013", "config"}, ""},
+		{[]string{"Software", "Path"}, `C:\Games\"Steam"`},
+		{[]string{"Software", "Multi"}, "line\none\ttab"},
+		{[]string{"unquoted"}, "value"},
+	}
+	for _, tt := range tests {
+		got, ok := store.GetString(tt.path...)
+		if !ok || got != tt.want {
+			t.Errorf("%v: got %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	mapping := store.Lookup("Software", "CompatToolMapping")
+	if !reflect.DeepEqual(mapping.Comments, []string{"Compat tools"}) {
+		t.Errorf("unexpected comments: %v", mapping.Comments)
+	}
+	if _, ok := store.GetString("Software"); ok {
+		t.Error("expected objects not to be returned as strings")
+	}
+}
+
+func TestConditionals(t *testing.T) {
+	root := parse(t, document)
+	store := root.Child("InstallConfigStore")
+
+	platforms := map[string]string{}
+	for _, child := range store.Children {
+		if child.Key == "platform" && child.Matches("$LINUX") {
+			platforms["linux"] = child.Value
+		}
+		if child.Key == "platform" && child.Matches("WIN32") {
+			platforms["win32"] = child.Value
+		}
+	}
+	want := map[string]string{"linux": "linux", "win32": "windows"}
+	if !reflect.DeepEqual(platforms, want) {
+		t.Errorf("unexpected platforms: %v", platforms)
+	}
+
+	console := store.Child("console")
+	if console.Condition != "!$X360 && !$PS3" || !console.IsObject() {
+		t.Errorf("unexpected console node: %+v", console)
+	}
+	if !console.Matches("$LINUX") || console.Matches("$PS3") {
+		t.Error("unexpected result for console conditional")
+	}
+}
+
+func TestEvalCondition(t *testing.T) {
+	tests := []struct {
+		condition string
+		defines   []string
+		want      bool
+	}{
+		{"$WIN32", []string{"$WIN32"}, true},
+		{"$win32", []string{"WIN32"}, true},
+		{"$WIN32", []string{"$LINUX"}, false},
+		{"!$X360", nil, true},
+		{"$WIN32 || $OSX", []string{"$OSX"}, true},
+		{"$WIN32 && $OSX", []string{"$OSX"}, false},
+		{"!($WIN32 || $OSX)", []string{"$LINUX"}, true},
+		{"$LINUX && !($X360 || $PS3)", []string{"$LINUX"}, true},
+		{"($WIN32", []string{"$WIN32"}, false},
+		{"WIN32", []string{"$WIN32"}, false},
+		{"$WIN32 garbage", []string{"$WIN32"}, false},
+		{"", nil, false},
+	}
+	for _, tt := range tests {
+		if got := EvalCondition(tt.condition, tt.defines...); got != tt.want {
+			t.Errorf("EvalCondition(%q, %v) = %v, want %v", tt.condition, tt.defines, got, tt.want)
+		}
+	}
+}
+
+func TestEdit(t *testing.T) {
+	root := parse(t, document)
+	mapping := root.EnsureObject("InstallConfigStore", "Software", "CompatToolMapping")
+
+	// Changing a value keeps the formatting around it
+	mapping.Child("3810698013").Set("name", "proton_8")
+
+	// New keys are written in Steam's style
+	entry := mapping.EnsureObject("123")
+	entry.Set("name", `quote " and \`)
+	entry.Comments = []string{"Added"}
+
+	// Removed keys are dropped with their formatting
+	root.Child("InstallConfigStore").Remove("console")
+
+	want := `// Steam config
+"InstallConfigStore"
+{
+	"Software"
+	{
+		// Compat tools
+		"CompatToolMapping"
+		{
+			"3810698013"
+			{
+				"name"		"proton_8"
+				"config"		""
+				"priority"		"250"
+			}
+			// Added
+			"123"
+			{
+				"name"		"quote \" and \\"
+			}
+		}
+		"Path"	"C:\\Games\\\"Steam\""
+		"Multi"		"line\none\ttab"
+	}
+	unquoted value
+	"platform"	"windows"	[$WIN32]
+	"platform"	"linux"	[$LINUX]
+}
+`
+	got := string(Marshal(root))
+	if got != want {
+		t.Errorf("unexpected document:\n%v", got)
+	}
+
+	// The edited document can be parsed again
+	value, _ := parse(t, got).GetString("InstallConfigStore", "Software", "CompatToolMapping", "123", "name")
+	if value != `quote " and \` {
+		t.Errorf("unexpected value after round trip: %q", value)
+	}
+}
+
+func TestNewDocument(t *testing.T) {
+	root := NewDocument()
+	apps := root.EnsureObject("UserLocalConfigStore", "Software", "Valve", "Steam", "apps")
+	apps.EnsureObject("620").Set("LaunchOptions", "gamemoderun %command%")
+	root.Child("UserLocalConfigStore").Append(&Node{Key: "platform", Value: "linux", Condition: "$LINUX"})
+
+	want := `"UserLocalConfigStore"
+{
+	"Software"
+	{
+		"Valve"
+		{
+			"Steam"
+			{
+				"apps"
+				{
+					"620"
+					{
+						"LaunchOptions"		"gamemoderun %command%"
+					}
+				}
+			}
+		}
+	}
+	"platform"		"linux" [$LINUX]
+}
+`
+	if got := string(Marshal(root)); got != want {
+		t.Errorf("unexpected document:\n%v", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"unclosed object":      "\"a\"\n{\n\t\"b\"\t\"c\"\n",
+		"unterminated string":  "\"a\"\t\"b",
+		"unterminated escape":  "\"a\"\t\"b\\",
+		"missing value":        "\"a\"",
+		"missing nested value": "\"a\"\n{\n\t\"b\"\n}\n",
+		"unexpected close":     "}",
+		"unterminated cond":    "\"a\"\t\"b\"\t[$WIN32\n",
+		"cond without object":  "\"a\" [$WIN32] \"b\"",
+	}
+	for name, doc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseBytes([]byte(doc))
+			if err == nil {
+				t.Errorf("expected an error for %q", doc)
+			}
+		})
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.vdf")
+	err := os.WriteFile(file, []byte(document), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.Child("InstallConfigStore").Set("new", "1")
+	err = WriteFile(file, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected file mode to be kept, got %v", info.Mode().Perm())
+	}
+	root, err = ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := root.GetString("InstallConfigStore", "new"); value != "1" {
+		t.Errorf("unexpected value: %q", value)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(file))
+	if len(entries) != 1 {
+		t.Errorf("expected temporary files to be removed, found %v files", len(entries))
+	}
+}
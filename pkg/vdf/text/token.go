@@ -0,0 +1,305 @@
+package text
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TokenType is the kind of a KeyValues token
+type TokenType int
+
+const (
+	// TokenEOF is the end of the input. Its leading trivia holds anything
+	// after the last token.
+	TokenEOF TokenType = iota
+	// TokenString is a quoted or unquoted key or value
+	TokenString
+	// TokenOpen is the '{' that starts an object
+	TokenOpen
+	// TokenClose is the '}' that ends an object
+	TokenClose
+	// TokenConditional is a platform conditional such as [$WIN32]
+	TokenConditional
+)
+
+// String will return the name of the token type
+func (t TokenType) String() string {
+	switch t {
+	case TokenEOF:
+		return "EOF"
+	case TokenString:
+		return "string"
+	case TokenOpen:
+		return "'{'"
+	case TokenClose:
+		return "'}'"
+	case TokenConditional:
+		return "conditional"
+	}
+	return fmt.Sprintf("TokenType(%d)", int(t))
+}
+
+// Token is a single token in a KeyValues document. Whitespace and comments are
+// not tokens of their own; they are kept as the leading trivia of the token
+// that follows them so documents can be written back unchanged.
+type Token struct {
+	Type TokenType
+	// Value is the unescaped string or the condition without brackets
+	Value string
+	// Raw is the token exactly as it appeared in the input
+	Raw string
+	// Quoted is whether or not a string token was quoted
+	Quoted bool
+	// Leading is the whitespace and comments before the token
+	Leading string
+	Line    int
+	Column  int
+}
+
+// Comments will return the text of the comments in the token's leading
+// trivia, without the "//" markers.
+func (t *Token) Comments() []string {
+	return parseComments(t.Leading)
+}
+
+// Tokenizer reads KeyValues tokens from a stream
+type Tokenizer struct {
+	r      *bufio.Reader
+	line   int
+	column int
+	done   bool
+
+	// position before the last rune read, used by unread
+	lastLine   int
+	lastColumn int
+}
+
+// NewTokenizer will return a new tokenizer that reads from the given reader
+func NewTokenizer(r io.Reader) *Tokenizer {
+	return &Tokenizer{r: bufio.NewReader(r), line: 1, column: 1}
+}
+
+// Next will return the next token. A TokenEOF token is returned at the end of
+// the input, followed by io.EOF.
+func (t *Tokenizer) Next() (*Token, error) {
+	if t.done {
+		return nil, io.EOF
+	}
+	leading, err := t.readTrivia()
+	if err != nil {
+		return nil, err
+	}
+	token := &Token{Leading: leading, Line: t.line, Column: t.column}
+
+	c, err := t.read()
+	if err == io.EOF {
+		t.done = true
+		token.Type = TokenEOF
+		return token, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch c {
+	case '{':
+		token.Type = TokenOpen
+		token.Raw = "{"
+	case '}':
+		token.Type = TokenClose
+		token.Raw = "}"
+	case '"':
+		token.Type = TokenString
+		token.Quoted = true
+		token.Value, token.Raw, err = t.readQuoted()
+	case '[':
+		token.Type = TokenConditional
+		token.Value, token.Raw, err = t.readConditional()
+	default:
+		t.unread()
+		token.Type = TokenString
+		token.Value, err = t.readUnquoted()
+		token.Raw = token.Value
+	}
+	if err != nil {
+		return nil, fmt.Errorf("line %v, column %v: %w", token.Line, token.Column, err)
+	}
+
+	return token, nil
+}
+
+// readTrivia will read whitespace and comments
+func (t *Tokenizer) readTrivia() (string, error) {
+	var sb strings.Builder
+	for {
+		c, err := t.read()
+		if err == io.EOF {
+			return sb.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			sb.WriteRune(c)
+		case c == '/':
+			next, err := t.peek()
+			if err != nil || next != '/' {
+				t.unread()
+				return sb.String(), nil
+			}
+			sb.WriteRune(c)
+			for {
+				c, err := t.read()
+				if err == io.EOF {
+					return sb.String(), nil
+				}
+				if err != nil {
+					return "", err
+				}
+				if c == '\n' {
+					t.unread()
+					break
+				}
+				sb.WriteRune(c)
+			}
+		default:
+			t.unread()
+			return sb.String(), nil
+		}
+	}
+}
+
+// readQuoted will read the rest of a quoted string after the opening quote
+func (t *Tokenizer) readQuoted() (value, raw string, err error) {
+	var val, rawText strings.Builder
+	rawText.WriteRune('"')
+	for {
+		c, err := t.read()
+		if err == io.EOF {
+			return "", "", fmt.Errorf("unterminated string")
+		}
+		if err != nil {
+			return "", "", err
+		}
+		rawText.WriteRune(c)
+		switch c {
+		case '"':
+			return val.String(), rawText.String(), nil
+		case '\\':
+			next, err := t.read()
+			if err == io.EOF {
+				return "", "", fmt.Errorf("unterminated string")
+			}
+			if err != nil {
+				return "", "", err
+			}
+			rawText.WriteRune(next)
+			switch next {
+			case 'n':
+				val.WriteRune('\n')
+			case 't':
+				val.WriteRune('\t')
+			case '\\', '"':
+				val.WriteRune(next)
+			default:
+				val.WriteRune('\\')
+				val.WriteRune(next)
+			}
+		default:
+			val.WriteRune(c)
+		}
+	}
+}
+
+// readUnquoted will read an unquoted string
+func (t *Tokenizer) readUnquoted() (string, error) {
+	var sb strings.Builder
+	for {
+		c, err := t.read()
+		if err == io.EOF {
+			return sb.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if isDelimiter(c) {
+			t.unread()
+			return sb.String(), nil
+		}
+		sb.WriteRune(c)
+	}
+}
+
+// readConditional will read the rest of a conditional after the '['
+func (t *Tokenizer) readConditional() (value, raw string, err error) {
+	var sb strings.Builder
+	for {
+		c, err := t.read()
+		if err == io.EOF || c == '\n' {
+			return "", "", fmt.Errorf("unterminated conditional")
+		}
+		if err != nil {
+			return "", "", err
+		}
+		if c == ']' {
+			return strings.TrimSpace(sb.String()), "[" + sb.String() + "]", nil
+		}
+		sb.WriteRune(c)
+	}
+}
+
+func (t *Tokenizer) read() (rune, error) {
+	c, _, err := t.r.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	t.lastLine, t.lastColumn = t.line, t.column
+	if c == '\n' {
+		t.line++
+		t.column = 1
+	} else {
+		t.column++
+	}
+	return c, nil
+}
+
+// unread will push back the last rune read
+func (t *Tokenizer) unread() {
+	if t.r.UnreadRune() == nil {
+		t.line, t.column = t.lastLine, t.lastColumn
+	}
+}
+
+func (t *Tokenizer) peek() (rune, error) {
+	c, _, err := t.r.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	return c, t.r.UnreadRune()
+}
+
+// isDelimiter will return whether or not the given rune ends an unquoted
+// string
+func isDelimiter(c rune) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '{', '}', '"':
+		return true
+	}
+	return false
+}
+
+// parseComments will return the text of the comments in the given trivia
+func parseComments(trivia string) []string {
+	comments := []string{}
+	for _, line := range strings.Split(trivia, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "//") {
+			continue
+		}
+		comments = append(comments, strings.TrimSpace(strings.TrimPrefix(line, "//")))
+	}
+	return comments
+}
@@ -0,0 +1,139 @@
+package text
+
+import (
+	"io"
+	"strings"
+
+	"github.com/shadowblip/steam-shortcut-manager/pkg/fileutil"
+)
+
+// Marshal will return the KeyValues text of the given document root. Parts of
+// the document that were parsed and not changed are written exactly as they
+// were read, including whitespace and comments. New nodes are written in the
+// tab-indented style that Steam uses.
+func Marshal(root *Node) []byte {
+	var sb strings.Builder
+	for _, child := range root.Children {
+		writeNode(&sb, child, 0)
+	}
+	if root.format != nil && root.format.close != nil {
+		sb.WriteString(root.format.close.Leading)
+	} else {
+		sb.WriteString("\n")
+	}
+	return []byte(sb.String())
+}
+
+// Write will write the KeyValues text of the given document root to the given
+// writer.
+func Write(w io.Writer, root *Node) error {
+	_, err := w.Write(Marshal(root))
+	return err
+}
+
+// WriteFile will write the given document root to the given file. The
+// document is written to a temporary file first and renamed so that readers
+// never see a partially written file.
+func WriteFile(file string, root *Node) error {
+	return fileutil.ReplaceFile(file, Marshal(root), 0644)
+}
+
+// Quote will quote and escape the given string
+func Quote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`).Replace(s)
+	return `"` + s + `"`
+}
+
+// writeNode will write the given node at the given depth
+func writeNode(sb *strings.Builder, n *Node, depth int) {
+	f := n.format
+	indent := strings.Repeat("\t", depth)
+
+	// Key and the comments before it
+	if f != nil && equalStrings(n.Comments, f.comments) {
+		sb.WriteString(f.key.Leading)
+	} else {
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		for _, comment := range n.Comments {
+			sb.WriteString(indent + "// " + comment + "\n")
+		}
+		sb.WriteString(indent)
+	}
+	if f != nil && f.key.Value == n.Key {
+		sb.WriteString(f.key.Raw)
+	} else {
+		sb.WriteString(Quote(n.Key))
+	}
+
+	if !n.IsObject() {
+		if f != nil && f.value != nil {
+			sb.WriteString(f.value.Leading)
+			if f.value.Value == n.Value {
+				sb.WriteString(f.value.Raw)
+			} else {
+				sb.WriteString(Quote(n.Value))
+			}
+		} else {
+			sb.WriteString("\t\t" + Quote(n.Value))
+		}
+		writeCondition(sb, n)
+		return
+	}
+
+	// Objects keep their original braces if they were parsed as objects
+	if f == nil || !f.object {
+		if n.Condition != "" {
+			sb.WriteString(" [" + n.Condition + "]")
+		}
+		sb.WriteString("\n" + indent + "{")
+		for _, child := range n.Children {
+			writeNode(sb, child, depth+1)
+		}
+		sb.WriteString("\n" + indent + "}")
+		return
+	}
+	if f.conditionBeforeOpen {
+		writeCondition(sb, n)
+	}
+	sb.WriteString(f.open.Leading + f.open.Raw)
+	for _, child := range n.Children {
+		writeNode(sb, child, depth+1)
+	}
+	sb.WriteString(f.close.Leading + f.close.Raw)
+	if !f.conditionBeforeOpen {
+		writeCondition(sb, n)
+	}
+}
+
+// writeCondition will write the node's conditional, keeping its original
+// formatting if it did not change.
+func writeCondition(sb *strings.Builder, n *Node) {
+	var original *Token
+	if n.format != nil {
+		original = n.format.condition
+	}
+	switch {
+	case n.Condition == "":
+		return
+	case original != nil && original.Value == n.Condition:
+		sb.WriteString(original.Leading + original.Raw)
+	case original != nil:
+		sb.WriteString(original.Leading + "[" + n.Condition + "]")
+	default:
+		sb.WriteString(" [" + n.Condition + "]")
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}